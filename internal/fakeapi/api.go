@@ -0,0 +1,468 @@
+package fakeapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// apiPath is a parsed Scaleway API path such as /instance/v1/zones/fr-par-1/servers/<id>.
+type apiPath struct {
+	product      string
+	localityType string
+	locality     string
+	// segments are the path segments after the locality, alternating collections and IDs.
+	segments []string
+}
+
+func parseAPIPath(path string) (*apiPath, bool) {
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	if len(parts) < 3 {
+		return nil, false
+	}
+	p := &apiPath{
+		product:  parts[0],
+		segments: parts[2:],
+	}
+	if len(parts) >= 5 && (parts[2] == "zones" || parts[2] == "regions") {
+		p.localityType = parts[2]
+		p.locality = parts[3]
+		p.segments = parts[4:]
+	}
+	return p, len(p.segments) > 0
+}
+
+func (p *apiPath) collectionKey(collection string) string {
+	return p.product + "/" + p.locality + "/" + collection
+}
+
+// singular returns the singular form of a collection, e.g. server for servers.
+func singular(collection string) string {
+	collection = strings.ReplaceAll(collection, "-", "_")
+	switch {
+	case strings.HasSuffix(collection, "ies"):
+		return strings.TrimSuffix(collection, "ies") + "y"
+	case strings.HasSuffix(collection, "s"):
+		return strings.TrimSuffix(collection, "s")
+	}
+	return collection
+}
+
+func listKey(collection string) string {
+	return strings.ReplaceAll(collection, "-", "_")
+}
+
+// wrapsObjects returns true for APIs wrapping objects in their singular name, e.g. {"server": {...}}.
+func (p *apiPath) wrapsObjects() bool {
+	return p.product == "instance"
+}
+
+// keepsNullFields returns true for APIs setting the fields sent as null in update requests, e.g. to detach an instance IP.
+// The other APIs leave them unchanged.
+func (p *apiPath) keepsNullFields() bool {
+	return p.product == "instance"
+}
+
+// mergeFields merges the fields of an update request into an object, leaving null fields unchanged and merging nested objects.
+func mergeFields(obj, body map[string]interface{}) {
+	for key, value := range body {
+		switch typedValue := value.(type) {
+		case nil:
+			continue
+		case map[string]interface{}:
+			if nested, isObject := obj[key].(map[string]interface{}); isObject {
+				mergeFields(nested, typedValue)
+				continue
+			}
+		}
+		obj[key] = value
+	}
+}
+
+// projectKeys returns the project and organization keys used by the API.
+func (p *apiPath) projectKeys() (string, string) {
+	if p.product == "instance" {
+		return "project", "organization"
+	}
+	return "project_id", "organization_id"
+}
+
+func writeJSON(w http.ResponseWriter, statusCode int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func writeAPIError(w http.ResponseWriter, statusCode int, errorType, message string) {
+	writeJSON(w, statusCode, map[string]interface{}{
+		"type":    errorType,
+		"message": message,
+	})
+}
+
+func writeNotFound(w http.ResponseWriter, resource, id string) {
+	writeJSON(w, http.StatusNotFound, map[string]interface{}{
+		"type":        "not_found",
+		"message":     "resource is not found",
+		"resource":    resource,
+		"resource_id": id,
+	})
+}
+
+func readJSONBody(r *http.Request) (map[string]interface{}, error) {
+	body := map[string]interface{}{}
+	raw, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+	if len(raw) == 0 {
+		return body, nil
+	}
+	if err := json.Unmarshal(raw, &body); err != nil {
+		return nil, err
+	}
+	return body, nil
+}
+
+func (s *Server) serveAPI(w http.ResponseWriter, r *http.Request) {
+	p, ok := parseAPIPath(r.URL.Path)
+	if !ok {
+		writeAPIError(w, http.StatusNotFound, "not_found", "unknown path "+r.URL.Path)
+		return
+	}
+
+	s.seed(p)
+
+	if n := len(p.segments); n >= 3 {
+		collection := p.segments[n-1]
+		if n%2 == 0 {
+			collection = p.segments[n-2]
+		}
+		if handler, isRaw := rawHandlers[p.product+"/"+collection]; isRaw {
+			handler(s, w, r, p)
+			return
+		}
+	}
+
+	body := map[string]interface{}{}
+	if r.Method == http.MethodPost || r.Method == http.MethodPatch || r.Method == http.MethodPut {
+		var err error
+		body, err = readJSONBody(r)
+		if err != nil {
+			writeAPIError(w, http.StatusBadRequest, "invalid_arguments", err.Error())
+			return
+		}
+	}
+
+	segments := p.segments
+	n := len(segments)
+	for i := 0; i+1 < n; i += 2 {
+		if resolve, exists := idResolvers[p.product+"/"+segments[i]]; exists {
+			parentID := ""
+			if i > 0 {
+				parentID = segments[i-1]
+			}
+			segments[i+1] = resolve(s, p, parentID, segments[i+1])
+		}
+	}
+	last := segments[n-1]
+
+	if response, isStatic := staticResponses[p.product+"/"+strings.Join(segments, "/")]; isStatic && r.Method == http.MethodGet {
+		writeJSON(w, http.StatusOK, response(p))
+		return
+	}
+
+	if handler, isAction := actions[p.product+"/"+last]; isAction && n >= 3 {
+		collection, id := segments[n-3], segments[n-2]
+		obj, exists := s.get(p.collectionKey(collection), id)
+		if !exists {
+			writeNotFound(w, singular(collection), id)
+			return
+		}
+		handler(s, w, p, collection, obj, body)
+		return
+	}
+
+	if n%2 == 1 {
+		parentField, parentID := "", ""
+		if n >= 3 {
+			parentField, parentID = singular(segments[n-3])+"_id", segments[n-2]
+		}
+		switch r.Method {
+		case http.MethodGet:
+			s.list(w, p, last, parentField, parentID, r.URL.Query())
+		case http.MethodPost:
+			s.create(w, p, last, parentField, parentID, body)
+		case http.MethodPut, http.MethodPatch:
+			if handler, exists := collectionUpdates[p.product+"/"+last]; exists {
+				handler(s, w, p, last, parentField, parentID, body)
+				return
+			}
+			writeAPIError(w, http.StatusMethodNotAllowed, "invalid_request", "cannot update a collection")
+		default:
+			writeAPIError(w, http.StatusMethodNotAllowed, "invalid_request", "unsupported method "+r.Method)
+		}
+		return
+	}
+
+	collection, id := segments[n-2], last
+	switch r.Method {
+	case http.MethodGet:
+		obj, exists := s.get(p.collectionKey(collection), id)
+		if !exists {
+			writeNotFound(w, singular(collection), id)
+			return
+		}
+		s.writeObject(w, http.StatusOK, p, collection, obj)
+	case http.MethodPatch, http.MethodPut:
+		obj, exists := s.get(p.collectionKey(collection), id)
+		if !exists {
+			writeNotFound(w, singular(collection), id)
+			return
+		}
+		if check, exists := updateChecks[p.product+"/"+collection]; exists {
+			if message := check(obj, body); message != "" {
+				writeAPIError(w, http.StatusBadRequest, "invalid_request_error", message)
+				return
+			}
+		}
+		if p.keepsNullFields() {
+			for key, value := range body {
+				obj[key] = value
+			}
+		} else {
+			mergeFields(obj, body)
+		}
+		obj["updated_at"] = s.timestamp()
+		normalizeObject(s, p, collection, obj)
+		s.writeObject(w, http.StatusOK, p, collection, obj)
+	case http.MethodDelete:
+		obj, exists := s.get(p.collectionKey(collection), id)
+		if !exists {
+			writeNotFound(w, singular(collection), id)
+			return
+		}
+		if tags, _ := obj["tags"].([]interface{}); containsValue(tags, DeletionDeniedTag) {
+			writeAPIError(w, http.StatusConflict, "precondition_failed", "resource is protected against deletion")
+			return
+		}
+		s.delete(p, collection, id)
+		if status, returned := deletedObjectStatuses[p.product+"/"+collection]; returned {
+			obj["status"] = status
+			s.writeObject(w, http.StatusOK, p, collection, obj)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeAPIError(w, http.StatusMethodNotAllowed, "invalid_request", "unsupported method "+r.Method)
+	}
+}
+
+func (s *Server) get(key, id string) (map[string]interface{}, bool) {
+	obj, exists := s.objects[key][id]
+	return obj, exists
+}
+
+func (s *Server) store(key string, obj map[string]interface{}) {
+	id := obj["id"].(string)
+	if s.objects[key] == nil {
+		s.objects[key] = map[string]map[string]interface{}{}
+	}
+	if _, exists := s.objects[key][id]; !exists {
+		s.order[key] = append(s.order[key], id)
+	}
+	s.objects[key][id] = obj
+}
+
+// embed sets the current children of an object returned with it by the API.
+func (s *Server) embed(p *apiPath, collection string, obj map[string]interface{}) {
+	embedded, exists := embeddedCollections[p.product+"/"+collection]
+	if !exists {
+		return
+	}
+	children := []interface{}{}
+	key := p.collectionKey(embedded.collection)
+	for _, id := range s.order[key] {
+		if child := s.objects[key][id]; child[embedded.parentField] == obj["id"] {
+			children = append(children, child)
+		}
+	}
+	obj[embedded.field] = children
+}
+
+// DeletionDeniedTag is the tag of the objects whose deletions are denied, to test how deletion failures are handled.
+const DeletionDeniedTag = "fakeapi.scaleway.com/deletion-denied"
+
+// delete removes an object and the objects referencing it as their parent.
+func (s *Server) delete(p *apiPath, collection, id string) {
+	key := p.collectionKey(collection)
+	delete(s.objects[key], id)
+	for i, orderedID := range s.order[key] {
+		if orderedID == id {
+			s.order[key] = append(s.order[key][:i], s.order[key][i+1:]...)
+			break
+		}
+	}
+
+	parentField := singular(collection) + "_id"
+	prefix := p.product + "/" + p.locality + "/"
+	for childKey, children := range s.objects {
+		if !strings.HasPrefix(childKey, prefix) {
+			continue
+		}
+		if _, kept := keptOnParentDeletion[p.product+"/"+strings.TrimPrefix(childKey, prefix)]; kept {
+			continue
+		}
+		for childID, child := range children {
+			if child[parentField] == id {
+				s.delete(p, strings.TrimPrefix(childKey, prefix), childID)
+			}
+		}
+	}
+}
+
+// newObject creates an object from a creation request body, filling the fields set by the API.
+func (s *Server) newObject(p *apiPath, collection, parentField, parentID string, body map[string]interface{}) map[string]interface{} {
+	obj := map[string]interface{}{}
+	for key, value := range body {
+		obj[key] = value
+	}
+	if _, hasID := obj["id"]; !hasID {
+		obj["id"] = newUUID()
+	}
+	obj["created_at"] = s.timestamp()
+	obj["updated_at"] = s.timestamp()
+
+	switch p.localityType {
+	case "zones":
+		obj["zone"] = p.locality
+	case "regions":
+		obj["region"] = p.locality
+	}
+
+	projectKey, organizationKey := p.projectKeys()
+	if projectID, exists := obj[projectKey]; !exists || projectID == nil || projectID == "" {
+		if organizationID, hasOrganization := obj[organizationKey]; hasOrganization && organizationID != nil && organizationID != "" {
+			obj[projectKey] = organizationID
+		} else {
+			obj[projectKey] = DefaultProjectID
+		}
+	}
+	obj[organizationKey] = DefaultOrganizationID
+
+	if parentField != "" {
+		obj[parentField] = parentID
+	}
+	if _, hasTags := obj["tags"]; !hasTags {
+		obj["tags"] = []interface{}{}
+	}
+
+	normalizeObject(s, p, collection, obj)
+	return obj
+}
+
+func (s *Server) create(w http.ResponseWriter, p *apiPath, collection, parentField, parentID string, body map[string]interface{}) {
+	obj := s.newObject(p, collection, parentField, parentID, body)
+	s.store(p.collectionKey(collection), obj)
+	if hook, exists := createHooks[p.product+"/"+collection]; exists {
+		hook(s, p, obj, body)
+	}
+	s.writeObject(w, http.StatusOK, p, collection, obj)
+}
+
+// ignoredFilters are query parameters that are not used to filter objects.
+var ignoredFilters = map[string]struct{}{
+	"page":            {},
+	"per_page":        {},
+	"page_size":       {},
+	"order_by":        {},
+	"organization":    {},
+	"organization_id": {},
+}
+
+func matchFilter(obj map[string]interface{}, key string, values []string) bool {
+	field, exists := obj[key]
+	if !exists {
+		return true
+	}
+	switch typedField := field.(type) {
+	case string:
+		if key == "name" {
+			return strings.Contains(typedField, values[0])
+		}
+		return typedField == values[0]
+	case []interface{}:
+		for _, value := range strings.Split(strings.Join(values, ","), ",") {
+			found := false
+			for _, item := range typedField {
+				if fmt.Sprint(item) == value {
+					found = true
+					break
+				}
+			}
+			if !found {
+				return false
+			}
+		}
+		return true
+	case nil:
+		return false
+	default:
+		return fmt.Sprint(typedField) == values[0]
+	}
+}
+
+func (s *Server) filter(key, parentField, parentID string, query url.Values) []interface{} {
+	objects := []interface{}{}
+	for _, id := range s.order[key] {
+		obj := s.objects[key][id]
+		if parentField != "" && obj[parentField] != parentID {
+			continue
+		}
+		matches := true
+		for filterKey, values := range query {
+			// SDKs send the zero value of enums as "unknown", it does not filter anything.
+			if _, ignored := ignoredFilters[filterKey]; ignored || len(values) == 0 || values[0] == "" || values[0] == "unknown" {
+				continue
+			}
+			if !matchFilter(obj, filterKey, values) {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			objects = append(objects, obj)
+		}
+	}
+	return objects
+}
+
+func (s *Server) list(w http.ResponseWriter, p *apiPath, collection, parentField, parentID string, query url.Values) {
+	objects := s.filter(p.collectionKey(collection), parentField, parentID, query)
+	for _, obj := range objects {
+		s.embed(p, collection, obj.(map[string]interface{}))
+	}
+	if page, err := strconv.Atoi(query.Get("page")); err == nil && page > 1 {
+		objects = []interface{}{}
+	}
+	total := len(s.filter(p.collectionKey(collection), parentField, parentID, query))
+
+	w.Header().Set("X-Total-Count", strconv.Itoa(total))
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		listKey(collection): objects,
+		"total_count":       total,
+	})
+}
+
+func (s *Server) writeObject(w http.ResponseWriter, statusCode int, p *apiPath, collection string, obj map[string]interface{}) {
+	s.embed(p, collection, obj)
+	if p.wrapsObjects() {
+		writeJSON(w, statusCode, map[string]interface{}{singular(collection): obj})
+		return
+	}
+	writeJSON(w, statusCode, obj)
+}
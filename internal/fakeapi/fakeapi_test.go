@@ -0,0 +1,215 @@
+package fakeapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func doJSON(t *testing.T, client *http.Client, method, url string, body interface{}) (int, map[string]interface{}) {
+	t.Helper()
+	var reader io.Reader
+	if body != nil {
+		raw, err := json.Marshal(body)
+		require.NoError(t, err)
+		reader = bytes.NewReader(raw)
+	}
+	req, err := http.NewRequest(method, url, reader)
+	require.NoError(t, err)
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	response := map[string]interface{}{}
+	raw, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	if len(raw) > 0 && resp.Header.Get("Content-Type") == "application/json" {
+		require.NoError(t, json.Unmarshal(raw, &response))
+	}
+	return resp.StatusCode, response
+}
+
+func TestParseAPIPath(t *testing.T) {
+	p, ok := parseAPIPath("/k8s/v1/regions/fr-par/clusters/123/pools")
+	require.True(t, ok)
+	assert.Equal(t, "k8s", p.product)
+	assert.Equal(t, "regions", p.localityType)
+	assert.Equal(t, "fr-par", p.locality)
+	assert.Equal(t, []string{"clusters", "123", "pools"}, p.segments)
+
+	p, ok = parseAPIPath("/domain/v2beta1/dns-zones/example.com/records")
+	require.True(t, ok)
+	assert.Equal(t, "", p.locality)
+	assert.Equal(t, []string{"dns-zones", "example.com", "records"}, p.segments)
+
+	_, ok = parseAPIPath("/instance/v1")
+	assert.False(t, ok)
+}
+
+func TestServer_CRUD(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	client := s.Client()
+	url := "https://api.scaleway.com/vpc/v1/zones/fr-par-1/private-networks"
+
+	status, created := doJSON(t, client, http.MethodPost, url, map[string]interface{}{"name": "pn"})
+	require.Equal(t, http.StatusOK, status)
+	id := created["id"].(string)
+	assert.Equal(t, "fr-par-1", created["zone"])
+	assert.Equal(t, DefaultProjectID, created["project_id"])
+
+	status, list := doJSON(t, client, http.MethodGet, url+"?name=p", nil)
+	require.Equal(t, http.StatusOK, status)
+	assert.Equal(t, float64(1), list["total_count"])
+
+	status, updated := doJSON(t, client, http.MethodPatch, url+"/"+id, map[string]interface{}{"name": "renamed"})
+	require.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "renamed", updated["name"])
+
+	status, _ = doJSON(t, client, http.MethodDelete, url+"/"+id, nil)
+	require.Equal(t, http.StatusNoContent, status)
+
+	status, notFound := doJSON(t, client, http.MethodGet, url+"/"+id, nil)
+	require.Equal(t, http.StatusNotFound, status)
+	assert.Equal(t, "not_found", notFound["type"])
+}
+
+func TestServer_InstanceServer(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	client := s.Client()
+	url := "https://api.scaleway.com/instance/v1/zones/fr-par-1/servers"
+
+	status, created := doJSON(t, client, http.MethodPost, url, map[string]interface{}{
+		"name":            "server",
+		"commercial_type": "DEV1-S",
+		"image":           "image-id",
+		"project":         "project-id",
+	})
+	require.Equal(t, http.StatusOK, status)
+	server := created["server"].(map[string]interface{})
+	id := server["id"].(string)
+	assert.Equal(t, "stopped", server["state"])
+	assert.Equal(t, map[string]interface{}{"id": "image-id"}, server["image"])
+	assert.Equal(t, "project-id", server["project"])
+	assert.Len(t, server["volumes"], 1)
+
+	status, action := doJSON(t, client, http.MethodPost, url+"/"+id+"/action", map[string]interface{}{"action": "poweron"})
+	require.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "success", action["task"].(map[string]interface{})["status"])
+
+	_, fetched := doJSON(t, client, http.MethodGet, url+"/"+id, nil)
+	assert.Equal(t, "running", fetched["server"].(map[string]interface{})["state"])
+
+	status, _ = doJSON(t, client, http.MethodPost, url+"/"+id+"/action", map[string]interface{}{"action": "terminate"})
+	require.Equal(t, http.StatusOK, status)
+	_, volumes := doJSON(t, client, http.MethodGet, "https://api.scaleway.com/instance/v1/zones/fr-par-1/volumes", nil)
+	assert.Empty(t, volumes["volumes"])
+}
+
+func TestServer_K8SPools(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	client := s.Client()
+	url := "https://api.scaleway.com/k8s/v1/regions/fr-par"
+
+	_, cluster := doJSON(t, client, http.MethodPost, url+"/clusters", map[string]interface{}{
+		"name":    "cluster",
+		"version": "1.26.2",
+		"pools":   []interface{}{map[string]interface{}{"name": "default", "size": 2}},
+	})
+	clusterID := cluster["id"].(string)
+	assert.Equal(t, "ready", cluster["status"])
+
+	_, pools := doJSON(t, client, http.MethodGet, url+"/clusters/"+clusterID+"/pools", nil)
+	require.Len(t, pools["pools"], 1)
+	pool := pools["pools"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, "1.26.2", pool["version"])
+
+	_, nodes := doJSON(t, client, http.MethodGet, url+"/nodes?cluster_id="+clusterID, nil)
+	assert.Equal(t, float64(2), nodes["total_count"])
+
+	_, _ = doJSON(t, client, http.MethodPatch, url+"/pools/"+pool["id"].(string), map[string]interface{}{"size": 1})
+	_, nodes = doJSON(t, client, http.MethodGet, url+"/nodes?pool_id="+pool["id"].(string), nil)
+	assert.Equal(t, float64(1), nodes["total_count"])
+
+	_, versions := doJSON(t, client, http.MethodGet, url+"/versions", nil)
+	assert.NotEmpty(t, versions["versions"])
+
+	// The API returns the deleted clusters, like the deleted pools.
+	status, deleted := doJSON(t, client, http.MethodDelete, url+"/clusters/"+clusterID, nil)
+	require.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "deleting", deleted["status"])
+	_, nodes = doJSON(t, client, http.MethodGet, url+"/nodes", nil)
+	assert.Equal(t, float64(0), nodes["total_count"])
+}
+
+func TestServer_DomainRecords(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	client := s.Client()
+	url := "https://api.scaleway.com/domain/v2beta1/dns-zones/example.com/records"
+
+	_, records := doJSON(t, client, http.MethodPatch, url, map[string]interface{}{
+		"changes": []interface{}{map[string]interface{}{"add": map[string]interface{}{
+			"records": []interface{}{
+				map[string]interface{}{"name": "www", "type": "A", "data": "1.2.3.4"},
+				map[string]interface{}{"name": "mail", "type": "MX", "data": "10 mx.example.com."},
+			},
+		}}},
+	})
+	assert.Equal(t, float64(2), records["total_count"])
+
+	_, records = doJSON(t, client, http.MethodPatch, url, map[string]interface{}{
+		"changes": []interface{}{map[string]interface{}{"delete": map[string]interface{}{
+			"id_fields": map[string]interface{}{"name": "www", "type": "A"},
+		}}},
+	})
+	assert.Equal(t, float64(1), records["total_count"])
+
+	_, records = doJSON(t, client, http.MethodGet, url, nil)
+	assert.Equal(t, "mail", records["records"].([]interface{})[0].(map[string]interface{})["name"])
+}
+
+func TestServer_S3(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	client := s.Client()
+	bucketURL := "https://test-bucket.s3.fr-par.scw.cloud"
+
+	status, _ := doJSON(t, client, http.MethodGet, bucketURL+"/?tagging", nil)
+	assert.Equal(t, http.StatusNotFound, status)
+
+	status, _ = doJSON(t, client, http.MethodPut, bucketURL+"/", nil)
+	require.Equal(t, http.StatusOK, status)
+
+	status, _ = doJSON(t, client, http.MethodGet, bucketURL+"/?tagging", nil)
+	assert.Equal(t, http.StatusNotFound, status)
+
+	req, err := http.NewRequest(http.MethodPut, bucketURL+"/file.txt", bytes.NewBufferString("content"))
+	require.NoError(t, err)
+	req.Header.Set("X-Amz-Meta-Foo", "bar")
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	resp, err = client.Get("https://s3.fr-par.scw.cloud/test-bucket/file.txt")
+	require.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	require.NoError(t, err)
+	assert.Equal(t, "content", string(body))
+	assert.Equal(t, "bar", resp.Header.Get("X-Amz-Meta-Foo"))
+
+	status, _ = doJSON(t, client, http.MethodDelete, bucketURL+"/", nil)
+	assert.Equal(t, http.StatusConflict, status)
+	status, _ = doJSON(t, client, http.MethodDelete, bucketURL+"/file.txt", nil)
+	assert.Equal(t, http.StatusNoContent, status)
+	status, _ = doJSON(t, client, http.MethodDelete, bucketURL+"/", nil)
+	assert.Equal(t, http.StatusNoContent, status)
+}
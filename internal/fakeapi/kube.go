@@ -0,0 +1,131 @@
+package fakeapi
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// kubeCluster is the state of the Kubernetes API of a cluster, only holding what the provider manages through it.
+type kubeCluster struct {
+	nodes map[string]map[string]interface{}
+	pods  []map[string]interface{}
+}
+
+// isKubeHost returns true for the API server hosts of Kubernetes clusters, e.g. <cluster-id>.api.k8s.fr-par.scw.cloud:6443.
+func isKubeHost(host string) bool {
+	return strings.Contains(host, ".api.k8s.")
+}
+
+func writeKubeStatus(w http.ResponseWriter, statusCode int, reason, message string) {
+	writeJSON(w, statusCode, map[string]interface{}{
+		"kind":       "Status",
+		"apiVersion": "v1",
+		"status":     "Failure",
+		"reason":     reason,
+		"message":    message,
+		"code":       statusCode,
+	})
+}
+
+func kubeObjectName(obj map[string]interface{}) (string, string) {
+	metadata, _ := obj["metadata"].(map[string]interface{})
+	name, _ := metadata["name"].(string)
+	namespace, _ := metadata["namespace"].(string)
+	return namespace, name
+}
+
+// KubeEvictionDeniedAnnotation is the annotation of the pods whose evictions are denied, as if they were protected by a disruption budget.
+const KubeEvictionDeniedAnnotation = "fakeapi.scaleway.com/eviction-denied"
+
+func kubePodEvictionDenied(metadata map[string]interface{}) bool {
+	annotations, _ := metadata["annotations"].(map[string]interface{})
+	return annotations[KubeEvictionDeniedAnnotation] == "true"
+}
+
+// serveKube serves the core v1 endpoints used to cordon and drain nodes: nodes, pods and pod evictions.
+func (s *Server) serveKube(w http.ResponseWriter, r *http.Request, host string) {
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "Bearer ") {
+		writeKubeStatus(w, http.StatusUnauthorized, "Unauthorized", "Unauthorized")
+		return
+	}
+
+	cluster, exists := s.kubeClusters[host]
+	if !exists {
+		cluster = &kubeCluster{nodes: map[string]map[string]interface{}{}}
+		s.kubeClusters[host] = cluster
+	}
+
+	body := map[string]interface{}{}
+	if r.Body != nil {
+		content, _ := io.ReadAll(r.Body)
+		if len(content) > 0 {
+			if err := json.Unmarshal(content, &body); err != nil {
+				writeKubeStatus(w, http.StatusBadRequest, "BadRequest", err.Error())
+				return
+			}
+		}
+	}
+
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/v1"), "/"), "/")
+	switch {
+	case len(parts) == 2 && parts[0] == "nodes":
+		cluster.serveNode(w, r, parts[1], body)
+	case len(parts) == 1 && parts[0] == "pods" && r.Method == http.MethodGet:
+		nodeName := strings.TrimPrefix(r.URL.Query().Get("fieldSelector"), "spec.nodeName=")
+		items := []interface{}{}
+		for _, pod := range cluster.pods {
+			if spec, _ := pod["spec"].(map[string]interface{}); nodeName == "" || spec["nodeName"] == nodeName {
+				items = append(items, pod)
+			}
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"kind": "PodList", "apiVersion": "v1", "items": items})
+	case len(parts) == 3 && parts[0] == "namespaces" && parts[2] == "pods" && r.Method == http.MethodPost:
+		metadata, _ := body["metadata"].(map[string]interface{})
+		if metadata == nil {
+			writeKubeStatus(w, http.StatusBadRequest, "BadRequest", "metadata is required")
+			return
+		}
+		metadata["namespace"] = parts[1]
+		cluster.pods = append(cluster.pods, body)
+		writeJSON(w, http.StatusCreated, body)
+	case len(parts) == 5 && parts[0] == "namespaces" && parts[2] == "pods" && parts[4] == "eviction" && r.Method == http.MethodPost:
+		for i, pod := range cluster.pods {
+			if namespace, name := kubeObjectName(pod); namespace == parts[1] && name == parts[3] {
+				if metadata, _ := pod["metadata"].(map[string]interface{}); kubePodEvictionDenied(metadata) {
+					writeKubeStatus(w, http.StatusTooManyRequests, "TooManyRequests", "Cannot evict pod as it would violate the pod's disruption budget.")
+					return
+				}
+				cluster.pods = append(cluster.pods[:i], cluster.pods[i+1:]...)
+				writeJSON(w, http.StatusCreated, map[string]interface{}{"kind": "Status", "apiVersion": "v1", "status": "Success"})
+				return
+			}
+		}
+		writeKubeStatus(w, http.StatusNotFound, "NotFound", "pods \""+parts[3]+"\" not found")
+	default:
+		writeKubeStatus(w, http.StatusNotFound, "NotFound", "the server could not find the requested resource")
+	}
+}
+
+func (c *kubeCluster) serveNode(w http.ResponseWriter, r *http.Request, name string, body map[string]interface{}) {
+	node, exists := c.nodes[name]
+	if !exists {
+		node = map[string]interface{}{
+			"kind":       "Node",
+			"apiVersion": "v1",
+			"metadata":   map[string]interface{}{"name": name},
+			"spec":       map[string]interface{}{},
+		}
+		c.nodes[name] = node
+	}
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, node)
+	case http.MethodPatch:
+		mergeFields(node, body)
+		writeJSON(w, http.StatusOK, node)
+	default:
+		writeKubeStatus(w, http.StatusMethodNotAllowed, "MethodNotAllowed", "unsupported method "+r.Method)
+	}
+}
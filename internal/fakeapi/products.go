@@ -0,0 +1,845 @@
+package fakeapi
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// actionHandler handles a POST on an object sub path, e.g. /instance/v1/zones/fr-par-1/servers/<id>/action.
+type actionHandler func(s *Server, w http.ResponseWriter, p *apiPath, collection string, obj, body map[string]interface{})
+
+// collectionUpdateHandler handles a PUT or PATCH on a whole collection, e.g. to set the rules of a security group.
+type collectionUpdateHandler func(s *Server, w http.ResponseWriter, p *apiPath, collection, parentField, parentID string, body map[string]interface{})
+
+// createHook is called once an object is stored, to create the objects implied by the creation request.
+type createHook func(s *Server, p *apiPath, obj, body map[string]interface{})
+
+// actions are keyed by product and action name.
+var actions = map[string]actionHandler{
+	"instance/action": instanceServerAction,
+	"k8s/upgrade":     k8sUpgrade,
+	"k8s/kubeconfig":  k8sKubeconfig,
+	"k8s/reboot":      returnObject,
+	"k8s/replace":     returnObject,
+}
+
+// collectionUpdates are keyed by product and collection name.
+var collectionUpdates = map[string]collectionUpdateHandler{
+	"instance/rules": setCollection,
+	"domain/records": domainUpdateRecords,
+}
+
+// createHooks are keyed by product and collection name.
+var createHooks = map[string]createHook{
+	"iam/api-keys": iamCreateAPIKey,
+	"k8s/clusters": k8sCreateClusterPools,
+}
+
+// updateCheck rejects an update request the real API would reject, returning the error message.
+type updateCheck func(obj, body map[string]interface{}) string
+
+// updateChecks are keyed by product and collection name.
+var updateChecks = map[string]updateCheck{
+	"instance/servers": instanceCheckServerUpdate,
+}
+
+// staticResponses are read-only documents returned on GET, keyed by product and path after the locality.
+var staticResponses = map[string]func(p *apiPath) interface{}{
+	"instance/products/servers": instanceServerTypes,
+}
+
+// idResolver returns the ID of the object a path refers to, for APIs using other identifiers in their paths.
+type idResolver func(s *Server, p *apiPath, parentID, id string) string
+
+// idResolvers are keyed by product and collection name.
+var idResolvers = map[string]idResolver{
+	"iam/api-keys": iamAPIKeyID,
+}
+
+// keptOnParentDeletion are collections whose objects reference a parent without being deleted with it, keyed by product and collection name.
+var keptOnParentDeletion = map[string]struct{}{
+	"instance/snapshots": {},
+}
+
+// deletedObjectStatuses are the statuses of the objects returned by deletion requests, keyed by product and collection name.
+// The objects of the other collections are deleted without response body.
+var deletedObjectStatuses = map[string]string{
+	"k8s/clusters": "deleting",
+	"k8s/pools":    "deleting",
+}
+
+// embeddedCollection is a child collection the API returns inside its parent objects.
+type embeddedCollection struct {
+	field       string
+	collection  string
+	parentField string
+}
+
+// embeddedCollections are keyed by product and parent collection name.
+var embeddedCollections = map[string]embeddedCollection{
+	"instance/servers": {field: "private_nics", collection: "private_nics", parentField: "server_id"},
+}
+
+// rawHandler serves a collection whose requests or responses are not JSON objects.
+type rawHandler func(s *Server, w http.ResponseWriter, r *http.Request, p *apiPath)
+
+// rawHandlers are keyed by product and collection name.
+var rawHandlers = map[string]rawHandler{
+	"instance/user_data":        instanceServerUserData,
+	"instance/compatible-types": instanceServerCompatibleTypes,
+	"lb/servers":                lbBackendServers,
+}
+
+// seeds are read-only collections available in every locality, keyed by product and collection name.
+var seeds = map[string]func(p *apiPath) []map[string]interface{}{
+	"k8s/versions": k8sVersions,
+}
+
+func (s *Server) seed(p *apiPath) {
+	for name, seed := range seeds {
+		product, collection, _ := strings.Cut(name, "/")
+		if product != p.product {
+			continue
+		}
+		key := p.collectionKey(collection)
+		if _, seeded := s.objects[key]; seeded {
+			continue
+		}
+		for _, obj := range seed(p) {
+			s.store(key, obj)
+		}
+	}
+}
+
+func returnObject(s *Server, w http.ResponseWriter, p *apiPath, collection string, obj, _ map[string]interface{}) {
+	s.writeObject(w, http.StatusOK, p, collection, obj)
+}
+
+// setCollection replaces every object of a collection by the ones in the request body.
+func setCollection(s *Server, w http.ResponseWriter, p *apiPath, collection, parentField, parentID string, body map[string]interface{}) {
+	key := p.collectionKey(collection)
+	for _, obj := range s.filter(key, parentField, parentID, nil) {
+		s.delete(p, collection, obj.(map[string]interface{})["id"].(string))
+	}
+
+	rawObjects, _ := body[listKey(collection)].([]interface{})
+	for _, rawObj := range rawObjects {
+		obj, _ := rawObj.(map[string]interface{})
+		s.store(key, s.newObject(p, collection, parentField, parentID, obj))
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		listKey(collection): s.filter(key, parentField, parentID, nil),
+	})
+}
+
+// normalizeObject converts request fields to their response form and fills the fields computed by the API.
+func normalizeObject(s *Server, p *apiPath, collection string, obj map[string]interface{}) {
+	switch p.product + "/" + collection {
+	case "instance/servers":
+		normalizeInstanceServer(s, p, obj)
+	case "instance/ips":
+		setDefault(obj, "type", "nat")
+		if obj["type"] == "routed_ipv6" {
+			setDefault(obj, "prefix", randomIPv6Prefix())
+			setDefault(obj, "address", nil)
+		}
+		setDefault(obj, "address", randomIPv4())
+		setDefault(obj, "reverse", nil)
+		toReference(obj, "server")
+		instanceSyncServerIPs(s, p)
+	case "instance/volumes":
+		setDefault(obj, "state", "available")
+		setDefault(obj, "server", nil)
+		setDefault(obj, "volume_type", "l_ssd")
+	case "instance/private_nics":
+		setDefault(obj, "state", "available")
+		setDefault(obj, "mac_address", randomMACAddress())
+		setDefault(obj, "tags", []interface{}{})
+	case "instance/snapshots":
+		setDefault(obj, "state", "available")
+	case "instance/images":
+		setDefault(obj, "state", "available")
+		setDefault(obj, "creation_date", obj["created_at"])
+		setDefault(obj, "extra_volumes", map[string]interface{}{})
+		toReference(obj, "root_volume")
+	case "instance/security_groups":
+		setDefault(obj, "state", "available")
+		setDefault(obj, "stateful", true)
+		setDefault(obj, "inbound_default_policy", "accept")
+		setDefault(obj, "outbound_default_policy", "accept")
+		setDefault(obj, "servers", []interface{}{})
+	case "instance/rules":
+		setDefault(obj, "editable", true)
+		setDefault(obj, "position", len(s.filter(p.collectionKey("rules"), "security_group_id", obj["security_group_id"].(string), nil))+1)
+	case "instance/placement_groups":
+		setDefault(obj, "policy_mode", "optional")
+		setDefault(obj, "policy_type", "max_availability")
+		setDefault(obj, "policy_respected", true)
+	case "vpc/private-networks":
+		setDefault(obj, "subnets", []interface{}{})
+	case "lb/ips":
+		setDefault(obj, "ip_address", randomIPv4())
+		setDefault(obj, "lb_id", nil)
+		setDefault(obj, "reverse", "")
+	case "lb/lbs":
+		normalizeLB(s, p, obj)
+	case "lb/backends":
+		if serverIPs, exists := obj["server_ip"]; exists {
+			obj["pool"] = serverIPs
+			delete(obj, "server_ip")
+		}
+		setDefault(obj, "pool", []interface{}{})
+	case "rdb/instances":
+		setDefault(obj, "status", "ready")
+		setDefault(obj, "endpoints", []interface{}{})
+		setDefault(obj, "settings", []interface{}{})
+		setDefault(obj, "init_settings", []interface{}{})
+		setDefault(obj, "volume", map[string]interface{}{
+			"type": obj["volume_type"],
+			"size": obj["volume_size"],
+		})
+		setDefault(obj, "backup_schedule", map[string]interface{}{
+			"frequency": 24,
+			"retention": 7,
+			"disabled":  obj["disable_backup"] == true,
+		})
+	case "k8s/clusters":
+		normalizeK8SCluster(obj)
+	case "k8s/pools":
+		normalizeK8SPool(s, p, obj)
+	}
+}
+
+func setDefault(obj map[string]interface{}, key string, value interface{}) {
+	if _, exists := obj[key]; !exists {
+		obj[key] = value
+	}
+}
+
+// toReference converts an ID sent in a request to the object returned by the API, e.g. "image": {"id": "..."}.
+func toReference(obj map[string]interface{}, key string) {
+	if id, isString := obj[key].(string); isString {
+		if id == "" {
+			obj[key] = nil
+			return
+		}
+		obj[key] = map[string]interface{}{"id": id}
+	}
+}
+
+func randomIPv4() string {
+	//nolint:gosec
+	return fmt.Sprintf("51.15.%d.%d", rand.Intn(256), 1+rand.Intn(254))
+}
+
+func randomPrivateIPv4() string {
+	//nolint:gosec
+	return fmt.Sprintf("10.%d.%d.%d", rand.Intn(256), rand.Intn(256), 1+rand.Intn(254))
+}
+
+func randomIPv6Prefix() string {
+	//nolint:gosec
+	return fmt.Sprintf("2001:bc8:%x:%x::/64", rand.Intn(65536), rand.Intn(65536))
+}
+
+func randomMACAddress() string {
+	//nolint:gosec
+	return fmt.Sprintf("02:00:00:%02x:%02x:%02x", rand.Intn(256), rand.Intn(256), rand.Intn(256))
+}
+
+func normalizeInstanceServer(s *Server, p *apiPath, obj map[string]interface{}) {
+	setDefault(obj, "state", "stopped")
+	setDefault(obj, "arch", "x86_64")
+	setDefault(obj, "boot_type", "local")
+	setDefault(obj, "dynamic_ip_required", false)
+	setDefault(obj, "enable_ipv6", false)
+	setDefault(obj, "private_nics", []interface{}{})
+	setDefault(obj, "maintenances", []interface{}{})
+	setDefault(obj, "allowed_actions", []interface{}{"poweron", "backup"})
+	for _, key := range []string{"image", "security_group", "placement_group", "public_ip", "bootscript"} {
+		toReference(obj, key)
+	}
+	setDefault(obj, "security_group", map[string]interface{}{"id": newUUID(), "name": "Default security group"})
+	setDefault(obj, "creation_date", obj["created_at"])
+	if obj["dynamic_ip_required"] == true && obj["public_ip"] == nil {
+		obj["public_ip"] = map[string]interface{}{"id": newUUID(), "address": randomIPv4(), "dynamic": true}
+	}
+	if publicIP, _ := obj["public_ip"].(map[string]interface{}); publicIP != nil && publicIP["dynamic"] != true {
+		if ipID, isString := publicIP["id"].(string); isString {
+			if ip, exists := s.get(p.collectionKey("ips"), ipID); exists {
+				ip["server"] = map[string]interface{}{"id": obj["id"], "name": obj["name"]}
+			}
+		}
+	}
+	instanceSetServerIPs(s, p, obj)
+	if obj["bootscript"] == nil {
+		obj["bootscript"] = map[string]interface{}{"id": newUUID(), "title": "default", "arch": obj["arch"], "public": true}
+	}
+
+	volumes, _ := obj["volumes"].(map[string]interface{})
+	if len(volumes) == 0 {
+		volumes = map[string]interface{}{"0": map[string]interface{}{"size": 20000000000, "volume_type": "l_ssd"}}
+	}
+	volumesKey := p.collectionKey("volumes")
+	for index, rawVolume := range volumes {
+		volume, _ := rawVolume.(map[string]interface{})
+		if volume == nil {
+			continue
+		}
+		if id, hasID := volume["id"].(string); hasID {
+			if stored, exists := s.get(volumesKey, id); exists {
+				volume = stored
+			}
+		} else {
+			volume = s.newObject(p, "volumes", "", "", volume)
+			setDefault(volume, "name", fmt.Sprintf("%s-volume-%s", obj["name"], index))
+			s.store(volumesKey, volume)
+		}
+		volume["server"] = map[string]interface{}{"id": obj["id"], "name": obj["name"]}
+		volumes[index] = volume
+	}
+	obj["volumes"] = volumes
+}
+
+// instanceSetServerIPs sets the public IPs of a server from its dynamic IP and the IPs attached to it.
+// Its public_ip is the first IPv4 of the list.
+func instanceSetServerIPs(s *Server, p *apiPath, server map[string]interface{}) {
+	publicIPs := []interface{}{}
+	if publicIP, _ := server["public_ip"].(map[string]interface{}); publicIP != nil && publicIP["dynamic"] == true {
+		publicIP["family"] = "inet"
+		publicIPs = append(publicIPs, publicIP)
+	}
+	ipsKey := p.collectionKey("ips")
+	for _, id := range s.order[ipsKey] {
+		ip := s.objects[ipsKey][id]
+		if reference, _ := ip["server"].(map[string]interface{}); reference == nil || reference["id"] != server["id"] {
+			continue
+		}
+		publicIP := map[string]interface{}{"id": id, "address": ip["address"], "family": "inet", "dynamic": false}
+		if prefix, isString := ip["prefix"].(string); isString {
+			address, _, _ := strings.Cut(prefix, "/")
+			publicIP["address"], publicIP["family"] = address, "inet6"
+		}
+		publicIPs = append(publicIPs, publicIP)
+	}
+
+	server["public_ips"] = publicIPs
+	server["public_ip"] = nil
+	for _, rawPublicIP := range publicIPs {
+		if publicIP := rawPublicIP.(map[string]interface{}); publicIP["family"] == "inet" {
+			server["public_ip"] = publicIP
+			break
+		}
+	}
+}
+
+// instanceSyncServerIPs sets the public IPs of every server, once an IP is attached or detached.
+func instanceSyncServerIPs(s *Server, p *apiPath) {
+	for _, server := range s.objects[p.collectionKey("servers")] {
+		instanceSetServerIPs(s, p, server)
+	}
+}
+
+// instanceCheckServerUpdate rejects commercial type changes of servers which are not stopped.
+func instanceCheckServerUpdate(obj, body map[string]interface{}) string {
+	if commercialType, exists := body["commercial_type"]; exists && commercialType != obj["commercial_type"] && obj["state"] != "stopped" {
+		return "server must be stopped to change its commercial type"
+	}
+	return ""
+}
+
+// instanceServerUserData serves the user data of a server, which are plain text values listed by key.
+func instanceServerUserData(s *Server, w http.ResponseWriter, r *http.Request, p *apiPath) {
+	serverID := p.segments[1]
+	if _, exists := s.get(p.collectionKey("servers"), serverID); !exists {
+		writeNotFound(w, "instance_server", serverID)
+		return
+	}
+	key := p.collectionKey("user_data")
+
+	if len(p.segments) == 3 {
+		keys := []interface{}{}
+		for _, obj := range s.filter(key, "server_id", serverID, nil) {
+			keys = append(keys, obj.(map[string]interface{})["key"])
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"user_data": keys})
+		return
+	}
+
+	userDataKey := p.segments[3]
+	id := serverID + "/" + userDataKey
+	switch r.Method {
+	case http.MethodGet:
+		obj, exists := s.get(key, id)
+		if !exists {
+			writeNotFound(w, "instance_user_data", userDataKey)
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(obj["value"].(string)))
+	case http.MethodPatch, http.MethodPut:
+		value, err := io.ReadAll(r.Body)
+		if err != nil {
+			writeAPIError(w, http.StatusBadRequest, "invalid_arguments", err.Error())
+			return
+		}
+		s.store(key, map[string]interface{}{"id": id, "server_id": serverID, "key": userDataKey, "value": string(value)})
+		w.WriteHeader(http.StatusNoContent)
+	case http.MethodDelete:
+		s.delete(p, "user_data", id)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeAPIError(w, http.StatusMethodNotAllowed, "invalid_request", "unsupported method "+r.Method)
+	}
+}
+
+// instanceServerCompatibleTypes lists the types a server can be changed to in place.
+// Only the types of the same architecture and range as the server are listed, e.g. DEV1-M for a DEV1-S server,
+// so that tests cover type changes rejected by the API only.
+func instanceServerCompatibleTypes(s *Server, w http.ResponseWriter, _ *http.Request, p *apiPath) {
+	serverID := p.segments[1]
+	server, exists := s.get(p.collectionKey("servers"), serverID)
+	if !exists {
+		writeNotFound(w, "instance_server", serverID)
+		return
+	}
+	commercialType := fmt.Sprint(server["commercial_type"])
+	serverRange := strings.SplitN(commercialType, "-", 2)[0]
+
+	compatibleTypes := []string{}
+	serverTypes := instanceServerTypes(p).(map[string]interface{})["servers"].(map[string]interface{})
+	for name, rawServerType := range serverTypes {
+		serverType := rawServerType.(map[string]interface{})
+		if name != commercialType && serverType["arch"] == server["arch"] && strings.SplitN(name, "-", 2)[0] == serverRange {
+			compatibleTypes = append(compatibleTypes, name)
+		}
+	}
+	sort.Strings(compatibleTypes)
+	writeJSON(w, http.StatusOK, map[string]interface{}{"compatible_types": compatibleTypes})
+}
+
+// instanceServerTypes returns a few server types, with the local volume constraints of the real ones.
+func instanceServerTypes(_ *apiPath) interface{} {
+	serverType := func(arch string, ncpus int, maxLocalSize int64) map[string]interface{} {
+		return map[string]interface{}{
+			"arch":               arch,
+			"ncpus":              ncpus,
+			"ram":                ncpus * 2 * 1024 * 1024 * 1024,
+			"hourly_price":       0.01 * float64(ncpus),
+			"alt_names":          []interface{}{},
+			"baremetal":          false,
+			"volumes_constraint": map[string]interface{}{"min_size": 0, "max_size": maxLocalSize},
+			"per_volume_constraint": map[string]interface{}{
+				"l_ssd": map[string]interface{}{"min_size": 1000000000, "max_size": maxLocalSize},
+			},
+		}
+	}
+	return map[string]interface{}{
+		"servers": map[string]interface{}{
+			"DEV1-S":  serverType("x86_64", 2, 20000000000),
+			"DEV1-M":  serverType("x86_64", 3, 40000000000),
+			"DEV1-L":  serverType("x86_64", 4, 80000000000),
+			"GP1-XS":  serverType("x86_64", 4, 150000000000),
+			"AMP2-C1": serverType("arm64", 1, 0),
+		},
+	}
+}
+
+// instanceServerAction applies power actions to a server and returns the matching task.
+func instanceServerAction(s *Server, w http.ResponseWriter, p *apiPath, collection string, obj, body map[string]interface{}) {
+	action, _ := body["action"].(string)
+	switch action {
+	case "poweron", "reboot":
+		obj["state"] = "running"
+	case "poweroff":
+		obj["state"] = "stopped"
+	case "stop_in_place":
+		obj["state"] = "stopped in place"
+	case "terminate":
+		volumes, _ := obj["volumes"].(map[string]interface{})
+		for _, rawVolume := range volumes {
+			if volume, isMap := rawVolume.(map[string]interface{}); isMap && volume["volume_type"] == "l_ssd" {
+				s.delete(p, "volumes", volume["id"].(string))
+			}
+		}
+		s.delete(p, collection, obj["id"].(string))
+	case "backup":
+	default:
+		writeAPIError(w, http.StatusBadRequest, "invalid_arguments", "unknown action "+action)
+		return
+	}
+	obj["updated_at"] = s.timestamp()
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"task": map[string]interface{}{
+			"id":            newUUID(),
+			"description":   "server_" + action,
+			"status":        "success",
+			"href_from":     fmt.Sprintf("/servers/%s/action", obj["id"]),
+			"started_at":    s.timestamp(),
+			"terminated_at": s.timestamp(),
+			"progress":      100,
+			"zone":          p.locality,
+		},
+	})
+}
+
+func normalizeLB(s *Server, p *apiPath, obj map[string]interface{}) {
+	setDefault(obj, "status", "ready")
+	setDefault(obj, "backend_count", 0)
+	setDefault(obj, "frontend_count", 0)
+	setDefault(obj, "private_network_count", 0)
+	setDefault(obj, "subscriber", nil)
+	if _, hasIP := obj["ip"]; !hasIP {
+		ipsKey := p.collectionKey("ips")
+		ip, exists := map[string]interface{}(nil), false
+		if ipID, isString := obj["ip_id"].(string); isString {
+			ip, exists = s.get(ipsKey, ipID)
+		}
+		if !exists {
+			ip = s.newObject(p, "ips", "", "", map[string]interface{}{"project_id": obj["project_id"]})
+			s.store(ipsKey, ip)
+		}
+		ip["lb_id"] = obj["id"]
+		obj["ip"] = []interface{}{ip}
+	}
+	setDefault(obj, "instances", []interface{}{map[string]interface{}{
+		"id":         newUUID(),
+		"status":     "ready",
+		"ip_address": randomIPv4(),
+		"zone":       p.locality,
+	}})
+}
+
+// lbBackendServers adds, removes or sets the server IPs of a backend, which are returned in its pool.
+func lbBackendServers(s *Server, w http.ResponseWriter, r *http.Request, p *apiPath) {
+	backend, exists := s.get(p.collectionKey("backends"), p.segments[1])
+	if !exists {
+		writeNotFound(w, "backend", p.segments[1])
+		return
+	}
+	body, err := readJSONBody(r)
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, "invalid_arguments", err.Error())
+		return
+	}
+	serverIPs, _ := body["server_ip"].([]interface{})
+	pool, _ := backend["pool"].([]interface{})
+
+	switch r.Method {
+	case http.MethodPost:
+		for _, ip := range serverIPs {
+			if !containsValue(pool, ip) {
+				pool = append(pool, ip)
+			}
+		}
+	case http.MethodDelete:
+		remaining := []interface{}{}
+		for _, ip := range pool {
+			if !containsValue(serverIPs, ip) {
+				remaining = append(remaining, ip)
+			}
+		}
+		pool = remaining
+	case http.MethodPut:
+		pool = serverIPs
+	default:
+		writeAPIError(w, http.StatusMethodNotAllowed, "invalid_request", "unsupported method "+r.Method)
+		return
+	}
+	backend["pool"] = pool
+	backend["updated_at"] = s.timestamp()
+	s.writeObject(w, http.StatusOK, p, "backends", backend)
+}
+
+func containsValue(values []interface{}, value interface{}) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func normalizeK8SCluster(obj map[string]interface{}) {
+	delete(obj, "pools")
+	// The API applies its defaults to the configurations left null in the creation request.
+	for _, key := range []string{"autoscaler_config", "auto_upgrade", "open_id_connect_config"} {
+		if obj[key] == nil {
+			delete(obj, key)
+		}
+	}
+	setDefault(obj, "status", "ready")
+	setDefault(obj, "cluster_url", fmt.Sprintf("https://%s.api.k8s.%s.scw.cloud:6443", obj["id"], obj["region"]))
+	setDefault(obj, "dns_wildcard", fmt.Sprintf("*.%s.nodes.k8s.%s.scw.cloud", obj["id"], obj["region"]))
+	setDefault(obj, "upgrade_available", false)
+	setDefault(obj, "feature_gates", []interface{}{})
+	setDefault(obj, "admission_plugins", []interface{}{})
+	setDefault(obj, "apiserver_cert_sans", []interface{}{})
+	setDefault(obj, "autoscaler_config", map[string]interface{}{
+		"scale_down_disabled":              false,
+		"scale_down_delay_after_add":       "10m",
+		"estimator":                        "binpacking",
+		"expander":                         "random",
+		"ignore_daemonsets_utilization":    false,
+		"balance_similar_node_groups":      false,
+		"expendable_pods_priority_cutoff":  -10,
+		"scale_down_unneeded_time":         "10m",
+		"scale_down_utilization_threshold": 0.5,
+		"max_graceful_termination_sec":     600,
+	})
+	setDefault(obj, "open_id_connect_config", map[string]interface{}{
+		"issuer_url":      "",
+		"client_id":       "",
+		"username_claim":  "",
+		"username_prefix": "",
+		"groups_claim":    []interface{}{},
+		"groups_prefix":   "",
+		"required_claim":  []interface{}{},
+	})
+	setDefault(obj, "auto_upgrade", map[string]interface{}{
+		"enabled": false,
+		"maintenance_window": map[string]interface{}{
+			"start_hour": 0,
+			"day":        "any",
+		},
+	})
+}
+
+// k8sCreateClusterPools creates the pools sent with a cluster creation request.
+func k8sCreateClusterPools(s *Server, p *apiPath, obj, body map[string]interface{}) {
+	pools, _ := body["pools"].([]interface{})
+	for _, rawPool := range pools {
+		pool, _ := rawPool.(map[string]interface{})
+		s.store(p.collectionKey("pools"), s.newObject(p, "pools", "cluster_id", obj["id"].(string), pool))
+	}
+}
+
+func normalizeK8SPool(s *Server, p *apiPath, obj map[string]interface{}) {
+	setDefault(obj, "status", "ready")
+	setDefault(obj, "container_runtime", "containerd")
+	setDefault(obj, "autohealing", false)
+	setDefault(obj, "autoscaling", false)
+	setDefault(obj, "kubelet_args", map[string]interface{}{})
+	setDefault(obj, "upgrade_policy", map[string]interface{}{"max_unavailable": 1, "max_surge": 0})
+	// Pools sent with a cluster creation request have an empty zone.
+	if zone, _ := obj["zone"].(string); zone == "" {
+		obj["zone"] = fmt.Sprintf("%s-1", obj["region"])
+	}
+	setDefault(obj, "root_volume_type", "default_volume_type")
+	if clusterID, isString := obj["cluster_id"].(string); isString {
+		if cluster, exists := s.get(p.collectionKey("clusters"), clusterID); exists {
+			setDefault(obj, "version", cluster["version"])
+		}
+	}
+	s.syncK8SNodes(p, obj)
+}
+
+// syncK8SNodes creates or deletes the nodes of a pool to match its size.
+func (s *Server) syncK8SNodes(p *apiPath, pool map[string]interface{}) {
+	size := 0
+	switch typedSize := pool["size"].(type) {
+	case float64:
+		size = int(typedSize)
+	case int:
+		size = typedSize
+	}
+
+	// Nodes are instance servers of the zone of their pool, referenced by their provider ID.
+	instancePath := &apiPath{product: "instance", localityType: "zones", locality: fmt.Sprint(pool["zone"])}
+	key := p.collectionKey("nodes")
+	nodes := s.filter(key, "pool_id", pool["id"].(string), nil)
+	for i := len(nodes); i < size; i++ {
+		name := fmt.Sprintf("scw-%s-%s-%d", pool["cluster_id"], pool["name"], i)
+		server := s.newObject(instancePath, "servers", "", "", map[string]interface{}{
+			"name":            name,
+			"commercial_type": pool["node_type"],
+			"state":           "running",
+			"private_ip":      randomPrivateIPv4(),
+		})
+		s.store(instancePath.collectionKey("servers"), server)
+		node := s.newObject(p, "nodes", "pool_id", pool["id"].(string), map[string]interface{}{
+			"name":          name,
+			"cluster_id":    pool["cluster_id"],
+			"status":        "ready",
+			"public_ip_v4":  randomIPv4(),
+			"provider_id":   fmt.Sprintf("scaleway://instance/%s/%s", pool["zone"], server["id"]),
+			"conditions":    map[string]interface{}{},
+			"error_message": nil,
+		})
+		s.store(key, node)
+	}
+	for i := size; i < len(nodes); i++ {
+		node := nodes[i].(map[string]interface{})
+		providerID := node["provider_id"].(string)
+		s.delete(p, "nodes", node["id"].(string))
+		s.delete(instancePath, "servers", providerID[strings.LastIndex(providerID, "/")+1:])
+	}
+}
+
+// k8sUpgrade upgrades a cluster or a pool, and the pools of a cluster when asked to.
+func k8sUpgrade(s *Server, w http.ResponseWriter, p *apiPath, collection string, obj, body map[string]interface{}) {
+	version := fmt.Sprint(body["version"])
+	switch collection {
+	case "clusters":
+		if k8sMinorVersion(version) > k8sMinorVersion(fmt.Sprint(obj["version"]))+1 {
+			writeAPIError(w, http.StatusBadRequest, "invalid_request_error", "cluster can only be upgraded to the next minor version")
+			return
+		}
+	case "pools":
+		if cluster, exists := s.get(p.collectionKey("clusters"), fmt.Sprint(obj["cluster_id"])); exists && cluster["version"] != version {
+			writeAPIError(w, http.StatusBadRequest, "invalid_request_error", "pool can only be upgraded to the version of its cluster")
+			return
+		}
+	}
+	obj["version"] = body["version"]
+	obj["updated_at"] = s.timestamp()
+	if collection == "clusters" && body["upgrade_pools"] == true {
+		for _, rawPool := range s.filter(p.collectionKey("pools"), "cluster_id", obj["id"].(string), nil) {
+			rawPool.(map[string]interface{})["version"] = body["version"]
+		}
+	}
+	s.writeObject(w, http.StatusOK, p, collection, obj)
+}
+
+// k8sMinorVersion returns the minor of a x.y.z version.
+func k8sMinorVersion(version string) int {
+	parts := strings.Split(version, ".")
+	if len(parts) < 2 {
+		return 0
+	}
+	minor, _ := strconv.Atoi(parts[1])
+	return minor
+}
+
+// k8sKubeconfig returns a kubeconfig file for a cluster.
+func k8sKubeconfig(s *Server, w http.ResponseWriter, _ *apiPath, _ string, obj, _ map[string]interface{}) {
+	name := fmt.Sprint(obj["name"])
+	kubeconfig := fmt.Sprintf(`apiVersion: v1
+clusters:
+- name: %[1]s
+  cluster:
+    certificate-authority-data: %[3]s
+    server: %[2]s
+contexts:
+- name: admin@%[1]s
+  context:
+    cluster: %[1]s
+    user: %[1]s-admin
+current-context: admin@%[1]s
+kind: Config
+preferences: {}
+users:
+- name: %[1]s-admin
+  user:
+    token: %[4]s
+`, name, obj["cluster_url"], base64.StdEncoding.EncodeToString([]byte("fake-certificate-authority")), newUUID())
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"name":         "kubeconfig.yaml",
+		"content_type": "application/octet-stream",
+		"content":      base64.StdEncoding.EncodeToString([]byte(kubeconfig)),
+	})
+}
+
+func k8sVersions(p *apiPath) []map[string]interface{} {
+	versions := []map[string]interface{}(nil)
+	for _, version := range []string{"1.26.2", "1.25.7", "1.24.11"} {
+		versions = append(versions, map[string]interface{}{
+			"id":                           version,
+			"name":                         version,
+			"label":                        "Kubernetes " + version,
+			"region":                       p.locality,
+			"available_cnis":               []interface{}{"cilium", "calico", "kilo", "none"},
+			"available_ingresses":          []interface{}{"none"},
+			"available_container_runtimes": []interface{}{"containerd"},
+			"available_feature_gates":      []interface{}{},
+			"available_admission_plugins":  []interface{}{},
+			"available_kubelet_args":       map[string]interface{}{},
+		})
+	}
+	return versions
+}
+
+// domainUpdateRecords applies the changes of a DNS zone records update.
+func domainUpdateRecords(s *Server, w http.ResponseWriter, p *apiPath, collection, parentField, parentID string, body map[string]interface{}) {
+	key := p.collectionKey(collection)
+	matches := func(record map[string]interface{}, change map[string]interface{}) bool {
+		if id, hasID := change["id"].(string); hasID && id != "" {
+			return record["id"] == id
+		}
+		idFields, _ := change["id_fields"].(map[string]interface{})
+		for field, value := range idFields {
+			if value != nil && record[field] != value {
+				return false
+			}
+		}
+		return idFields != nil
+	}
+	deleteMatching := func(change map[string]interface{}) {
+		for _, rawRecord := range s.filter(key, parentField, parentID, nil) {
+			record := rawRecord.(map[string]interface{})
+			if change == nil || matches(record, change) {
+				s.delete(p, collection, record["id"].(string))
+			}
+		}
+	}
+	addRecords := func(change map[string]interface{}) {
+		records, _ := change["records"].([]interface{})
+		for _, rawRecord := range records {
+			record, _ := rawRecord.(map[string]interface{})
+			s.store(key, s.newObject(p, collection, parentField, parentID, record))
+		}
+	}
+
+	changes, _ := body["changes"].([]interface{})
+	for _, rawChange := range changes {
+		change, _ := rawChange.(map[string]interface{})
+		for changeType, rawChangeBody := range change {
+			changeBody, _ := rawChangeBody.(map[string]interface{})
+			switch changeType {
+			case "add":
+				addRecords(changeBody)
+			case "set":
+				deleteMatching(changeBody)
+				addRecords(changeBody)
+			case "delete":
+				deleteMatching(changeBody)
+			case "clear":
+				deleteMatching(nil)
+			}
+		}
+	}
+
+	records := s.filter(key, parentField, parentID, nil)
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"records":     records,
+		"total_count": len(records),
+	})
+}
+
+// iamCreateAPIKey generates the access and secret keys of an API key.
+func iamCreateAPIKey(_ *Server, _ *apiPath, obj, _ map[string]interface{}) {
+	obj["access_key"] = "SCW" + strings.ToUpper(strings.ReplaceAll(newUUID(), "-", "")[:17])
+	obj["secret_key"] = newUUID()
+	obj["editable"] = true
+}
+
+// iamAPIKeyID resolves the access key identifying an API key in paths to its ID.
+func iamAPIKeyID(s *Server, p *apiPath, _, accessKey string) string {
+	for _, rawAPIKey := range s.filter(p.collectionKey("api-keys"), "", "", nil) {
+		apiKey := rawAPIKey.(map[string]interface{})
+		if apiKey["access_key"] == accessKey {
+			return apiKey["id"].(string)
+		}
+	}
+	return accessKey
+}
@@ -0,0 +1,395 @@
+package fakeapi
+
+import (
+	"crypto/md5" //nolint:gosec
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const s3Namespace = "http://s3.amazonaws.com/doc/2006-03-01/"
+
+type bucket struct {
+	name      string
+	region    string
+	createdAt string
+	// configurations holds the documents set on bucket sub resources such as tagging or cors.
+	configurations map[string][]byte
+	objects        map[string]*object
+}
+
+type object struct {
+	body           []byte
+	contentType    string
+	etag           string
+	lastModified   string
+	metadata       map[string]string
+	configurations map[string][]byte
+}
+
+// bucketSubResources are the supported bucket sub resources with the error returned when they are not set.
+// Sub resources without error return their default document instead.
+var bucketSubResources = map[string]string{
+	"tagging":     "NoSuchTagSet",
+	"cors":        "NoSuchCORSConfiguration",
+	"lifecycle":   "NoSuchLifecycleConfiguration",
+	"policy":      "NoSuchBucketPolicy",
+	"website":     "NoSuchWebsiteConfiguration",
+	"object-lock": "ObjectLockConfigurationNotFoundError",
+	"versioning":  "",
+	"acl":         "",
+	"location":    "",
+}
+
+type s3Error struct {
+	XMLName  xml.Name `xml:"Error"`
+	Code     string   `xml:"Code"`
+	Message  string   `xml:"Message"`
+	Resource string   `xml:"Resource,omitempty"`
+}
+
+func writeXML(w http.ResponseWriter, statusCode int, body interface{}) {
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(statusCode)
+	_, _ = io.WriteString(w, xml.Header)
+	_ = xml.NewEncoder(w).Encode(body)
+}
+
+func writeS3Error(w http.ResponseWriter, statusCode int, code, resource string) {
+	writeXML(w, statusCode, s3Error{
+		Code:     code,
+		Message:  code,
+		Resource: resource,
+	})
+}
+
+// parseS3Request returns the region, bucket and object key of a request,
+// supporting both virtual host (bucket.s3.fr-par.scw.cloud) and path (s3.fr-par.scw.cloud/bucket) styles.
+func parseS3Request(host, path string) (region, bucketName, key string) {
+	host = strings.Split(host, ":")[0]
+	path = strings.TrimPrefix(path, "/")
+	if index := strings.Index(host, ".s3."); index >= 0 {
+		bucketName, host = host[:index], host[index+1:]
+	} else {
+		bucketName, path, _ = strings.Cut(path, "/")
+	}
+	region = strings.TrimSuffix(strings.TrimPrefix(host, "s3."), ".scw.cloud")
+	return region, bucketName, path
+}
+
+// subResource returns the sub resource of a request, e.g. tagging for PUT /?tagging.
+func subResource(r *http.Request) string {
+	for name := range r.URL.Query() {
+		if _, known := bucketSubResources[name]; known {
+			return name
+		}
+	}
+	return ""
+}
+
+func (s *Server) serveS3(w http.ResponseWriter, r *http.Request, host string) {
+	region, bucketName, key := parseS3Request(host, r.URL.Path)
+	if bucketName == "" {
+		s.listBuckets(w)
+		return
+	}
+
+	b, exists := s.buckets[bucketName]
+	if r.Method == http.MethodPut && key == "" && subResource(r) == "" {
+		if exists {
+			writeS3Error(w, http.StatusConflict, "BucketAlreadyOwnedByYou", bucketName)
+			return
+		}
+		s.buckets[bucketName] = &bucket{
+			name:           bucketName,
+			region:         region,
+			createdAt:      s.timestamp(),
+			configurations: map[string][]byte{},
+			objects:        map[string]*object{},
+		}
+		w.Header().Set("Location", "/"+bucketName)
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	if !exists {
+		writeS3Error(w, http.StatusNotFound, "NoSuchBucket", bucketName)
+		return
+	}
+
+	if key == "" {
+		s.serveBucket(w, r, b)
+		return
+	}
+	s.serveObject(w, r, b, key)
+}
+
+func (s *Server) listBuckets(w http.ResponseWriter) {
+	type bucketEntry struct {
+		Name         string `xml:"Name"`
+		CreationDate string `xml:"CreationDate"`
+	}
+	result := struct {
+		XMLName xml.Name      `xml:"ListAllMyBucketsResult"`
+		Xmlns   string        `xml:"xmlns,attr"`
+		Owner   string        `xml:"Owner>ID"`
+		Buckets []bucketEntry `xml:"Buckets>Bucket"`
+	}{
+		Xmlns: s3Namespace,
+		Owner: DefaultProjectID,
+	}
+	for _, b := range s.buckets {
+		result.Buckets = append(result.Buckets, bucketEntry{Name: b.name, CreationDate: b.createdAt})
+	}
+	sort.Slice(result.Buckets, func(i, j int) bool { return result.Buckets[i].Name < result.Buckets[j].Name })
+	writeXML(w, http.StatusOK, result)
+}
+
+func (s *Server) serveBucket(w http.ResponseWriter, r *http.Request, b *bucket) {
+	if name := subResource(r); name != "" {
+		serveConfiguration(w, r, b.configurations, name, b, "")
+		return
+	}
+
+	query := r.URL.Query()
+	switch r.Method {
+	case http.MethodHead:
+		w.WriteHeader(http.StatusOK)
+	case http.MethodGet:
+		switch {
+		case query.Has("versions"):
+			listObjects(w, b, query.Get("prefix"), "ListVersionsResult", "Version")
+		case query.Has("uploads"):
+			writeXML(w, http.StatusOK, struct {
+				XMLName xml.Name `xml:"ListMultipartUploadsResult"`
+				Xmlns   string   `xml:"xmlns,attr"`
+				Bucket  string   `xml:"Bucket"`
+			}{Xmlns: s3Namespace, Bucket: b.name})
+		default:
+			listObjects(w, b, query.Get("prefix"), "ListBucketResult", "Contents")
+		}
+	case http.MethodDelete:
+		if len(b.objects) > 0 {
+			writeS3Error(w, http.StatusConflict, "BucketNotEmpty", b.name)
+			return
+		}
+		delete(s.buckets, b.name)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeS3Error(w, http.StatusMethodNotAllowed, "MethodNotAllowed", b.name)
+	}
+}
+
+// listObjects writes the objects of a bucket, as a list of objects or of object versions.
+func listObjects(w http.ResponseWriter, b *bucket, prefix string, resultName string, entryName string) {
+	type objectEntry struct {
+		XMLName      xml.Name
+		Key          string `xml:"Key"`
+		VersionID    string `xml:"VersionId,omitempty"`
+		IsLatest     bool   `xml:"IsLatest,omitempty"`
+		LastModified string `xml:"LastModified"`
+		ETag         string `xml:"ETag"`
+		Size         int    `xml:"Size"`
+		StorageClass string `xml:"StorageClass"`
+	}
+	result := struct {
+		XMLName     xml.Name
+		Xmlns       string        `xml:"xmlns,attr"`
+		Name        string        `xml:"Name"`
+		Prefix      string        `xml:"Prefix"`
+		KeyCount    int           `xml:"KeyCount"`
+		IsTruncated bool          `xml:"IsTruncated"`
+		Objects     []objectEntry `xml:""`
+	}{
+		XMLName: xml.Name{Local: resultName},
+		Xmlns:   s3Namespace,
+		Name:    b.name,
+		Prefix:  prefix,
+	}
+
+	keys := make([]string, 0, len(b.objects))
+	for key := range b.objects {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		o := b.objects[key]
+		entry := objectEntry{
+			XMLName:      xml.Name{Local: entryName},
+			Key:          key,
+			LastModified: o.lastModified,
+			ETag:         o.etag,
+			Size:         len(o.body),
+			StorageClass: "STANDARD",
+		}
+		if entryName == "Version" {
+			entry.VersionID = "null"
+			entry.IsLatest = true
+		}
+		result.Objects = append(result.Objects, entry)
+	}
+	result.KeyCount = len(result.Objects)
+	writeXML(w, http.StatusOK, result)
+}
+
+// serveConfiguration stores and returns the documents set on a bucket sub resource, or on an object one when key is set.
+func serveConfiguration(w http.ResponseWriter, r *http.Request, configurations map[string][]byte, name string, b *bucket, key string) {
+	switch r.Method {
+	case http.MethodPut:
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			writeS3Error(w, http.StatusBadRequest, "IncompleteBody", b.name)
+			return
+		}
+		if len(body) == 0 {
+			// Canned ACLs are sent as headers, the default document is returned for them.
+			delete(configurations, name)
+		} else {
+			configurations[name] = body
+		}
+		w.WriteHeader(http.StatusOK)
+	case http.MethodGet:
+		if configuration, exists := configurations[name]; exists {
+			if name == "policy" {
+				w.Header().Set("Content-Type", "application/json")
+			} else {
+				w.Header().Set("Content-Type", "application/xml")
+			}
+			_, _ = w.Write(configuration)
+			return
+		}
+		switch name {
+		case "versioning":
+			writeXML(w, http.StatusOK, struct {
+				XMLName xml.Name `xml:"VersioningConfiguration"`
+				Xmlns   string   `xml:"xmlns,attr"`
+			}{Xmlns: s3Namespace})
+		case "acl":
+			writeXML(w, http.StatusOK, defaultACL())
+		case "location":
+			writeXML(w, http.StatusOK, struct {
+				XMLName xml.Name `xml:"LocationConstraint"`
+				Xmlns   string   `xml:"xmlns,attr"`
+				Region  string   `xml:",chardata"`
+			}{Xmlns: s3Namespace, Region: b.region})
+		case "tagging":
+			if key != "" {
+				// Objects have an empty tag set by default.
+				writeXML(w, http.StatusOK, struct {
+					XMLName xml.Name `xml:"Tagging"`
+					Xmlns   string   `xml:"xmlns,attr"`
+					TagSet  string   `xml:"TagSet"`
+				}{Xmlns: s3Namespace})
+				return
+			}
+			writeS3Error(w, http.StatusNotFound, bucketSubResources[name], b.name)
+		default:
+			writeS3Error(w, http.StatusNotFound, bucketSubResources[name], b.name)
+		}
+	case http.MethodDelete:
+		delete(configurations, name)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeS3Error(w, http.StatusMethodNotAllowed, "MethodNotAllowed", b.name)
+	}
+}
+
+type accessControlPolicy struct {
+	XMLName xml.Name `xml:"AccessControlPolicy"`
+	Xmlns   string   `xml:"xmlns,attr"`
+	Owner   struct {
+		ID          string `xml:"ID"`
+		DisplayName string `xml:"DisplayName"`
+	} `xml:"Owner"`
+	Grants []grant `xml:"AccessControlList>Grant"`
+}
+
+type grant struct {
+	Grantee struct {
+		XMLNS       string `xml:"xmlns:xsi,attr"`
+		Type        string `xml:"xsi:type,attr"`
+		ID          string `xml:"ID"`
+		DisplayName string `xml:"DisplayName"`
+	} `xml:"Grantee"`
+	Permission string `xml:"Permission"`
+}
+
+func defaultACL() accessControlPolicy {
+	owner := fmt.Sprintf("%[1]s:%[1]s", DefaultProjectID)
+	acl := accessControlPolicy{Xmlns: s3Namespace}
+	acl.Owner.ID = owner
+	acl.Owner.DisplayName = owner
+	g := grant{Permission: "FULL_CONTROL"}
+	g.Grantee.XMLNS = "http://www.w3.org/2001/XMLSchema-instance"
+	g.Grantee.Type = "CanonicalUser"
+	g.Grantee.ID = owner
+	g.Grantee.DisplayName = owner
+	acl.Grants = []grant{g}
+	return acl
+}
+
+func (s *Server) serveObject(w http.ResponseWriter, r *http.Request, b *bucket, key string) {
+	o, exists := b.objects[key]
+	if name := subResource(r); name != "" {
+		if !exists {
+			writeS3Error(w, http.StatusNotFound, "NoSuchKey", key)
+			return
+		}
+		serveConfiguration(w, r, o.configurations, name, b, key)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPut:
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			writeS3Error(w, http.StatusBadRequest, "IncompleteBody", key)
+			return
+		}
+		sum := md5.Sum(body) //nolint:gosec
+		o = &object{
+			body:           body,
+			contentType:    r.Header.Get("Content-Type"),
+			etag:           strconv.Quote(hex.EncodeToString(sum[:])),
+			lastModified:   s.timestamp(),
+			metadata:       map[string]string{},
+			configurations: map[string][]byte{},
+		}
+		for header := range r.Header {
+			if strings.HasPrefix(strings.ToLower(header), "x-amz-meta-") {
+				o.metadata[header] = r.Header.Get(header)
+			}
+		}
+		b.objects[key] = o
+		w.Header().Set("ETag", o.etag)
+		w.WriteHeader(http.StatusOK)
+	case http.MethodGet, http.MethodHead:
+		if !exists {
+			writeS3Error(w, http.StatusNotFound, "NoSuchKey", key)
+			return
+		}
+		for header, value := range o.metadata {
+			w.Header().Set(header, value)
+		}
+		if o.contentType != "" {
+			w.Header().Set("Content-Type", o.contentType)
+		}
+		w.Header().Set("ETag", o.etag)
+		w.Header().Set("Content-Length", strconv.Itoa(len(o.body)))
+		w.WriteHeader(http.StatusOK)
+		if r.Method == http.MethodGet {
+			_, _ = w.Write(o.body)
+		}
+	case http.MethodDelete:
+		delete(b.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeS3Error(w, http.StatusMethodNotAllowed, "MethodNotAllowed", key)
+	}
+}
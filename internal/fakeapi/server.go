@@ -0,0 +1,121 @@
+// Package fakeapi provides an in-process fake of the Scaleway APIs.
+//
+// It implements a stateful CRUD for the products used by the provider (instance, vpc, lb, rdb, k8s, domain)
+// a minimal S3 compatible object storage and the Kubernetes API endpoints used to drain nodes,
+// allowing tests to run offline without cassettes.
+package fakeapi
+
+import (
+	"crypto/rand"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultProjectID is the project used when a request does not specify one.
+	DefaultProjectID = "11111111-1111-1111-1111-111111111111"
+	// DefaultOrganizationID is the organization of every created object.
+	DefaultOrganizationID = "11111111-1111-1111-1111-111111111111"
+
+	// originalHostHeader holds the host a request was sent to before being redirected to the fake server.
+	originalHostHeader = "X-Fakeapi-Original-Host"
+)
+
+// Server is an in-process fake Scaleway API.
+type Server struct {
+	*httptest.Server
+
+	mu sync.Mutex
+	// objects are stored by collection key, see collectionKey, then by ID.
+	objects map[string]map[string]map[string]interface{}
+	// order keeps the creation order of the objects of a collection.
+	order   map[string][]string
+	buckets map[string]*bucket
+	// kubeClusters are stored by API server host.
+	kubeClusters map[string]*kubeCluster
+
+	now func() time.Time
+}
+
+// NewServer starts a fake Scaleway API. It should be closed once done with it.
+func NewServer() *Server {
+	s := &Server{
+		objects:      map[string]map[string]map[string]interface{}{},
+		order:        map[string][]string{},
+		buckets:      map[string]*bucket{},
+		kubeClusters: map[string]*kubeCluster{},
+		now:          time.Now,
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// APIURL is the URL to use as the Scaleway API URL.
+func (s *Server) APIURL() string {
+	return s.URL
+}
+
+// Client returns an HTTP client sending every request to the fake server, including the ones sent to S3 endpoints.
+func (s *Server) Client() *http.Client {
+	target, _ := url.Parse(s.URL)
+	return &http.Client{
+		Transport: &redirectTransport{
+			target:    target,
+			transport: s.Server.Client().Transport,
+		},
+	}
+}
+
+// redirectTransport sends requests to the fake server, keeping their original host in a header.
+type redirectTransport struct {
+	target    *url.URL
+	transport http.RoundTripper
+}
+
+func (t *redirectTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	r = r.Clone(r.Context())
+	if r.URL.Host != t.target.Host {
+		r.Header.Set(originalHostHeader, r.URL.Host)
+	}
+	r.URL.Scheme = t.target.Scheme
+	r.URL.Host = t.target.Host
+	r.Host = t.target.Host
+	return t.transport.RoundTrip(r)
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	host := r.Header.Get(originalHostHeader)
+	if isS3Host(host) {
+		s.serveS3(w, r, host)
+		return
+	}
+	if isKubeHost(host) {
+		s.serveKube(w, r, host)
+		return
+	}
+	s.serveAPI(w, r)
+}
+
+func isS3Host(host string) bool {
+	return strings.HasPrefix(host, "s3.") || strings.Contains(host, ".s3.")
+}
+
+func newUUID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+func (s *Server) timestamp() string {
+	return s.now().UTC().Format(time.RFC3339Nano)
+}
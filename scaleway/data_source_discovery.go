@@ -0,0 +1,131 @@
+package scaleway
+
+import (
+	"context"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// discoveredResource is an existing resource that can be imported with its Terraform ID.
+type discoveredResource struct {
+	Type string
+	ID   string
+	Name string
+}
+
+// discoverFunc lists the resources of a product in a zone or region, only in the given project when projectID is not nil.
+type discoverFunc func(ctx context.Context, meta *Meta, locality string, projectID *string) ([]discoveredResource, error)
+
+// dataSourceScalewayDiscovery returns a data source listing the resources of a product to feed import blocks.
+// localityKey is either "zone" or "region".
+func dataSourceScalewayDiscovery(localityKey string, resourceTypes []string, discover discoverFunc) *schema.Resource {
+	localitySchema := zoneSchema()
+	if localityKey == "region" {
+		localitySchema = regionSchema()
+	}
+
+	return &schema.Resource{
+		ReadContext: func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+			return dataSourceScalewayDiscoveryRead(ctx, d, m, localityKey, discover)
+		},
+		Schema: map[string]*schema.Schema{
+			"resource_types": {
+				Type: schema.TypeList,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.StringInSlice(resourceTypes, false),
+				},
+				Optional:    true,
+				Description: "Only list resources of these Terraform types",
+			},
+			"resources": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The resources that can be imported",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"type": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The Terraform type of the resource",
+						},
+						"id": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The Terraform ID of the resource, to be used as import ID",
+						},
+						"name": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The name of the resource",
+						},
+					},
+				},
+			},
+			localityKey: localitySchema,
+			"project_id": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  "Only list resources of this project",
+				ValidateFunc: validationUUID(),
+			},
+		},
+	}
+}
+
+func dataSourceScalewayDiscoveryRead(ctx context.Context, d *schema.ResourceData, m interface{}, localityKey string, discover discoverFunc) diag.Diagnostics {
+	meta := m.(*Meta)
+
+	var locality string
+	if localityKey == "region" {
+		region, err := extractRegion(d, meta)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		locality = region.String()
+	} else {
+		zone, err := extractZone(d, meta)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		locality = zone.String()
+	}
+
+	resources, err := discover(ctx, meta, locality, expandStringPtr(d.Get("project_id")))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	resourceTypes := map[string]bool{}
+	for _, resourceType := range expandStrings(d.Get("resource_types")) {
+		resourceTypes[resourceType] = true
+	}
+
+	sort.SliceStable(resources, func(i, j int) bool {
+		if resources[i].Type != resources[j].Type {
+			return resources[i].Type < resources[j].Type
+		}
+		return resources[i].Name < resources[j].Name
+	})
+
+	rawResources := []interface{}(nil)
+	for _, resource := range resources {
+		if len(resourceTypes) > 0 && !resourceTypes[resource.Type] {
+			continue
+		}
+		rawResources = append(rawResources, map[string]interface{}{
+			"type": resource.Type,
+			"id":   resource.ID,
+			"name": resource.Name,
+		})
+	}
+
+	d.SetId(locality)
+	_ = d.Set(localityKey, locality)
+	_ = d.Set("resources", rawResources)
+
+	return nil
+}
@@ -0,0 +1,107 @@
+package scaleway
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/scaleway/scaleway-sdk-go/api/instance/v1"
+	"github.com/scaleway/scaleway-sdk-go/scw"
+)
+
+func dataSourceScalewayInstanceDiscovery() *schema.Resource {
+	return dataSourceScalewayDiscovery("zone", []string{
+		"scaleway_instance_server",
+		"scaleway_instance_volume",
+		"scaleway_instance_security_group",
+		"scaleway_instance_ip",
+		"scaleway_instance_placement_group",
+		"scaleway_instance_snapshot",
+		"scaleway_instance_image",
+	}, discoverInstanceResources)
+}
+
+func discoverInstanceResources(ctx context.Context, meta *Meta, locality string, projectID *string) ([]discoveredResource, error) {
+	instanceAPI := instance.NewAPI(meta.scwClient)
+	zone := scw.Zone(locality)
+	var resources []discoveredResource
+
+	servers, err := instanceAPI.ListServers(&instance.ListServersRequest{
+		Zone:    zone,
+		Project: projectID,
+	}, scw.WithContext(ctx), scw.WithAllPages())
+	if err != nil {
+		return nil, err
+	}
+	for _, server := range servers.Servers {
+		resources = append(resources, discoveredResource{Type: "scaleway_instance_server", ID: newZonedIDString(zone, server.ID), Name: server.Name})
+	}
+
+	volumes, err := instanceAPI.ListVolumes(&instance.ListVolumesRequest{
+		Zone:    zone,
+		Project: projectID,
+	}, scw.WithContext(ctx), scw.WithAllPages())
+	if err != nil {
+		return nil, err
+	}
+	for _, volume := range volumes.Volumes {
+		resources = append(resources, discoveredResource{Type: "scaleway_instance_volume", ID: newZonedIDString(zone, volume.ID), Name: volume.Name})
+	}
+
+	securityGroups, err := instanceAPI.ListSecurityGroups(&instance.ListSecurityGroupsRequest{
+		Zone:    zone,
+		Project: projectID,
+	}, scw.WithContext(ctx), scw.WithAllPages())
+	if err != nil {
+		return nil, err
+	}
+	for _, securityGroup := range securityGroups.SecurityGroups {
+		resources = append(resources, discoveredResource{Type: "scaleway_instance_security_group", ID: newZonedIDString(zone, securityGroup.ID), Name: securityGroup.Name})
+	}
+
+	ips, err := instanceAPI.ListIPs(&instance.ListIPsRequest{
+		Zone:    zone,
+		Project: projectID,
+	}, scw.WithContext(ctx), scw.WithAllPages())
+	if err != nil {
+		return nil, err
+	}
+	for _, ip := range ips.IPs {
+		resources = append(resources, discoveredResource{Type: "scaleway_instance_ip", ID: newZonedIDString(zone, ip.ID), Name: ip.Address.String()})
+	}
+
+	placementGroups, err := instanceAPI.ListPlacementGroups(&instance.ListPlacementGroupsRequest{
+		Zone:    zone,
+		Project: projectID,
+	}, scw.WithContext(ctx), scw.WithAllPages())
+	if err != nil {
+		return nil, err
+	}
+	for _, placementGroup := range placementGroups.PlacementGroups {
+		resources = append(resources, discoveredResource{Type: "scaleway_instance_placement_group", ID: newZonedIDString(zone, placementGroup.ID), Name: placementGroup.Name})
+	}
+
+	snapshots, err := instanceAPI.ListSnapshots(&instance.ListSnapshotsRequest{
+		Zone:    zone,
+		Project: projectID,
+	}, scw.WithContext(ctx), scw.WithAllPages())
+	if err != nil {
+		return nil, err
+	}
+	for _, snapshot := range snapshots.Snapshots {
+		resources = append(resources, discoveredResource{Type: "scaleway_instance_snapshot", ID: newZonedIDString(zone, snapshot.ID), Name: snapshot.Name})
+	}
+
+	images, err := instanceAPI.ListImages(&instance.ListImagesRequest{
+		Zone:    zone,
+		Project: projectID,
+		Public:  scw.BoolPtr(false),
+	}, scw.WithContext(ctx), scw.WithAllPages())
+	if err != nil {
+		return nil, err
+	}
+	for _, image := range images.Images {
+		resources = append(resources, discoveredResource{Type: "scaleway_instance_image", ID: newZonedIDString(zone, image.ID), Name: image.Name})
+	}
+
+	return resources, nil
+}
@@ -0,0 +1,30 @@
+package scaleway
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFakeAPI_InstanceDiscovery(t *testing.T) {
+	tt := NewFakeAPITestTools(t)
+	defer tt.Cleanup()
+	ctx := context.Background()
+
+	ip := schema.TestResourceDataRaw(t, resourceScalewayInstanceIP().Schema, map[string]interface{}{})
+	diags := resourceScalewayInstanceIPCreate(ctx, ip, tt.Meta)
+	require.False(t, diags.HasError(), diags)
+
+	dataSource := dataSourceScalewayInstanceDiscovery()
+	d := schema.TestResourceDataRaw(t, dataSource.Schema, map[string]interface{}{
+		"resource_types": []interface{}{"scaleway_instance_ip"},
+	})
+	diags = dataSource.ReadContext(ctx, d, tt.Meta)
+	require.False(t, diags.HasError(), diags)
+	resources := d.Get("resources").([]interface{})
+	require.Len(t, resources, 1)
+	assert.Equal(t, ip.Id(), resources[0].(map[string]interface{})["id"])
+}
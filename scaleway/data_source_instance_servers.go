@@ -7,7 +7,9 @@ import (
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/scaleway/scaleway-sdk-go/api/instance/v1"
+	ipam "github.com/scaleway/scaleway-sdk-go/api/ipam/v1alpha1"
 	"github.com/scaleway/scaleway-sdk-go/scw"
 )
 
@@ -28,6 +30,41 @@ func dataSourceScalewayInstanceServers() *schema.Resource {
 				Optional:    true,
 				Description: "Servers with these exact tags are listed.",
 			},
+			"commercial_type": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Servers of this commercial type are listed.",
+			},
+			"state": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Servers in this state are listed.",
+				ValidateFunc: validation.StringInSlice([]string{
+					InstanceServerStateStarted,
+					InstanceServerStateStopped,
+					InstanceServerStateStandby,
+				}, false),
+			},
+			"image": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Servers created from this image ID are listed.",
+			},
+			"private_network_id": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Servers attached to this private network are listed.",
+			},
+			"placement_group_id": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Servers in this placement group are listed.",
+			},
+			"all_zones": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Description: "Servers of all zones are listed, instead of the servers of zone only.",
+			},
 			"servers": {
 				Type:     schema.TypeList,
 				Computed: true,
@@ -108,9 +145,58 @@ func dataSourceScalewayInstanceServers() *schema.Resource {
 							Computed: true,
 							Type:     schema.TypeInt,
 						},
-						"zone":            zoneSchema(),
-						"organization_id": organizationIDSchema(),
-						"project_id":      projectIDSchema(),
+						"public_ips": {
+							Computed: true,
+							Type:     schema.TypeList,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"id": {
+										Computed: true,
+										Type:     schema.TypeString,
+									},
+									"address": {
+										Computed: true,
+										Type:     schema.TypeString,
+									},
+									"dynamic": {
+										Computed: true,
+										Type:     schema.TypeBool,
+									},
+								},
+							},
+						},
+						"private_network": {
+							Computed: true,
+							Type:     schema.TypeList,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"pn_id": {
+										Computed: true,
+										Type:     schema.TypeString,
+									},
+									"mac_address": {
+										Computed: true,
+										Type:     schema.TypeString,
+									},
+									"status": {
+										Computed: true,
+										Type:     schema.TypeString,
+									},
+									"ip_addresses": {
+										Computed: true,
+										Type:     schema.TypeList,
+										Elem: &schema.Schema{
+											Type: schema.TypeString,
+										},
+									},
+								},
+							},
+						},
+						"root_volume":        dataSourceInstanceServersVolumeSchema(),
+						"additional_volumes": dataSourceInstanceServersVolumeSchema(),
+						"zone":               zoneSchema(),
+						"organization_id":    organizationIDSchema(),
+						"project_id":         projectIDSchema(),
 					},
 				},
 			},
@@ -121,79 +207,187 @@ func dataSourceScalewayInstanceServers() *schema.Resource {
 	}
 }
 
+func dataSourceInstanceServersVolumeSchema() *schema.Schema {
+	return &schema.Schema{
+		Computed: true,
+		Type:     schema.TypeList,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"volume_id": {
+					Computed: true,
+					Type:     schema.TypeString,
+				},
+				"name": {
+					Computed: true,
+					Type:     schema.TypeString,
+				},
+				"size_in_gb": {
+					Computed: true,
+					Type:     schema.TypeInt,
+				},
+				"volume_type": {
+					Computed: true,
+					Type:     schema.TypeString,
+				},
+			},
+		},
+	}
+}
+
 func dataSourceScalewayInstanceServersRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	instanceAPI, zone, err := instanceAPIWithZone(d, meta)
 	if err != nil {
 		return diag.FromErr(err)
 	}
-	res, err := instanceAPI.ListServers(&instance.ListServersRequest{
-		Zone:    zone,
-		Name:    expandStringPtr(d.Get("name")),
-		Project: expandStringPtr(d.Get("project_id")),
-		Tags:    expandStrings(d.Get("tags")),
-	}, scw.WithContext(ctx))
-	if err != nil {
-		return diag.FromErr(err)
+	ipamAPI := ipam.NewAPI(meta.(*Meta).scwClient)
+
+	zones := []scw.Zone{zone}
+	if d.Get("all_zones").(bool) {
+		zones = instanceAPI.Zones()
+	}
+
+	req := &instance.ListServersRequest{
+		Name:           expandStringPtr(d.Get("name")),
+		Project:        expandStringPtr(d.Get("project_id")),
+		Tags:           expandStrings(d.Get("tags")),
+		CommercialType: expandStringPtr(d.Get("commercial_type")),
+	}
+	if rawState, ok := d.GetOk("state"); ok {
+		state, err := serverStateExpand(rawState.(string))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		req.State = &state
 	}
+	if privateNetworkID, ok := d.GetOk("private_network_id"); ok {
+		req.PrivateNetwork = scw.StringPtr(expandID(privateNetworkID))
+	}
+	imageID := expandID(d.Get("image"))
+	placementGroupID := expandID(d.Get("placement_group_id"))
 
 	var diags diag.Diagnostics
 
 	servers := []interface{}(nil)
-	for _, server := range res.Servers {
-		rawServer := make(map[string]interface{})
-		rawServer["id"] = newZonedID(server.Zone, server.ID).String()
-		if server.PublicIP != nil {
-			rawServer["public_ip"] = server.PublicIP.Address.String()
-		}
-		if server.PrivateIP != nil {
-			rawServer["private_ip"] = *server.PrivateIP
-		}
-		state, err := serverStateFlatten(server.State)
+	for _, zone := range zones {
+		req.Zone = zone
+		res, err := instanceAPI.ListServers(req, scw.WithAllPages(), scw.WithContext(ctx))
 		if err != nil {
-			diags = append(diags, diag.FromErr(err)...)
-			continue
-		}
-		rawServer["state"] = state
-		rawServer["zone"] = string(zone)
-		rawServer["name"] = server.Name
-		rawServer["boot_type"] = server.BootType
-		rawServer["bootscript_id"] = server.Bootscript.ID
-		rawServer["type"] = server.CommercialType
-		if len(server.Tags) > 0 {
-			rawServer["tags"] = server.Tags
+			return diag.FromErr(err)
 		}
-		rawServer["security_group_id"] = newZonedID(zone, server.SecurityGroup.ID).String()
-		rawServer["enable_ipv6"] = server.EnableIPv6
-		rawServer["enable_dynamic_ip"] = server.DynamicIPRequired
-		rawServer["organization_id"] = server.Organization
-		rawServer["project_id"] = server.Project
-		if server.Image != nil {
-			rawServer["image"] = server.Image.ID
-		}
-		if server.PlacementGroup != nil {
-			rawServer["placement_group_id"] = newZonedID(zone, server.PlacementGroup.ID).String()
-			rawServer["placement_group_policy_respected"] = server.PlacementGroup.PolicyRespected
-		}
-		if server.IPv6 != nil {
-			rawServer["ipv6_address"] = server.IPv6.Address.String()
-			rawServer["ipv6_gateway"] = server.IPv6.Gateway.String()
-			prefixLength, err := strconv.Atoi(server.IPv6.Netmask)
-			if err != nil {
-				diags = append(diags, diag.FromErr(fmt.Errorf("failed to read ipv6 netmask: %w", err))...)
+
+		for _, server := range res.Servers {
+			if imageID != "" && (server.Image == nil || server.Image.ID != imageID) {
+				continue
+			}
+			if placementGroupID != "" && (server.PlacementGroup == nil || server.PlacementGroup.ID != placementGroupID) {
 				continue
 			}
 
-			rawServer["ipv6_prefix_length"] = prefixLength
+			rawServer, err := flattenInstanceServersServer(ctx, ipamAPI, server)
+			if err != nil {
+				diags = append(diags, diag.FromErr(err)...)
+				continue
+			}
+			servers = append(servers, rawServer)
 		}
-
-		servers = append(servers, rawServer)
 	}
 	if len(diags) > 0 {
 		return diags
 	}
 
-	d.SetId(zone.String())
+	if d.Get("all_zones").(bool) {
+		d.SetId("all")
+	} else {
+		d.SetId(zone.String())
+	}
 	_ = d.Set("servers", servers)
 
 	return nil
 }
+
+func flattenInstanceServersServer(ctx context.Context, ipamAPI *ipam.API, server *instance.Server) (map[string]interface{}, error) {
+	zone := server.Zone
+	rawServer := make(map[string]interface{})
+	rawServer["id"] = newZonedID(server.Zone, server.ID).String()
+	if server.PublicIP != nil {
+		rawServer["public_ip"] = server.PublicIP.Address.String()
+		rawServer["public_ips"] = []interface{}{map[string]interface{}{
+			"id":      newZonedID(zone, server.PublicIP.ID).String(),
+			"address": server.PublicIP.Address.String(),
+			"dynamic": server.PublicIP.Dynamic,
+		}}
+	}
+	if server.PrivateIP != nil {
+		rawServer["private_ip"] = *server.PrivateIP
+	}
+	state, err := serverStateFlatten(server.State)
+	if err != nil {
+		return nil, err
+	}
+	rawServer["state"] = state
+	rawServer["zone"] = zone.String()
+	rawServer["name"] = server.Name
+	rawServer["boot_type"] = server.BootType
+	if server.Bootscript != nil {
+		rawServer["bootscript_id"] = server.Bootscript.ID
+	}
+	rawServer["type"] = server.CommercialType
+	if len(server.Tags) > 0 {
+		rawServer["tags"] = server.Tags
+	}
+	rawServer["security_group_id"] = newZonedID(zone, server.SecurityGroup.ID).String()
+	rawServer["enable_ipv6"] = server.EnableIPv6
+	rawServer["enable_dynamic_ip"] = server.DynamicIPRequired
+	rawServer["organization_id"] = server.Organization
+	rawServer["project_id"] = server.Project
+	if server.Image != nil {
+		rawServer["image"] = server.Image.ID
+	}
+	if server.PlacementGroup != nil {
+		rawServer["placement_group_id"] = newZonedID(zone, server.PlacementGroup.ID).String()
+		rawServer["placement_group_policy_respected"] = server.PlacementGroup.PolicyRespected
+	}
+	if server.IPv6 != nil {
+		rawServer["ipv6_address"] = server.IPv6.Address.String()
+		rawServer["ipv6_gateway"] = server.IPv6.Gateway.String()
+		prefixLength, err := strconv.Atoi(server.IPv6.Netmask)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read ipv6 netmask: %w", err)
+		}
+
+		rawServer["ipv6_prefix_length"] = prefixLength
+	}
+
+	additionalVolumes := []interface{}(nil)
+	for i, volume := range sortVolumeServer(server.Volumes) {
+		rawVolume := map[string]interface{}{
+			"volume_id":   newZonedID(zone, volume.ID).String(),
+			"name":        volume.Name,
+			"size_in_gb":  int(uint64(volume.Size) / gb),
+			"volume_type": volume.VolumeType.String(),
+		}
+		if i == 0 {
+			rawServer["root_volume"] = []interface{}{rawVolume}
+			continue
+		}
+		additionalVolumes = append(additionalVolumes, rawVolume)
+	}
+	rawServer["additional_volumes"] = additionalVolumes
+
+	privateNetworks := []interface{}(nil)
+	for _, nic := range server.PrivateNics {
+		ipAddresses, err := instancePrivateNICIPAddresses(ctx, ipamAPI, zone, nic.ID)
+		if err != nil {
+			return nil, err
+		}
+		privateNetworks = append(privateNetworks, map[string]interface{}{
+			"pn_id":        newZonedID(zone, nic.PrivateNetworkID).String(),
+			"mac_address":  nic.MacAddress,
+			"status":       nic.State.String(),
+			"ip_addresses": ipAddresses,
+		})
+	}
+	rawServer["private_network"] = privateNetworks
+
+	return rawServer, nil
+}
@@ -1,9 +1,14 @@
 package scaleway
 
 import (
+	"context"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/scaleway/scaleway-sdk-go/api/instance/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestAccScalewayDataSourceInstanceServers_Basic(t *testing.T) {
@@ -86,3 +91,60 @@ func TestAccScalewayDataSourceInstanceServers_Basic(t *testing.T) {
 		},
 	})
 }
+
+func TestFakeAPI_InstanceServersDataSource(t *testing.T) {
+	tt := NewFakeAPITestTools(t)
+	defer tt.Cleanup()
+	ctx := context.Background()
+
+	servers := map[string]*schema.ResourceData{}
+	for name, raw := range map[string]map[string]interface{}{
+		"small": {"type": "DEV1-S"},
+		"large": {"type": "DEV1-L", "state": "stopped"},
+		"other": {"type": "DEV1-S", "zone": "fr-par-2"},
+	} {
+		raw["name"] = name
+		raw["image"] = "11111111-1111-1111-1111-111111111111"
+		d := schema.TestResourceDataRaw(t, resourceScalewayInstanceServer().Schema, raw)
+		diags := resourceScalewayInstanceServerCreate(ctx, d, tt.Meta)
+		require.False(t, diags.HasError(), diags)
+		servers[name] = d
+	}
+
+	instanceAPI, zone, smallID, err := instanceAPIWithZoneAndID(tt.Meta, servers["small"].Id())
+	require.NoError(t, err)
+	nic, err := instanceAPI.CreatePrivateNIC(&instance.CreatePrivateNICRequest{
+		Zone:             zone,
+		ServerID:         smallID,
+		PrivateNetworkID: "22222222-2222-2222-2222-222222222222",
+	})
+	require.NoError(t, err)
+
+	readServers := func(raw map[string]interface{}) []interface{} {
+		dataSource := dataSourceScalewayInstanceServers()
+		d := schema.TestResourceDataRaw(t, dataSource.Schema, raw)
+		diags := dataSource.ReadContext(ctx, d, tt.Meta)
+		require.False(t, diags.HasError(), diags)
+		return d.Get("servers").([]interface{})
+	}
+
+	found := readServers(map[string]interface{}{"commercial_type": "DEV1-S"})
+	require.Len(t, found, 1)
+	server := found[0].(map[string]interface{})
+	assert.Equal(t, servers["small"].Id(), server["id"])
+	assert.Equal(t, servers["small"].Get("root_volume.0.volume_id"), server["root_volume"].([]interface{})[0].(map[string]interface{})["volume_id"])
+	privateNetworks := server["private_network"].([]interface{})
+	require.Len(t, privateNetworks, 1)
+	assert.Equal(t, "fr-par-1/22222222-2222-2222-2222-222222222222", privateNetworks[0].(map[string]interface{})["pn_id"])
+	assert.Equal(t, nic.PrivateNic.MacAddress, privateNetworks[0].(map[string]interface{})["mac_address"])
+
+	found = readServers(map[string]interface{}{"state": "stopped"})
+	require.Len(t, found, 1)
+	assert.Equal(t, servers["large"].Id(), found[0].(map[string]interface{})["id"])
+
+	found = readServers(map[string]interface{}{"commercial_type": "DEV1-S", "all_zones": true})
+	assert.Len(t, found, 2)
+
+	found = readServers(map[string]interface{}{"image": "fr-par-1/33333333-3333-3333-3333-333333333333"})
+	assert.Empty(t, found)
+}
@@ -15,8 +15,9 @@ func dataSourceScalewayK8SCluster() *schema.Resource {
 	dsSchema := datasourceSchemaFromResourceSchema(resourceScalewayK8SCluster().Schema)
 
 	// Set 'Optional' schema elements
-	addOptionalFieldsToSchema(dsSchema, "name", "region")
+	addOptionalFieldsToSchema(dsSchema, "name", "region", "persist_kubeconfig")
 	delete(dsSchema, "delete_additional_resources")
+	delete(dsSchema, "upgrade_pools")
 
 	dsSchema["name"].ConflictsWith = []string{"cluster_id"}
 	dsSchema["cluster_id"] = &schema.Schema{
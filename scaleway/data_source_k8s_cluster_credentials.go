@@ -0,0 +1,180 @@
+package scaleway
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	iam "github.com/scaleway/scaleway-sdk-go/api/iam/v1alpha1"
+	"github.com/scaleway/scaleway-sdk-go/api/k8s/v1"
+	"github.com/scaleway/scaleway-sdk-go/scw"
+	"gopkg.in/yaml.v2"
+)
+
+func dataSourceScalewayK8SClusterCredentials() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceScalewayK8SClusterCredentialsRead,
+		Schema: map[string]*schema.Schema{
+			"cluster_id": {
+				Type:         schema.TypeString,
+				Required:     true,
+				Description:  "The ID of the cluster",
+				ValidateFunc: validationUUIDorUUIDWithLocality(),
+			},
+			"iam_application_id": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  "The IAM application the token is minted for, the admin token of the cluster is used when not set",
+				ValidateFunc: validationUUID(),
+			},
+			"token_ttl": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "1h",
+				Description:  "The lifetime of the token minted for the IAM application",
+				ValidateFunc: validateDuration(),
+			},
+			"host": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The kubernetes master URL",
+			},
+			"cluster_ca_certificate": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The kubernetes cluster CA certificate",
+			},
+			"token": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Sensitive:   true,
+				Description: "The token to authenticate to the cluster",
+			},
+			"config_file": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Sensitive:   true,
+				Description: "The whole kubeconfig file, using the token",
+			},
+			"expires_at": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The date and time of the expiration of the token, empty for the admin token",
+			},
+			"region": regionSchema(),
+		},
+	}
+}
+
+func dataSourceScalewayK8SClusterCredentialsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	k8sAPI, region, err := k8sAPIWithRegion(d, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	clusterID := expandID(d.Get("cluster_id"))
+
+	cluster, err := k8sAPI.GetCluster(&k8s.GetClusterRequest{
+		Region:    region,
+		ClusterID: clusterID,
+	}, scw.WithContext(ctx))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	kubeconfig, err := k8sAPI.GetClusterKubeConfig(&k8s.GetClusterKubeConfigRequest{
+		Region:    region,
+		ClusterID: clusterID,
+	}, scw.WithContext(ctx))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	host, err := kubeconfig.GetServer()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	ca, err := kubeconfig.GetCertificateAuthorityData()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	token, err := kubeconfig.GetToken()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	configFile := string(kubeconfig.GetRaw())
+	var expiresAt interface{}
+
+	var diags diag.Diagnostics
+
+	if applicationID, ok := d.GetOk("iam_application_id"); ok {
+		ttl, err := expandDuration(d.Get("token_ttl"))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		apiKey, err := mintK8SClusterCredentials(ctx, meta, region, cluster, applicationID.(string), *ttl)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		// The kubeconfig is only rewritten when minting a token, the admin one is kept as returned by the API.
+		token = *apiKey.SecretKey
+		for _, user := range kubeconfig.Users {
+			user.User.Token = token
+		}
+		rawKubeconfig, err := yaml.Marshal(kubeconfig)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		configFile = string(rawKubeconfig)
+		expiresAt = flattenTime(apiKey.ExpiresAt)
+	} else {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Warning,
+			Summary:  "The admin token of the cluster is stored in the state",
+			Detail:   "Set iam_application_id to use a token scoped by the policies of an IAM application and expiring after token_ttl.",
+		})
+	}
+
+	d.SetId(newRegionalIDString(region, clusterID))
+	_ = d.Set("cluster_id", newRegionalIDString(region, clusterID))
+	_ = d.Set("host", host)
+	_ = d.Set("cluster_ca_certificate", ca)
+	_ = d.Set("token", token)
+	_ = d.Set("config_file", configFile)
+	_ = d.Set("expires_at", expiresAt)
+	_ = d.Set("region", region)
+
+	return diags
+}
+
+// mintK8SClusterCredentials returns an API key of the application expiring after ttl, to authenticate to the cluster.
+// The key minted by a previous read of the provider is reused while it is valid for at least half of ttl,
+// and the expired keys minted for the cluster are deleted.
+func mintK8SClusterCredentials(ctx context.Context, meta interface{}, region scw.Region, cluster *k8s.Cluster, applicationID string, ttl time.Duration) (*iam.APIKey, error) {
+	cache := meta.(*Meta).k8sCredentials
+	cacheKey := applicationID + "/" + newRegionalIDString(region, cluster.ID)
+	if apiKey := cache.get(cacheKey, ttl/2); apiKey != nil {
+		return apiKey, nil
+	}
+
+	api := iamAPI(meta)
+	description := k8sClusterCredentialsDescription(region, cluster.ID)
+	err := deleteExpiredK8SClusterCredentials(ctx, api, applicationID, description)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete the expired credentials of cluster %s: %w", cluster.ID, err)
+	}
+
+	apiKey, err := api.CreateAPIKey(&iam.CreateAPIKeyRequest{
+		ApplicationID:    &applicationID,
+		ExpiresAt:        scw.TimePtr(time.Now().Add(ttl)),
+		DefaultProjectID: &cluster.ProjectID,
+		Description:      description,
+	}, scw.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	cache.set(cacheKey, apiKey)
+	return apiKey, nil
+}
@@ -0,0 +1,153 @@
+package scaleway
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	iam "github.com/scaleway/scaleway-sdk-go/api/iam/v1alpha1"
+	"github.com/scaleway/scaleway-sdk-go/api/k8s/v1"
+	"github.com/scaleway/scaleway-sdk-go/scw"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccScalewayDataSourceK8SClusterCredentials_Basic(t *testing.T) {
+	tt := NewTestTools(t)
+	defer tt.Cleanup()
+
+	config := `
+		data "scaleway_k8s_version" "latest" {
+			name = "latest"
+		}
+
+		resource "scaleway_k8s_cluster" "main" {
+			name                        = "tf-tests-k8s-cluster-credentials"
+			version                     = data.scaleway_k8s_version.latest.name
+			cni                         = "cilium"
+			delete_additional_resources = true
+			persist_kubeconfig          = false
+			tags                        = ["terraform-test", "data_scaleway_k8s_cluster_credentials", "basic"]
+		}
+
+		resource "scaleway_iam_application" "main" {
+			name = "tf-tests-k8s-cluster-credentials"
+		}
+
+		data "scaleway_k8s_cluster_credentials" "admin" {
+			cluster_id = scaleway_k8s_cluster.main.id
+		}
+
+		data "scaleway_k8s_cluster_credentials" "application" {
+			cluster_id         = scaleway_k8s_cluster.main.id
+			iam_application_id = scaleway_iam_application.main.id
+			token_ttl          = "30m"
+		}
+	`
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: tt.ProviderFactories,
+		CheckDestroy:      testAccCheckScalewayK8SClusterDestroy(tt),
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckScalewayK8SClusterExists(tt, "scaleway_k8s_cluster.main"),
+					resource.TestCheckNoResourceAttr("scaleway_k8s_cluster.main", "kubeconfig.0.token"),
+					resource.TestCheckResourceAttrPair("data.scaleway_k8s_cluster_credentials.admin", "host", "scaleway_k8s_cluster.main", "apiserver_url"),
+					resource.TestCheckResourceAttrSet("data.scaleway_k8s_cluster_credentials.admin", "token"),
+					resource.TestCheckResourceAttrSet("data.scaleway_k8s_cluster_credentials.admin", "cluster_ca_certificate"),
+					resource.TestCheckResourceAttrSet("data.scaleway_k8s_cluster_credentials.admin", "config_file"),
+					resource.TestCheckResourceAttr("data.scaleway_k8s_cluster_credentials.admin", "expires_at", ""),
+					resource.TestCheckResourceAttrPair("data.scaleway_k8s_cluster_credentials.application", "host", "scaleway_k8s_cluster.main", "apiserver_url"),
+					resource.TestCheckResourceAttrSet("data.scaleway_k8s_cluster_credentials.application", "token"),
+					resource.TestCheckResourceAttrSet("data.scaleway_k8s_cluster_credentials.application", "expires_at"),
+				),
+			},
+		},
+	})
+}
+
+func TestFakeAPI_K8SClusterCredentials(t *testing.T) {
+	tt := NewFakeAPITestTools(t)
+	defer tt.Cleanup()
+	ctx := context.Background()
+
+	cluster, err := k8s.NewAPI(tt.Meta.scwClient).CreateCluster(&k8s.CreateClusterRequest{
+		Region:  scw.RegionFrPar,
+		Name:    "cluster",
+		Version: "1.26.2",
+		Cni:     k8s.CNICilium,
+	})
+	require.NoError(t, err)
+	clusterID := newRegionalIDString(scw.RegionFrPar, cluster.ID)
+
+	// Imported clusters have no persist_kubeconfig in their state, they keep storing the kubeconfig.
+	resource := resourceScalewayK8SCluster()
+	d := resource.Data(nil)
+	d.SetId(clusterID)
+	diags := resource.ReadContext(ctx, d, tt.Meta)
+	require.False(t, diags.HasError(), diags)
+	assert.True(t, d.Get("persist_kubeconfig").(bool))
+	adminToken := d.Get("kubeconfig.0.token").(string)
+	assert.NotEmpty(t, adminToken)
+
+	d = schema.TestResourceDataRaw(t, resource.Schema, map[string]interface{}{"persist_kubeconfig": false})
+	d.SetId(clusterID)
+	diags = resource.ReadContext(ctx, d, tt.Meta)
+	require.False(t, diags.HasError(), diags)
+	assert.False(t, d.Get("persist_kubeconfig").(bool))
+	assert.Empty(t, d.Get("kubeconfig"))
+
+	dataSource := dataSourceScalewayK8SClusterCredentials()
+	d = schema.TestResourceDataRaw(t, dataSource.Schema, map[string]interface{}{"cluster_id": clusterID})
+	diags = dataSource.ReadContext(ctx, d, tt.Meta)
+	require.False(t, diags.HasError(), diags)
+	require.Len(t, diags, 1)
+	assert.Equal(t, "The admin token of the cluster is stored in the state", diags[0].Summary)
+	assert.Equal(t, clusterID, d.Id())
+	assert.Equal(t, cluster.ClusterURL, d.Get("host"))
+	assert.NotEmpty(t, d.Get("cluster_ca_certificate"))
+	assert.NotEmpty(t, d.Get("token"))
+	assert.Contains(t, d.Get("config_file"), d.Get("token"))
+	assert.Empty(t, d.Get("expires_at"))
+
+	// A key minted by a previous run has expired.
+	api := iamAPI(tt.Meta)
+	expiredAPIKey, err := api.CreateAPIKey(&iam.CreateAPIKeyRequest{
+		ApplicationID: scw.StringPtr("11111111-1111-1111-1111-111111111111"),
+		ExpiresAt:     scw.TimePtr(time.Now().Add(-time.Hour)),
+		Description:   k8sClusterCredentialsDescription(scw.RegionFrPar, cluster.ID),
+	})
+	require.NoError(t, err)
+
+	readScopedCredentials := func() *schema.ResourceData {
+		d := schema.TestResourceDataRaw(t, dataSource.Schema, map[string]interface{}{
+			"cluster_id":         clusterID,
+			"iam_application_id": "11111111-1111-1111-1111-111111111111",
+			"token_ttl":          "30m",
+		})
+		diags := dataSource.ReadContext(ctx, d, tt.Meta)
+		require.False(t, diags.HasError(), diags)
+		assert.Empty(t, diags)
+		return d
+	}
+	d = readScopedCredentials()
+	token := d.Get("token").(string)
+	assert.NotEqual(t, adminToken, token)
+	assert.Contains(t, d.Get("config_file"), "token: "+token)
+	assert.Contains(t, d.Get("config_file"), "server: "+cluster.ClusterURL)
+	expiresAt, err := time.Parse(time.RFC3339, d.Get("expires_at").(string))
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(30*time.Minute), expiresAt, time.Minute)
+
+	// The key is reused by the next reads while it is valid, the expired one is deleted.
+	assert.Equal(t, token, readScopedCredentials().Get("token"))
+	apiKeys, err := api.ListAPIKeys(&iam.ListAPIKeysRequest{ApplicationID: scw.StringPtr("11111111-1111-1111-1111-111111111111")}, scw.WithAllPages())
+	require.NoError(t, err)
+	require.Len(t, apiKeys.APIKeys, 1)
+	assert.NotEqual(t, expiredAPIKey.AccessKey, apiKeys.APIKeys[0].AccessKey)
+}
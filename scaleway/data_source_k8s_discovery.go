@@ -0,0 +1,47 @@
+package scaleway
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/scaleway/scaleway-sdk-go/api/k8s/v1"
+	"github.com/scaleway/scaleway-sdk-go/scw"
+)
+
+func dataSourceScalewayK8SDiscovery() *schema.Resource {
+	return dataSourceScalewayDiscovery("region", []string{
+		"scaleway_k8s_cluster",
+		"scaleway_k8s_pool",
+	}, discoverK8SResources)
+}
+
+func discoverK8SResources(ctx context.Context, meta *Meta, locality string, projectID *string) ([]discoveredResource, error) {
+	k8sAPI := k8s.NewAPI(meta.scwClient)
+	region := scw.Region(locality)
+	var resources []discoveredResource
+
+	clusters, err := k8sAPI.ListClusters(&k8s.ListClustersRequest{
+		Region:    region,
+		ProjectID: projectID,
+	}, scw.WithContext(ctx), scw.WithAllPages())
+	if err != nil {
+		return nil, err
+	}
+	for _, cluster := range clusters.Clusters {
+		resources = append(resources, discoveredResource{Type: "scaleway_k8s_cluster", ID: newRegionalIDString(region, cluster.ID), Name: cluster.Name})
+
+		pools, err := k8sAPI.ListPools(&k8s.ListPoolsRequest{
+			Region:    region,
+			ClusterID: cluster.ID,
+		}, scw.WithContext(ctx), scw.WithAllPages())
+		if err != nil {
+			return nil, err
+		}
+		for _, pool := range pools.Pools {
+			// Pool names are only unique within a cluster.
+			resources = append(resources, discoveredResource{Type: "scaleway_k8s_pool", ID: newRegionalIDString(region, pool.ID), Name: cluster.Name + "/" + pool.Name})
+		}
+	}
+
+	return resources, nil
+}
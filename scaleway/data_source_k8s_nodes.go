@@ -0,0 +1,172 @@
+package scaleway
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/scaleway/scaleway-sdk-go/api/instance/v1"
+	ipam "github.com/scaleway/scaleway-sdk-go/api/ipam/v1alpha1"
+	"github.com/scaleway/scaleway-sdk-go/api/k8s/v1"
+	"github.com/scaleway/scaleway-sdk-go/scw"
+)
+
+func dataSourceScalewayK8SNodes() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceScalewayK8SNodesRead,
+		Schema: map[string]*schema.Schema{
+			"cluster_id": {
+				Type:         schema.TypeString,
+				Required:     true,
+				Description:  "The ID of the cluster the nodes belong to",
+				ValidateFunc: validationUUIDorUUIDWithLocality(),
+			},
+			"pool_id": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  "Nodes of this pool are listed.",
+				ValidateFunc: validationUUIDorUUIDWithLocality(),
+			},
+			"name": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Nodes with a name like it are listed.",
+			},
+			"status": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Nodes with this status are listed.",
+				ValidateFunc: validation.StringInSlice([]string{
+					k8s.NodeStatusCreating.String(),
+					k8s.NodeStatusNotReady.String(),
+					k8s.NodeStatusReady.String(),
+					k8s.NodeStatusDeleting.String(),
+					k8s.NodeStatusDeleted.String(),
+					k8s.NodeStatusLocked.String(),
+					k8s.NodeStatusRebooting.String(),
+					k8s.NodeStatusCreationError.String(),
+					k8s.NodeStatusUpgrading.String(),
+					k8s.NodeStatusStarting.String(),
+					k8s.NodeStatusRegistering.String(),
+				}, false),
+			},
+			"nodes": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The ID of the node",
+						},
+						"name": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The name of the node",
+						},
+						"pool_id": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The ID of the pool of the node",
+						},
+						"status": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The status of the node",
+						},
+						"provider_id": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The provider ID of the node, e.g. scaleway://instance/fr-par-1/<server-id>",
+						},
+						"public_ip": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The public IPv4 address of the node",
+						},
+						"public_ip_v6": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The public IPv6 address of the node",
+						},
+						"private_ips": {
+							Type:        schema.TypeList,
+							Computed:    true,
+							Description: "The private IP addresses of the node",
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
+						"error_message": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The details of the error of the node, if any",
+						},
+						"conditions": {
+							Type:        schema.TypeMap,
+							Computed:    true,
+							Description: "The conditions of the node, e.g. the ones of the node problem detector",
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
+						"created_at": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The date and time of the creation of the node",
+						},
+						"updated_at": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The date and time of the last update of the node",
+						},
+					},
+				},
+			},
+			"region": regionSchema(),
+		},
+	}
+}
+
+func dataSourceScalewayK8SNodesRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	k8sAPI, region, err := k8sAPIWithRegion(d, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	instanceAPI := instance.NewAPI(meta.(*Meta).scwClient)
+	ipamAPI := ipam.NewAPI(meta.(*Meta).scwClient)
+	clusterID := expandID(d.Get("cluster_id"))
+
+	req := &k8s.ListNodesRequest{
+		Region:    region,
+		ClusterID: clusterID,
+		Name:      expandStringPtr(d.Get("name")),
+		Status:    k8s.NodeStatus(d.Get("status").(string)),
+	}
+	if poolID, ok := d.GetOk("pool_id"); ok {
+		req.PoolID = scw.StringPtr(expandID(poolID))
+	}
+
+	res, err := k8sAPI.ListNodes(req, scw.WithAllPages(), scw.WithContext(ctx))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	nodes := []interface{}(nil)
+	for _, node := range res.Nodes {
+		privateIPs, err := k8sNodePrivateIPs(ctx, instanceAPI, ipamAPI, node)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		nodes = append(nodes, flattenK8SNode(node, privateIPs))
+	}
+
+	d.SetId(newRegionalIDString(region, clusterID))
+	_ = d.Set("cluster_id", newRegionalIDString(region, clusterID))
+	_ = d.Set("nodes", nodes)
+	_ = d.Set("region", region)
+
+	return nil
+}
@@ -0,0 +1,56 @@
+package scaleway
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/scaleway/scaleway-sdk-go/api/k8s/v1"
+	"github.com/scaleway/scaleway-sdk-go/scw"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFakeAPI_K8SNodes(t *testing.T) {
+	tt := NewFakeAPITestTools(t)
+	defer tt.Cleanup()
+	ctx := context.Background()
+	k8sAPI := k8s.NewAPI(tt.Meta.scwClient)
+
+	cluster, err := k8sAPI.CreateCluster(&k8s.CreateClusterRequest{
+		Region:  scw.RegionFrPar,
+		Name:    "cluster",
+		Version: "1.26.2",
+		Cni:     k8s.CNICilium,
+		Pools: []*k8s.CreateClusterRequestPoolConfig{
+			{Name: "default", NodeType: "DEV1-M", Size: 2},
+			{Name: "gpu", NodeType: "GPU-3070-S", Size: 1},
+		},
+	})
+	require.NoError(t, err)
+	clusterID := newRegionalIDString(scw.RegionFrPar, cluster.ID)
+	pools, err := k8sAPI.ListPools(&k8s.ListPoolsRequest{Region: scw.RegionFrPar, ClusterID: cluster.ID, Name: scw.StringPtr("gpu")})
+	require.NoError(t, err)
+	require.Len(t, pools.Pools, 1)
+	gpuPoolID := newRegionalIDString(scw.RegionFrPar, pools.Pools[0].ID)
+
+	dataSource := dataSourceScalewayK8SNodes()
+	d := schema.TestResourceDataRaw(t, dataSource.Schema, map[string]interface{}{"cluster_id": clusterID})
+	diags := dataSource.ReadContext(ctx, d, tt.Meta)
+	require.False(t, diags.HasError(), diags)
+	assert.Equal(t, 3, d.Get("nodes.#"))
+	assert.Regexp(t, `^scaleway://instance/fr-par-1/`, d.Get("nodes.0.provider_id"))
+	assert.Regexp(t, `^10\.`, d.Get("nodes.0.private_ips.0"))
+	assert.Equal(t, "ready", d.Get("nodes.0.status"))
+
+	d = schema.TestResourceDataRaw(t, dataSource.Schema, map[string]interface{}{"cluster_id": clusterID, "pool_id": gpuPoolID})
+	diags = dataSource.ReadContext(ctx, d, tt.Meta)
+	require.False(t, diags.HasError(), diags)
+	require.Equal(t, 1, d.Get("nodes.#"))
+	assert.Equal(t, gpuPoolID, d.Get("nodes.0.pool_id"))
+
+	d = schema.TestResourceDataRaw(t, dataSource.Schema, map[string]interface{}{"cluster_id": clusterID, "status": "not_ready"})
+	diags = dataSource.ReadContext(ctx, d, tt.Meta)
+	require.False(t, diags.HasError(), diags)
+	assert.Equal(t, 0, d.Get("nodes.#"))
+}
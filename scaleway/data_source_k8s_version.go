@@ -43,6 +43,14 @@ func dataSourceScalewayK8SVersion() *schema.Resource {
 				},
 				Description: "The list of supported feature gates for this version",
 			},
+			"available_upgrades": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Description: "The list of versions a cluster can be upgraded to from this version in a single step",
+			},
 			"region": regionSchema(),
 		},
 	}
@@ -61,18 +69,19 @@ func dataSourceScalewayK8SVersionRead(ctx context.Context, d *schema.ResourceDat
 
 	var version *k8s.Version
 
+	versions, err := k8sAPI.ListVersions(&k8s.ListVersionsRequest{
+		Region: region,
+	}, scw.WithContext(ctx))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
 	if name == "latest" {
-		res, err := k8sAPI.ListVersions(&k8s.ListVersionsRequest{
-			Region: region,
-		}, scw.WithContext(ctx))
-		if err != nil {
-			return diag.FromErr(err)
-		}
-		if len(res.Versions) == 0 {
+		if len(versions.Versions) == 0 {
 			return diag.FromErr(fmt.Errorf("could not find the latest version"))
 		}
 
-		version = res.Versions[0]
+		version = versions.Versions[0]
 	} else {
 		res, err := k8sAPI.GetVersion(&k8s.GetVersionRequest{
 			Region:      region,
@@ -84,11 +93,27 @@ func dataSourceScalewayK8SVersionRead(ctx context.Context, d *schema.ResourceDat
 		version = res
 	}
 
+	parsedVersion, err := k8sParseVersion(version.Name)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	availableUpgrades := []string(nil)
+	for _, v := range versions.Versions {
+		target, err := k8sParseVersion(v.Name)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		if parsedVersion.canUpgradeTo(target) {
+			availableUpgrades = append(availableUpgrades, v.Name)
+		}
+	}
+
 	d.SetId(fmt.Sprintf("%s/%s", region, version.Name))
 	_ = d.Set("name", version.Name)
 	_ = d.Set("available_cnis", version.AvailableCnis)
 	_ = d.Set("available_container_runtimes", version.AvailableContainerRuntimes)
 	_ = d.Set("available_feature_gates", version.AvailableFeatureGates)
+	_ = d.Set("available_upgrades", availableUpgrades)
 	_ = d.Set("region", region)
 
 	return nil
@@ -0,0 +1,46 @@
+package scaleway
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	lbSDK "github.com/scaleway/scaleway-sdk-go/api/lb/v1"
+	"github.com/scaleway/scaleway-sdk-go/scw"
+)
+
+func dataSourceScalewayLbDiscovery() *schema.Resource {
+	return dataSourceScalewayDiscovery("zone", []string{
+		"scaleway_lb",
+		"scaleway_lb_ip",
+	}, discoverLbResources)
+}
+
+func discoverLbResources(ctx context.Context, meta *Meta, locality string, projectID *string) ([]discoveredResource, error) {
+	lbAPI := lbSDK.NewZonedAPI(meta.scwClient)
+	zone := scw.Zone(locality)
+	var resources []discoveredResource
+
+	lbs, err := lbAPI.ListLBs(&lbSDK.ZonedAPIListLBsRequest{
+		Zone:      zone,
+		ProjectID: projectID,
+	}, scw.WithContext(ctx), scw.WithAllPages())
+	if err != nil {
+		return nil, err
+	}
+	for _, lb := range lbs.LBs {
+		resources = append(resources, discoveredResource{Type: "scaleway_lb", ID: newZonedIDString(zone, lb.ID), Name: lb.Name})
+	}
+
+	ips, err := lbAPI.ListIPs(&lbSDK.ZonedAPIListIPsRequest{
+		Zone:      zone,
+		ProjectID: projectID,
+	}, scw.WithContext(ctx), scw.WithAllPages())
+	if err != nil {
+		return nil, err
+	}
+	for _, ip := range ips.IPs {
+		resources = append(resources, discoveredResource{Type: "scaleway_lb_ip", ID: newZonedIDString(zone, ip.ID), Name: ip.IPAddress})
+	}
+
+	return resources, nil
+}
@@ -0,0 +1,32 @@
+package scaleway
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/scaleway/scaleway-sdk-go/api/rdb/v1"
+	"github.com/scaleway/scaleway-sdk-go/scw"
+)
+
+func dataSourceScalewayRDBDiscovery() *schema.Resource {
+	return dataSourceScalewayDiscovery("region", []string{
+		"scaleway_rdb_instance",
+	}, discoverRDBResources)
+}
+
+func discoverRDBResources(ctx context.Context, meta *Meta, locality string, projectID *string) ([]discoveredResource, error) {
+	region := scw.Region(locality)
+	instances, err := newRdbAPI(meta).ListInstances(&rdb.ListInstancesRequest{
+		Region:    region,
+		ProjectID: projectID,
+	}, scw.WithContext(ctx), scw.WithAllPages())
+	if err != nil {
+		return nil, err
+	}
+
+	var resources []discoveredResource
+	for _, instance := range instances.Instances {
+		resources = append(resources, discoveredResource{Type: "scaleway_rdb_instance", ID: newRegionalIDString(region, instance.ID), Name: instance.Name})
+	}
+	return resources, nil
+}
@@ -0,0 +1,32 @@
+package scaleway
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/scaleway/scaleway-sdk-go/api/registry/v1"
+	"github.com/scaleway/scaleway-sdk-go/scw"
+)
+
+func dataSourceScalewayRegistryDiscovery() *schema.Resource {
+	return dataSourceScalewayDiscovery("region", []string{
+		"scaleway_registry_namespace",
+	}, discoverRegistryResources)
+}
+
+func discoverRegistryResources(ctx context.Context, meta *Meta, locality string, projectID *string) ([]discoveredResource, error) {
+	region := scw.Region(locality)
+	namespaces, err := registry.NewAPI(meta.scwClient).ListNamespaces(&registry.ListNamespacesRequest{
+		Region:    region,
+		ProjectID: projectID,
+	}, scw.WithContext(ctx), scw.WithAllPages())
+	if err != nil {
+		return nil, err
+	}
+
+	var resources []discoveredResource
+	for _, namespace := range namespaces.Namespaces {
+		resources = append(resources, discoveredResource{Type: "scaleway_registry_namespace", ID: newRegionalIDString(region, namespace.ID), Name: namespace.Name})
+	}
+	return resources, nil
+}
@@ -0,0 +1,32 @@
+package scaleway
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/scaleway/scaleway-sdk-go/api/vpc/v1"
+	"github.com/scaleway/scaleway-sdk-go/scw"
+)
+
+func dataSourceScalewayVPCDiscovery() *schema.Resource {
+	return dataSourceScalewayDiscovery("zone", []string{
+		"scaleway_vpc_private_network",
+	}, discoverVPCResources)
+}
+
+func discoverVPCResources(ctx context.Context, meta *Meta, locality string, projectID *string) ([]discoveredResource, error) {
+	zone := scw.Zone(locality)
+	privateNetworks, err := vpc.NewAPI(meta.scwClient).ListPrivateNetworks(&vpc.ListPrivateNetworksRequest{
+		Zone:      zone,
+		ProjectID: projectID,
+	}, scw.WithContext(ctx), scw.WithAllPages())
+	if err != nil {
+		return nil, err
+	}
+
+	var resources []discoveredResource
+	for _, pn := range privateNetworks.PrivateNetworks {
+		resources = append(resources, discoveredResource{Type: "scaleway_vpc_private_network", ID: newZonedIDString(zone, pn.ID), Name: pn.Name})
+	}
+	return resources, nil
+}
@@ -0,0 +1,32 @@
+package scaleway
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFakeAPI_VPCDiscovery(t *testing.T) {
+	tt := NewFakeAPITestTools(t)
+	defer tt.Cleanup()
+	ctx := context.Background()
+
+	for _, name := range []string{"b-network", "a-network"} {
+		d := schema.TestResourceDataRaw(t, resourceScalewayVPCPrivateNetwork().Schema, map[string]interface{}{"name": name})
+		diags := resourceScalewayVPCPrivateNetworkCreate(ctx, d, tt.Meta)
+		require.False(t, diags.HasError(), diags)
+	}
+
+	dataSource := dataSourceScalewayVPCDiscovery()
+	d := schema.TestResourceDataRaw(t, dataSource.Schema, map[string]interface{}{})
+	diags := dataSource.ReadContext(ctx, d, tt.Meta)
+	require.False(t, diags.HasError(), diags)
+	assert.Equal(t, "fr-par-1", d.Id())
+	resources := d.Get("resources").([]interface{})
+	require.Len(t, resources, 2)
+	assert.Equal(t, "a-network", resources[0].(map[string]interface{})["name"])
+	assert.Equal(t, "scaleway_vpc_private_network", resources[0].(map[string]interface{})["type"])
+}
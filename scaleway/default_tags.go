@@ -0,0 +1,238 @@
+package scaleway
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+)
+
+// defaultTagsSchema returns the provider level default_tags block.
+func defaultTagsSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Optional:    true,
+		MaxItems:    1,
+		Description: "Configuration block with tags applied to every resource that supports tags.",
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"tags": {
+					Type:        schema.TypeMap,
+					Optional:    true,
+					Description: "Tags merged into the tags of every resource. Resource level tags take precedence.",
+					Elem: &schema.Schema{
+						Type: schema.TypeString,
+					},
+				},
+			},
+		},
+	}
+}
+
+// tagsAllSchema returns the computed attribute holding list tags merged with the provider default tags.
+func tagsAllSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Computed:    true,
+		Description: "All the tags of the resource, including the ones inherited from the provider default_tags",
+		Elem: &schema.Schema{
+			Type: schema.TypeString,
+		},
+	}
+}
+
+// tagsAllMapSchema returns the computed attribute holding map tags merged with the provider default tags.
+func tagsAllMapSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeMap,
+		Computed:    true,
+		Description: "All the tags of the resource, including the ones inherited from the provider default_tags",
+		Elem: &schema.Schema{
+			Type: schema.TypeString,
+		},
+	}
+}
+
+func expandDefaultTags(d *schema.ResourceData) map[string]string {
+	if d == nil {
+		return nil
+	}
+	rawDefaultTags, ok := d.GetOk("default_tags.0.tags")
+	if !ok {
+		return nil
+	}
+	defaultTags := map[string]string{}
+	for key, value := range rawDefaultTags.(map[string]interface{}) {
+		defaultTags[key] = value.(string)
+	}
+	return defaultTags
+}
+
+// tagKey returns the key part of a "key=value" tag.
+func tagKey(tag string) string {
+	key, _, _ := strings.Cut(tag, "=")
+	return key
+}
+
+// flattenDefaultTags converts default tags to the "key=value" list form used by most Scaleway APIs.
+// Tags with an empty value are converted to "key".
+func flattenDefaultTags(defaultTags map[string]string) []string {
+	tags := make([]string, 0, len(defaultTags))
+	for key, value := range defaultTags {
+		if value == "" {
+			tags = append(tags, key)
+		} else {
+			tags = append(tags, key+"="+value)
+		}
+	}
+	sort.Strings(tags)
+	return tags
+}
+
+// mergeTags merges list tags with default tags, a resource tag with the same key overrides the default one.
+func mergeTags(defaultTags map[string]string, tags []string) []string {
+	if len(defaultTags) == 0 {
+		return tags
+	}
+	keys := make(map[string]struct{}, len(tags))
+	merged := []string(nil)
+	for _, tag := range tags {
+		keys[tagKey(tag)] = struct{}{}
+		merged = append(merged, tag)
+	}
+	for _, tag := range flattenDefaultTags(defaultTags) {
+		if _, exists := keys[tagKey(tag)]; !exists {
+			merged = append(merged, tag)
+		}
+	}
+	return merged
+}
+
+// mergeMapTags merges map tags with default tags, a resource tag with the same key overrides the default one.
+func mergeMapTags(defaultTags map[string]string, tags map[string]string) map[string]string {
+	if len(defaultTags) == 0 {
+		return tags
+	}
+	merged := make(map[string]string, len(defaultTags)+len(tags))
+	for key, value := range defaultTags {
+		merged[key] = value
+	}
+	for key, value := range tags {
+		merged[key] = value
+	}
+	return merged
+}
+
+// removeDefaultTags removes from tags returned by the API the ones that were only inherited from default tags.
+func removeDefaultTags(defaultTags map[string]string, configuredTags []string, tags []string) []string {
+	if len(defaultTags) == 0 {
+		return tags
+	}
+	inherited := map[string]struct{}{}
+	for _, tag := range flattenDefaultTags(defaultTags) {
+		inherited[tag] = struct{}{}
+	}
+	for _, tag := range configuredTags {
+		delete(inherited, tag)
+	}
+	filtered := []string(nil)
+	for _, tag := range tags {
+		if _, isInherited := inherited[tag]; !isInherited {
+			filtered = append(filtered, tag)
+		}
+	}
+	return filtered
+}
+
+// removeDefaultMapTags is the map equivalent of removeDefaultTags.
+func removeDefaultMapTags(defaultTags map[string]string, configuredTags map[string]string, tags map[string]string) map[string]string {
+	if len(defaultTags) == 0 {
+		return tags
+	}
+	filtered := map[string]string{}
+	for key, value := range tags {
+		defaultValue, isDefault := defaultTags[key]
+		_, isConfigured := configuredTags[key]
+		if isDefault && defaultValue == value && !isConfigured {
+			continue
+		}
+		filtered[key] = value
+	}
+	return filtered
+}
+
+func expandTagsMap(data interface{}) map[string]string {
+	tags := map[string]string{}
+	if rawTags, ok := data.(map[string]interface{}); ok {
+		for key, value := range rawTags {
+			tags[key] = value.(string)
+		}
+	}
+	return tags
+}
+
+func metaDefaultTags(meta interface{}) map[string]string {
+	if m, ok := meta.(*Meta); ok && m != nil {
+		return m.defaultTags
+	}
+	return nil
+}
+
+// expandTagsAll returns the resource tags merged with the provider default tags.
+func expandTagsAll(d terraformResourceData, meta interface{}) []string {
+	return mergeTags(metaDefaultTags(meta), expandStrings(d.Get("tags")))
+}
+
+// expandUpdatedTagsAll is the update equivalent of expandTagsAll, it defaults to an empty list so tags can be removed.
+func expandUpdatedTagsAll(d terraformResourceData, meta interface{}) *[]string {
+	tags := expandTagsAll(d, meta)
+	if tags == nil {
+		tags = []string{}
+	}
+	return &tags
+}
+
+// expandMapTagsAll returns the resource map tags merged with the provider default tags.
+func expandMapTagsAll(d terraformResourceData, meta interface{}) map[string]string {
+	return mergeMapTags(metaDefaultTags(meta), expandTagsMap(d.Get("tags")))
+}
+
+// setTags sets tags and tags_all from the tags returned by the API.
+// Tags inherited from the provider default tags are only set in tags_all to avoid a perpetual diff.
+func setTags(d *schema.ResourceData, meta interface{}, tags []string) {
+	_ = d.Set("tags", removeDefaultTags(metaDefaultTags(meta), expandStrings(d.Get("tags")), tags))
+	_ = d.Set("tags_all", tags)
+}
+
+// setMapTags is the map equivalent of setTags.
+func setMapTags(d *schema.ResourceData, meta interface{}, tags map[string]string) {
+	_ = d.Set("tags", removeDefaultMapTags(metaDefaultTags(meta), expandTagsMap(d.Get("tags")), tags))
+	_ = d.Set("tags_all", tags)
+}
+
+// customizeDiffTagsAll computes tags_all during plan so changes to the provider default tags are shown.
+func customizeDiffTagsAll(_ context.Context, diff *schema.ResourceDiff, meta interface{}) error {
+	if !diff.NewValueKnown("tags") {
+		return diff.SetNewComputed("tags_all")
+	}
+	tagsAll := expandTagsAll(diff, meta)
+	if !diff.HasChange("tags") && slices.Equal(expandStrings(diff.Get("tags_all")), tagsAll) {
+		return nil
+	}
+	return diff.SetNew("tags_all", tagsAll)
+}
+
+// customizeDiffMapTagsAll is the map equivalent of customizeDiffTagsAll.
+func customizeDiffMapTagsAll(_ context.Context, diff *schema.ResourceDiff, meta interface{}) error {
+	if !diff.NewValueKnown("tags") {
+		return diff.SetNewComputed("tags_all")
+	}
+	tagsAll := expandMapTagsAll(diff, meta)
+	if !diff.HasChange("tags") && maps.Equal(expandTagsMap(diff.Get("tags_all")), tagsAll) {
+		return nil
+	}
+	return diff.SetNew("tags_all", tagsAll)
+}
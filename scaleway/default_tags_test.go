@@ -0,0 +1,57 @@
+package scaleway
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergeTags(t *testing.T) {
+	testCases := []struct {
+		name        string
+		defaultTags map[string]string
+		tags        []string
+		expected    []string
+	}{
+		{
+			name:     "no default tags",
+			tags:     []string{"foo", "bar=baz"},
+			expected: []string{"foo", "bar=baz"},
+		},
+		{
+			name:        "default tags only",
+			defaultTags: map[string]string{"team": "platform", "env": "prod", "flag": ""},
+			expected:    []string{"env=prod", "flag", "team=platform"},
+		},
+		{
+			name:        "resource tags take precedence",
+			defaultTags: map[string]string{"team": "platform", "env": "prod"},
+			tags:        []string{"env=staging", "foo"},
+			expected:    []string{"env=staging", "foo", "team=platform"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, mergeTags(tc.defaultTags, tc.tags))
+		})
+	}
+}
+
+func TestRemoveDefaultTags(t *testing.T) {
+	defaultTags := map[string]string{"team": "platform", "env": "prod"}
+
+	assert.Equal(t, []string{"foo"}, removeDefaultTags(defaultTags, []string{"foo"}, []string{"foo", "env=prod", "team=platform"}))
+	assert.Equal(t, []string{"foo", "env=prod"}, removeDefaultTags(defaultTags, []string{"foo", "env=prod"}, []string{"foo", "env=prod", "team=platform"}))
+	assert.Equal(t, []string{"env=staging"}, removeDefaultTags(defaultTags, []string{"env=staging"}, []string{"env=staging", "team=platform"}))
+}
+
+func TestMergeAndRemoveDefaultMapTags(t *testing.T) {
+	defaultTags := map[string]string{"team": "platform", "env": "prod"}
+	tags := map[string]string{"env": "staging", "foo": "bar"}
+
+	merged := mergeMapTags(defaultTags, tags)
+	assert.Equal(t, map[string]string{"team": "platform", "env": "staging", "foo": "bar"}, merged)
+	assert.Equal(t, tags, removeDefaultMapTags(defaultTags, tags, merged))
+	assert.Equal(t, map[string]string{"env": "staging", "foo": "bar"}, removeDefaultMapTags(defaultTags, map[string]string{}, merged))
+}
@@ -0,0 +1,145 @@
+package scaleway
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/scaleway/scaleway-sdk-go/scw"
+	"github.com/scaleway/terraform-provider-scaleway/v2/internal/fakeapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// NewFakeAPITestTools returns test tools sending every request to an in-process fake Scaleway API.
+// Unlike NewTestTools, it does not need cassettes nor credentials.
+func NewFakeAPITestTools(t *testing.T) *TestTools {
+	t.Helper()
+	// The S3 client can only load a custom CA bundle in an *http.Transport, which the fake API client is not.
+	t.Setenv("AWS_CA_BUNDLE", "")
+	server := fakeapi.NewServer()
+	// The fake API applies changes synchronously, there is nothing to wait for between retries.
+	noRetryInterval := time.Duration(0)
+	DefaultWaitRetryInterval = &noRetryInterval
+
+	meta, err := buildMeta(context.Background(), &metaConfig{
+		terraformVersion: "terraform-tests",
+		forceZone:        scw.ZoneFrPar1,
+		forceProjectID:   fakeapi.DefaultProjectID,
+		forceAccessKey:   "SCWXXXXXXXXXXXXXXXXX",
+		forceSecretKey:   "11111111-1111-1111-1111-111111111111",
+		forceAPIURL:      server.APIURL(),
+		httpClient:       server.Client(),
+		kubeHTTPClient:   server.Client(),
+	})
+	require.NoError(t, err)
+
+	return &TestTools{
+		T:    t,
+		Meta: meta,
+		ProviderFactories: map[string]func() (*schema.Provider, error){
+			"scaleway": func() (*schema.Provider, error) {
+				return Provider(&ProviderConfig{Meta: meta})(), nil
+			},
+		},
+		ProtoV5ProviderFactories: protoV5ProviderFactories(meta),
+		Cleanup:                  server.Close,
+	}
+}
+
+// testFakeAPILifecycle creates, reads and deletes a resource against the fake API, returning its state after creation.
+func testFakeAPILifecycle(t *testing.T, tt *TestTools, resource *schema.Resource, raw map[string]interface{}) *schema.ResourceData {
+	t.Helper()
+	ctx := context.Background()
+
+	d := schema.TestResourceDataRaw(t, resource.Schema, raw)
+	diags := resource.CreateContext(ctx, d, tt.Meta)
+	require.False(t, diags.HasError(), diags)
+	require.NotEmpty(t, d.Id())
+
+	id := d.Id()
+	diags = resource.ReadContext(ctx, d, tt.Meta)
+	require.False(t, diags.HasError(), diags)
+	require.Equal(t, id, d.Id())
+
+	deleted := schema.TestResourceDataRaw(t, resource.Schema, raw)
+	deleted.SetId(id)
+	diags = resource.DeleteContext(ctx, deleted, tt.Meta)
+	require.False(t, diags.HasError(), diags)
+
+	return d
+}
+
+func TestFakeAPI_VPCPrivateNetwork(t *testing.T) {
+	tt := NewFakeAPITestTools(t)
+	defer tt.Cleanup()
+
+	d := testFakeAPILifecycle(t, tt, resourceScalewayVPCPrivateNetwork(), map[string]interface{}{
+		"name": "private-network",
+		"tags": []interface{}{"foo", "bar"},
+	})
+	assert.Equal(t, "private-network", d.Get("name"))
+	assert.Equal(t, []interface{}{"foo", "bar"}, d.Get("tags"))
+	assert.Equal(t, fakeapi.DefaultProjectID, d.Get("project_id"))
+	assert.Equal(t, "fr-par-1", d.Get("zone"))
+
+	// Reading a deleted private network removes it from the state.
+	diags := resourceScalewayVPCPrivateNetworkRead(context.Background(), d, tt.Meta)
+	require.False(t, diags.HasError(), diags)
+	assert.Empty(t, d.Id())
+}
+
+func TestFakeAPI_InstanceIP(t *testing.T) {
+	tt := NewFakeAPITestTools(t)
+	defer tt.Cleanup()
+
+	d := testFakeAPILifecycle(t, tt, resourceScalewayInstanceIP(), map[string]interface{}{})
+	assert.NotEmpty(t, d.Get("address"))
+}
+
+func TestFakeAPI_ObjectBucket(t *testing.T) {
+	tt := NewFakeAPITestTools(t)
+	defer tt.Cleanup()
+
+	d := testFakeAPILifecycle(t, tt, resourceScalewayObjectBucket(), map[string]interface{}{
+		"name": "test-bucket",
+		"tags": map[string]interface{}{"foo": "bar"},
+	})
+	assert.Equal(t, "test-bucket", d.Get("name"))
+	assert.Equal(t, "https://test-bucket.s3.fr-par.scw.cloud", d.Get("endpoint"))
+}
+
+// testPlanResource validates and plans a resource with the given configuration, the attributes that are not given are null.
+func testPlanResource(t *testing.T, r *schema.Resource, state *terraform.InstanceState, meta interface{}, config map[string]cty.Value) (*terraform.InstanceDiff, error) {
+	t.Helper()
+	coreSchema := r.CoreConfigSchema()
+	configValue, err := coreSchema.CoerceValue(cty.ObjectVal(config))
+	require.NoError(t, err)
+
+	// The raw values are set by the plugin protocol before planning.
+	stateValue := cty.NullVal(coreSchema.ImpliedType())
+	if state != nil {
+		stateValue, err = state.AttrsAsObjectValue(coreSchema.ImpliedType())
+		require.NoError(t, err)
+		state = state.DeepCopy()
+	} else {
+		state = &terraform.InstanceState{}
+	}
+	state.RawState = stateValue
+	state.RawConfig = configValue
+
+	// The configuration is validated before planning, like schema.Provider.ValidateResource does.
+	resourceConfig := terraform.NewResourceConfigShimmed(configValue, coreSchema)
+	for _, d := range r.Validate(resourceConfig) {
+		if d.Severity == diag.Error {
+			return nil, fmt.Errorf("%s: %s", d.Summary, d.Detail)
+		}
+	}
+
+	return r.Diff(context.Background(), state, resourceConfig, meta)
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"net"
 	"net/http"
+	"sort"
 	"strconv"
 	"strings"
 	"testing"
@@ -267,6 +268,110 @@ func extractProjectID(d terraformResourceData, meta *Meta) (projectID string, is
 	return "", false, ErrProjectIDNotFound
 }
 
+// importByNamePrefix prefixes the name of the resource in import IDs such as fr-par-1/name:my-server.
+const importByNamePrefix = "name:"
+
+// parseImportByNameID parses an import ID such as fr-par-1/name:my-server or name:my-server and returns its locality and name.
+// ok is false when the import ID does not reference the resource by its name.
+func parseImportByNameID(importID string) (locality string, name string, ok bool) {
+	locality, name, found := strings.Cut(importID, "/")
+	if !found || strings.HasPrefix(importID, importByNamePrefix) {
+		locality, name = "", importID
+	}
+	if !strings.HasPrefix(name, importByNamePrefix) || name == importByNamePrefix {
+		return "", "", false
+	}
+	return locality, strings.TrimPrefix(name, importByNamePrefix), true
+}
+
+// importByNameResolver returns the ID of the resource named name in the given locality, which is empty when not set in the import ID.
+type importByNameResolver func(ctx context.Context, d *schema.ResourceData, meta *Meta, locality string, name string) (string, error)
+
+// importStateByName returns an importer resolving import IDs such as fr-par-1/name:my-server with resolveID.
+// Other import IDs are imported as is.
+func importStateByName(resolveID importByNameResolver) schema.StateContextFunc {
+	return func(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+		locality, name, ok := parseImportByNameID(d.Id())
+		if !ok {
+			return []*schema.ResourceData{d}, nil
+		}
+
+		id, err := resolveID(ctx, d, m.(*Meta), locality, name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to import %s: %w", d.Id(), err)
+		}
+		d.SetId(id)
+		return []*schema.ResourceData{d}, nil
+	}
+}
+
+// addImportDefaults makes the importer of resource set the default value of the attributes missing from the imported state.
+// Attributes such as wait_for_pool_ready cannot be read from the API and would otherwise show up as changes after an import.
+func addImportDefaults(resource *schema.Resource) {
+	if resource.Importer == nil || resource.Importer.StateContext == nil {
+		return
+	}
+
+	importState := resource.Importer.StateContext
+	resource.Importer.StateContext = func(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+		states, err := importState(ctx, d, m)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, state := range states {
+			for key, attribute := range resource.Schema {
+				if _, isSet := state.GetOk(key); isSet {
+					continue
+				}
+				defaultValue, err := attribute.DefaultValue()
+				if err != nil || defaultValue == nil {
+					continue
+				}
+				_ = state.Set(key, defaultValue)
+			}
+		}
+		return states, nil
+	}
+}
+
+// importZone returns the zone of an import by name, defaulting to the zone of the provider.
+func importZone(d *schema.ResourceData, meta *Meta, locality string) (scw.Zone, error) {
+	if locality == "" {
+		return extractZone(d, meta)
+	}
+	return scw.ParseZone(locality)
+}
+
+// importRegion returns the region of an import by name, defaulting to the region of the provider.
+func importRegion(d *schema.ResourceData, meta *Meta, locality string) (scw.Region, error) {
+	if locality == "" {
+		return extractRegion(d, meta)
+	}
+	return scw.ParseRegion(locality)
+}
+
+// findIDByName returns the ID of the only resource named name, candidates mapping IDs to names.
+// List APIs filter names by prefix or substring, so only exact matches are kept.
+func findIDByName(kind string, name string, candidates map[string]string) (string, error) {
+	var ids []string
+	for id, candidateName := range candidates {
+		if candidateName == name {
+			ids = append(ids, id)
+		}
+	}
+
+	switch len(ids) {
+	case 0:
+		return "", fmt.Errorf("no %s found with the name %s", kind, name)
+	case 1:
+		return ids[0], nil
+	default:
+		sort.Strings(ids)
+		return "", fmt.Errorf("%d %ss found with the name %s, import one of them by id: %s", len(ids), kind, name, strings.Join(ids, ", "))
+	}
+}
+
 // isHTTPCodeError returns true if err is an http error with code statusCode
 func isHTTPCodeError(err error, statusCode int) bool {
 	if err == nil {
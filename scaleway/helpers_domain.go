@@ -9,7 +9,6 @@ import (
 
 	domain "github.com/scaleway/scaleway-sdk-go/api/domain/v2beta1"
 	"github.com/scaleway/scaleway-sdk-go/scw"
-	"golang.org/x/exp/slices"
 )
 
 const (
@@ -297,6 +296,13 @@ var (
 )
 
 func hostResolver(ctx context.Context, timeout time.Duration, reverse, ip string) bool {
+	return hostResolverMatch(ctx, timeout, reverse, func(address net.IP) bool {
+		return address.Equal(net.ParseIP(ip))
+	})
+}
+
+// hostResolverMatch waits for one of the A or AAAA records of the host to match.
+func hostResolverMatch(ctx context.Context, timeout time.Duration, reverse string, match func(address net.IP) bool) bool {
 	if disableDNSResolver {
 		return true
 	}
@@ -307,7 +313,7 @@ func hostResolver(ctx context.Context, timeout time.Duration, reverse, ip string
 	r := newDNSResolver()
 
 	for range ticker {
-		address, err := r.LookupHost(ctx, reverse)
+		addresses, err := r.LookupHost(ctx, reverse)
 		if err != nil {
 			select {
 			case <-ctx.Done():
@@ -316,8 +322,10 @@ func hostResolver(ctx context.Context, timeout time.Duration, reverse, ip string
 				continue
 			}
 		}
-		if slices.Contains(address, ip) {
-			return true
+		for _, address := range addresses {
+			if ip := net.ParseIP(address); ip != nil && match(ip) {
+				return true
+			}
 		}
 	}
 
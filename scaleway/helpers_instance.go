@@ -1,19 +1,34 @@
 package scaleway
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"regexp"
 	"sort"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/dustin/go-humanize"
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/scaleway/scaleway-sdk-go/api/instance/v1"
+	ipam "github.com/scaleway/scaleway-sdk-go/api/ipam/v1alpha1"
+	lbSDK "github.com/scaleway/scaleway-sdk-go/api/lb/v1"
+	"github.com/scaleway/scaleway-sdk-go/api/marketplace/v2"
 	"github.com/scaleway/scaleway-sdk-go/api/vpc/v1"
 	"github.com/scaleway/scaleway-sdk-go/scw"
+	scwvalidation "github.com/scaleway/scaleway-sdk-go/validation"
+	"golang.org/x/exp/slices"
 )
 
 const (
@@ -24,6 +39,13 @@ const (
 	// InstanceServerStateStandby transient state of the instance event waiting third action or rescue mode
 	InstanceServerStateStandby = "standby"
 
+	// InstanceServerStopActionPoweroff powers the server off
+	InstanceServerStopActionPoweroff = "poweroff"
+	// InstanceServerStopActionGraceful stops the server in place, shutting its OS down, before powering it off
+	InstanceServerStopActionGraceful = "graceful"
+	// InstanceServerStopActionTerminate terminates the running server on deletion instead of powering it off
+	InstanceServerStopActionTerminate = "terminate"
+
 	defaultInstanceServerWaitTimeout        = 10 * time.Minute
 	defaultInstancePrivateNICWaitTimeout    = 10 * time.Minute
 	defaultInstanceVolumeDeleteTimeout      = 10 * time.Minute
@@ -33,6 +55,7 @@ const (
 	defaultInstanceIPTimeout                = 1 * time.Minute
 	defaultInstanceIPReverseDNSTimeout      = 5 * time.Minute
 	defaultInstanceRetryInterval            = 5 * time.Second
+	defaultInstanceServerConditionTimeout   = 5 * time.Minute
 
 	defaultInstanceSnapshotWaitTimeout = 1 * time.Hour
 
@@ -133,7 +156,37 @@ func serverStateExpand(rawState string) (instance.ServerState, error) {
 	return apiState, nil
 }
 
-func reachState(ctx context.Context, instanceAPI *instance.API, zone scw.Zone, serverID string, toState instance.ServerState) error {
+// serverWaitCondition is waited for before stopping a server or after starting it.
+type serverWaitCondition struct {
+	// UserDataKey is a user data key that must exist on the server.
+	UserDataKey string
+	// TCPPort must accept connections on TCPAddress, or on the public IP of the server when TCPAddress is empty.
+	TCPPort    int
+	TCPAddress string
+	Timeout    time.Duration
+}
+
+// serverLifecycle configures how reachState stops and starts a server, the zero value powers it off and on directly.
+type serverLifecycle struct {
+	StopAction      string
+	ShutdownTimeout time.Duration
+	PreStop         *serverWaitCondition
+	PostStart       *serverWaitCondition
+}
+
+func (l *serverLifecycle) shutdownTimeout() time.Duration {
+	if l.ShutdownTimeout == 0 {
+		return defaultInstanceServerWaitTimeout
+	}
+	return l.ShutdownTimeout
+}
+
+// reachState executes server action(s) to reach the expected state
+func reachState(ctx context.Context, instanceAPI *instance.API, zone scw.Zone, serverID string, toState instance.ServerState, lifecycle *serverLifecycle) error {
+	if lifecycle == nil {
+		lifecycle = &serverLifecycle{}
+	}
+
 	response, err := instanceAPI.GetServer(&instance.GetServerRequest{
 		Zone:     zone,
 		ServerID: serverID,
@@ -175,21 +228,184 @@ func reachState(ctx context.Context, instanceAPI *instance.API, zone scw.Zone, s
 		}
 	}
 
-	for _, a := range actions {
+	for i, a := range actions {
+		timeout := defaultInstanceServerWaitTimeout
+		isStopAction := a == instance.ServerActionPoweroff || a == instance.ServerActionStopInPlace
+		if isStopAction {
+			timeout = lifecycle.shutdownTimeout()
+			err = waitForServerCondition(ctx, instanceAPI, zone, serverID, lifecycle.PreStop)
+			if err != nil {
+				return fmt.Errorf("pre-stop condition of server %s: %w", serverID, err)
+			}
+		}
+
+		if a == instance.ServerActionPoweroff && lifecycle.StopAction == InstanceServerStopActionGraceful && (i > 0 || fromState == instance.ServerStateRunning) {
+			err = instanceAPI.ServerActionAndWait(&instance.ServerActionAndWaitRequest{
+				ServerID:      serverID,
+				Action:        instance.ServerActionStopInPlace,
+				Zone:          zone,
+				Timeout:       scw.TimeDurationPtr(timeout),
+				RetryInterval: DefaultWaitRetryInterval,
+			})
+			if err != nil {
+				tflog.Warn(ctx, fmt.Sprintf("server %s did not shut down gracefully, powering it off: %s", serverID, err))
+			}
+		}
+
 		err = instanceAPI.ServerActionAndWait(&instance.ServerActionAndWaitRequest{
 			ServerID:      serverID,
 			Action:        a,
 			Zone:          zone,
-			Timeout:       scw.TimeDurationPtr(defaultInstanceServerWaitTimeout),
+			Timeout:       scw.TimeDurationPtr(timeout),
 			RetryInterval: DefaultWaitRetryInterval,
 		})
 		if err != nil {
 			return err
 		}
 	}
+
+	if toState == instance.ServerStateRunning {
+		err = waitForServerCondition(ctx, instanceAPI, zone, serverID, lifecycle.PostStart)
+		if err != nil {
+			return fmt.Errorf("post-start condition of server %s: %w", serverID, err)
+		}
+	}
+
 	return nil
 }
 
+// waitForServerCondition waits until the condition is met, a nil condition is always met.
+func waitForServerCondition(ctx context.Context, instanceAPI *instance.API, zone scw.Zone, serverID string, condition *serverWaitCondition) error {
+	if condition == nil {
+		return nil
+	}
+
+	timeout := condition.Timeout
+	if timeout == 0 {
+		timeout = defaultInstanceServerConditionTimeout
+	}
+	retryInterval := defaultInstanceRetryInterval
+	if DefaultWaitRetryInterval != nil {
+		retryInterval = *DefaultWaitRetryInterval
+	}
+
+	deadline := time.Now().Add(timeout)
+	for {
+		met, reason, err := serverConditionMet(ctx, instanceAPI, zone, serverID, condition)
+		if err != nil {
+			return err
+		}
+		if met {
+			return nil
+		}
+
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			return fmt.Errorf("condition not met after %s: %s", timeout, reason)
+		}
+		if remaining > retryInterval {
+			remaining = retryInterval
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(remaining):
+		}
+	}
+}
+
+// serverConditionMet checks the condition once, returning why it is not met yet.
+func serverConditionMet(ctx context.Context, instanceAPI *instance.API, zone scw.Zone, serverID string, condition *serverWaitCondition) (bool, string, error) {
+	if condition.UserDataKey != "" {
+		_, err := instanceAPI.GetServerUserData(&instance.GetServerUserDataRequest{
+			Zone:     zone,
+			ServerID: serverID,
+			Key:      condition.UserDataKey,
+		}, scw.WithContext(ctx))
+		if is404Error(err) {
+			return false, fmt.Sprintf("user data %s does not exist", condition.UserDataKey), nil
+		}
+		if err != nil {
+			return false, "", err
+		}
+	}
+
+	if condition.TCPPort != 0 {
+		address := condition.TCPAddress
+		if address == "" {
+			res, err := instanceAPI.GetServer(&instance.GetServerRequest{
+				Zone:     zone,
+				ServerID: serverID,
+			}, scw.WithContext(ctx))
+			if err != nil {
+				return false, "", err
+			}
+			if res.Server.PublicIP == nil {
+				return false, "", fmt.Errorf("server %s has no public IP to reach port %d, set an address", serverID, condition.TCPPort)
+			}
+			address = res.Server.PublicIP.Address.String()
+		}
+
+		target := net.JoinHostPort(address, strconv.Itoa(condition.TCPPort))
+		conn, err := (&net.Dialer{Timeout: defaultInstanceRetryInterval}).DialContext(ctx, "tcp", target)
+		if err != nil {
+			return false, fmt.Sprintf("%s is not reachable: %s", target, err), nil
+		}
+		_ = conn.Close()
+	}
+
+	return true, "", nil
+}
+
+// expandInstanceServerLifecycle returns how the server must be stopped and started.
+func expandInstanceServerLifecycle(d *schema.ResourceData) (*serverLifecycle, error) {
+	shutdownTimeout, err := expandDuration(d.Get("shutdown_timeout"))
+	if err != nil {
+		return nil, err
+	}
+	preStop, err := expandInstanceServerWaitCondition(d.Get("pre_stop"))
+	if err != nil {
+		return nil, err
+	}
+	postStart, err := expandInstanceServerWaitCondition(d.Get("post_start"))
+	if err != nil {
+		return nil, err
+	}
+
+	lifecycle := &serverLifecycle{
+		StopAction: d.Get("stop_action").(string),
+		PreStop:    preStop,
+		PostStart:  postStart,
+	}
+	if shutdownTimeout != nil {
+		lifecycle.ShutdownTimeout = *shutdownTimeout
+	}
+	return lifecycle, nil
+}
+
+func expandInstanceServerWaitCondition(raw interface{}) (*serverWaitCondition, error) {
+	rawList, _ := raw.([]interface{})
+	if len(rawList) == 0 || rawList[0] == nil {
+		return nil, nil
+	}
+	rawCondition := rawList[0].(map[string]interface{})
+
+	condition := &serverWaitCondition{
+		UserDataKey: rawCondition["user_data_key"].(string),
+		TCPPort:     rawCondition["tcp_port"].(int),
+		TCPAddress:  rawCondition["tcp_address"].(string),
+	}
+	timeout, err := expandDuration(rawCondition["timeout"])
+	if err != nil {
+		return nil, err
+	}
+	if timeout != nil {
+		condition.Timeout = *timeout
+	}
+	return condition, nil
+}
+
 // getServerType is a util to get a instance.ServerType by its commercialType
 func getServerType(ctx context.Context, apiInstance *instance.API, zone scw.Zone, commercialType string) *instance.ServerType {
 	serverType := (*instance.ServerType)(nil)
@@ -214,8 +430,8 @@ func validateLocalVolumeSizes(volumes map[string]*instance.VolumeServerTemplate,
 	// Calculate local volume total size.
 	var localVolumeTotalSize scw.Size
 	for _, volume := range volumes {
-		if volume.VolumeType == instance.VolumeVolumeTypeLSSD {
-			localVolumeTotalSize += volume.Size
+		if volume.VolumeType == instance.VolumeVolumeTypeLSSD && volume.Size != nil {
+			localVolumeTotalSize += *volume.Size
 		}
 	}
 
@@ -239,6 +455,22 @@ func validateLocalVolumeSizes(volumes map[string]*instance.VolumeServerTemplate,
 	return nil
 }
 
+// expandInstanceVolumeSize returns nil for a zero size, the size of the volume is then computed by the API.
+func expandInstanceVolumeSize(size scw.Size) *scw.Size {
+	if size == 0 {
+		return nil
+	}
+	return &size
+}
+
+// expandInstanceVolumeBoot returns nil when the volume is not forced as boot volume, as the API defaults it to false.
+func expandInstanceVolumeBoot(boot bool) *bool {
+	if !boot {
+		return nil
+	}
+	return &boot
+}
+
 // sanitizeVolumeMap removes extra data for API validation.
 //
 // On the api side, there are two possibles validation schemas for volumes and the validator will be chosen dynamically depending on the passed JSON request
@@ -252,7 +484,7 @@ func sanitizeVolumeMap(volumes map[string]*instance.VolumeServerTemplate) map[st
 		switch {
 		// If a volume already got an ID it is passed as it to the API without specifying the volume type.
 		// TODO: Fix once instance accept volume type in the schema validation
-		case v.ID != "":
+		case v.ID != nil:
 			v = &instance.VolumeServerTemplate{
 				ID:   v.ID,
 				Name: v.Name,
@@ -260,7 +492,7 @@ func sanitizeVolumeMap(volumes map[string]*instance.VolumeServerTemplate) map[st
 			}
 		// For the root volume (index 0) if the size is 0, it is considered as a volume created from an image.
 		// The size is not passed to the API, so it's computed by the API
-		case index == "0" && v.Size == 0:
+		case index == "0" && v.Size == nil:
 			v = &instance.VolumeServerTemplate{
 				VolumeType: v.VolumeType,
 				Boot:       v.Boot,
@@ -587,19 +819,1304 @@ func flattenInstanceImageExtraVolumes(volumes map[string]*instance.Volume, zone
 	return volumesFlat
 }
 
+// instanceImageUUID returns the UUID of an image given by its UUID or by its marketplace label.
+func instanceImageUUID(ctx context.Context, meta interface{}, zone scw.Zone, commercialType string, image string) (string, error) {
+	imageUUID := expandID(image)
+	if imageUUID == "" || scwvalidation.IsUUID(imageUUID) {
+		return imageUUID, nil
+	}
+
+	// Replace dashes with underscores ubuntu-focal -> ubuntu_focal
+	imageLabel := formatImageLabel(imageUUID)
+
+	marketPlaceAPI := marketplace.NewAPI(meta.(*Meta).scwClient)
+	res, err := marketPlaceAPI.GetLocalImageByLabel(&marketplace.GetLocalImageByLabelRequest{
+		CommercialType: commercialType,
+		Zone:           zone,
+		ImageLabel:     imageLabel,
+	}, scw.WithContext(ctx))
+	if err != nil {
+		return "", fmt.Errorf("could not get image '%s': %s", newZonedID(zone, imageLabel), err)
+	}
+	return res.ID, nil
+}
+
 func formatImageLabel(imageUUID string) string {
 	return strings.ReplaceAll(imageUUID, "-", "_")
 }
 
-func isInstanceIPReverseResolved(ctx context.Context, instanceAPI *instance.API, reverse string, timeout time.Duration, id string, zone scw.Zone) bool {
-	getIPReq := &instance.GetIPRequest{
+// isInstanceIPReverseResolved checks that the reverse resolves to the IP, with an A or an AAAA record.
+// The reverse of a routed IPv6 IP may resolve to any address of its prefix.
+func isInstanceIPReverseResolved(ctx context.Context, client *scw.Client, reverse string, timeout time.Duration, id string, zone scw.Zone) bool {
+	ip, err := getInstanceIP(ctx, client, zone, id)
+	if err != nil {
+		return false
+	}
+
+	if ip.Prefix != "" {
+		_, prefix, err := net.ParseCIDR(ip.Prefix)
+		if err != nil {
+			return false
+		}
+		return hostResolverMatch(ctx, timeout, reverse, func(address net.IP) bool {
+			return prefix.Contains(address)
+		})
+	}
+
+	return hostResolverMatch(ctx, timeout, reverse, func(address net.IP) bool {
+		return address.Equal(ip.Address)
+	})
+}
+
+const (
+	instanceIPTypeNAT        = "nat"
+	instanceIPTypeRoutedIPv4 = "routed_ipv4"
+	instanceIPTypeRoutedIPv6 = "routed_ipv6"
+)
+
+// instanceIP is an instance IP with the type and the prefix of routed IPs, which the SDK does not support yet.
+type instanceIP struct {
+	instance.IP
+	Type   string `json:"type"`
+	Prefix string `json:"prefix"`
+}
+
+// instanceServerIP is an IP listed in the public_ips of a server, which the SDK does not support yet.
+type instanceServerIP struct {
+	ID      string `json:"id"`
+	Address string `json:"address"`
+	Family  string `json:"family"`
+	Dynamic bool   `json:"dynamic"`
+}
+
+// getInstanceIP gets an IP with the client, GetIP of the SDK does not return its type and prefix.
+func getInstanceIP(ctx context.Context, client *scw.Client, zone scw.Zone, id string) (*instanceIP, error) {
+	req := &scw.ScalewayRequest{
+		Method:  "GET",
+		Path:    "/instance/v1/zones/" + zone.String() + "/ips/" + id,
+		Headers: http.Header{},
+	}
+	res := &struct {
+		IP *instanceIP `json:"ip"`
+	}{}
+	err := client.Do(req, res, scw.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	return res.IP, nil
+}
+
+// createInstanceIPWithType creates an IP of the given type with the client, CreateIPRequest of the SDK does not support it yet.
+func createInstanceIPWithType(ctx context.Context, client *scw.Client, ipRequest *instance.CreateIPRequest, ipType string) (*instanceIP, error) {
+	req := &scw.ScalewayRequest{
+		Method:  "POST",
+		Path:    "/instance/v1/zones/" + ipRequest.Zone.String() + "/ips",
+		Headers: http.Header{},
+	}
+	err := req.SetBody(map[string]interface{}{
+		"project": ipRequest.Project,
+		"tags":    ipRequest.Tags,
+		"type":    ipType,
+	})
+	if err != nil {
+		return nil, err
+	}
+	res := &struct {
+		IP *instanceIP `json:"ip"`
+	}{}
+	err = client.Do(req, res, scw.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	return res.IP, nil
+}
+
+// instanceServerWithPublicIPs is a server with every IP attached to it, Server of the SDK only has the first one.
+type instanceServerWithPublicIPs struct {
+	*instance.Server
+	PublicIPs []*instanceServerIP `json:"public_ips"`
+}
+
+// waitForInstanceServerWithPublicIPs is the equivalent of waitForInstanceServer also returning every IP attached to the server.
+// The server is polled with the SDK client, which sends the same requests as WaitForServer of the SDK.
+func waitForInstanceServerWithPublicIPs(ctx context.Context, client *scw.Client, zone scw.Zone, id string, timeout time.Duration) (*instanceServerWithPublicIPs, error) {
+	retryInterval := defaultInstanceRetryInterval
+	if DefaultWaitRetryInterval != nil {
+		retryInterval = *DefaultWaitRetryInterval
+	}
+	terminalStates := map[instance.ServerState]struct{}{
+		instance.ServerStateStopped:        {},
+		instance.ServerStateStoppedInPlace: {},
+		instance.ServerStateLocked:         {},
+		instance.ServerStateRunning:        {},
+	}
+
+	deadline := time.Now().Add(timeout)
+	for {
+		req := &scw.ScalewayRequest{
+			Method:  "GET",
+			Path:    "/instance/v1/zones/" + zone.String() + "/servers/" + id,
+			Headers: http.Header{},
+		}
+		res := &struct {
+			Server *instanceServerWithPublicIPs `json:"server"`
+		}{}
+		err := client.Do(req, res, scw.WithContext(ctx))
+		if err != nil {
+			return nil, fmt.Errorf("waiting for server failed: %w", err)
+		}
+		if _, isTerminal := terminalStates[res.Server.State]; isTerminal {
+			return res.Server, nil
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("waiting for server failed: timeout after %s", timeout)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(retryInterval):
+		}
+	}
+}
+
+// enableInstanceServerRoutedIP switches a server to routed IPs, which can be attached to it along other IPs.
+// UpdateServerRequest of the SDK does not support routed_ip_enabled yet, the request is sent with the SDK client.
+func enableInstanceServerRoutedIP(ctx context.Context, client *scw.Client, zone scw.Zone, serverID string) error {
+	req := &scw.ScalewayRequest{
+		Method:  "PATCH",
+		Path:    "/instance/v1/zones/" + zone.String() + "/servers/" + serverID,
+		Headers: http.Header{},
+	}
+	err := req.SetBody(map[string]bool{"routed_ip_enabled": true})
+	if err != nil {
+		return err
+	}
+
+	return client.Do(req, &instance.UpdateServerResponse{}, scw.WithContext(ctx))
+}
+
+// attachInstanceServerIPs attaches the IPs to a server, or detaches them when serverID is empty.
+func attachInstanceServerIPs(ctx context.Context, instanceAPI *instance.API, zone scw.Zone, serverID string, ipIDs []string, timeout time.Duration) error {
+	server := &instance.NullableStringValue{Null: true}
+	if serverID != "" {
+		server = &instance.NullableStringValue{Value: serverID}
+	}
+	for _, ipID := range ipIDs {
+		_, err := instanceAPI.UpdateIP(&instance.UpdateIPRequest{
+			Zone:   zone,
+			IP:     ipID,
+			Server: server,
+		}, scw.WithContext(ctx))
+		if err != nil {
+			return err
+		}
+		if serverID != "" {
+			_, err = waitForInstanceServer(ctx, instanceAPI, zone, serverID, timeout)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// expandInstanceServerIPIDs returns the IDs without their zone of the ip_ids of a server.
+func expandInstanceServerIPIDs(rawIPIDs interface{}) []string {
+	ipIDs := []string(nil)
+	for _, rawIPID := range rawIPIDs.([]interface{}) {
+		ipIDs = append(ipIDs, expandID(rawIPID))
+	}
+	return ipIDs
+}
+
+// diffInstanceServerIPIDs returns the IPs to detach from a server and the ones to attach to it.
+func diffInstanceServerIPIDs(oldIPIDs, newIPIDs []string) ([]string, []string) {
+	detached := []string(nil)
+	for _, ipID := range oldIPIDs {
+		if !slices.Contains(newIPIDs, ipID) {
+			detached = append(detached, ipID)
+		}
+	}
+	attached := []string(nil)
+	for _, ipID := range newIPIDs {
+		if !slices.Contains(oldIPIDs, ipID) {
+			attached = append(attached, ipID)
+		}
+	}
+	return detached, attached
+}
+
+// flattenInstanceServerPublicIPs returns the public_ips of a server.
+func flattenInstanceServerPublicIPs(zone scw.Zone, publicIPs []*instanceServerIP) []interface{} {
+	flattened := []interface{}(nil)
+	for _, publicIP := range publicIPs {
+		flattened = append(flattened, map[string]interface{}{
+			"id":      newZonedIDString(zone, publicIP.ID),
+			"address": publicIP.Address,
+			"family":  publicIP.Family,
+		})
+	}
+	return flattened
+}
+
+// flattenInstanceServerIPIDs returns the IDs of the reserved IPs of a server, ordered as in the previous ones.
+// The IP in ipID is left out, as it is managed by the ip_id argument.
+func flattenInstanceServerIPIDs(zone scw.Zone, publicIPs []*instanceServerIP, ipID string, previousIPIDs []interface{}) []string {
+	position := map[string]int{}
+	for i, previousIPID := range previousIPIDs {
+		position[expandID(previousIPID)] = i
+	}
+
+	ipIDs := []*instanceServerIP(nil)
+	for _, publicIP := range publicIPs {
+		if !publicIP.Dynamic && publicIP.ID != ipID {
+			ipIDs = append(ipIDs, publicIP)
+		}
+	}
+	sort.SliceStable(ipIDs, func(i, j int) bool {
+		positionI, knownI := position[ipIDs[i].ID]
+		positionJ, knownJ := position[ipIDs[j].ID]
+		if knownI != knownJ {
+			return knownI
+		}
+		return positionI < positionJ
+	})
+
+	flattened := []string(nil)
+	for _, publicIP := range ipIDs {
+		flattened = append(flattened, newZonedIDString(zone, publicIP.ID))
+	}
+	return flattened
+}
+
+// instanceServerIDByName resolves the zoned ID of a server imported by name.
+func instanceServerIDByName(ctx context.Context, d *schema.ResourceData, meta *Meta, locality string, name string) (string, error) {
+	zone, err := importZone(d, meta, locality)
+	if err != nil {
+		return "", err
+	}
+
+	res, err := instance.NewAPI(meta.scwClient).ListServers(&instance.ListServersRequest{
+		Zone: zone,
+		Name: &name,
+	}, scw.WithContext(ctx), scw.WithAllPages())
+	if err != nil {
+		return "", err
+	}
+
+	candidates := make(map[string]string, len(res.Servers))
+	for _, server := range res.Servers {
+		candidates[server.ID] = server.Name
+	}
+	id, err := findIDByName("server", name, candidates)
+	if err != nil {
+		return "", err
+	}
+	return newZonedIDString(zone, id), nil
+}
+
+// instanceVolumeIDByName resolves the zoned ID of a volume imported by name.
+func instanceVolumeIDByName(ctx context.Context, d *schema.ResourceData, meta *Meta, locality string, name string) (string, error) {
+	zone, err := importZone(d, meta, locality)
+	if err != nil {
+		return "", err
+	}
+
+	res, err := instance.NewAPI(meta.scwClient).ListVolumes(&instance.ListVolumesRequest{
 		Zone: zone,
-		IP:   id,
+		Name: &name,
+	}, scw.WithContext(ctx), scw.WithAllPages())
+	if err != nil {
+		return "", err
 	}
-	res, err := instanceAPI.GetIP(getIPReq, scw.WithContext(ctx))
+
+	candidates := make(map[string]string, len(res.Volumes))
+	for _, volume := range res.Volumes {
+		candidates[volume.ID] = volume.Name
+	}
+	id, err := findIDByName("volume", name, candidates)
 	if err != nil {
-		return false
+		return "", err
+	}
+	return newZonedIDString(zone, id), nil
+}
+
+// instanceSecurityGroupIDByName resolves the zoned ID of a security group imported by name.
+func instanceSecurityGroupIDByName(ctx context.Context, d *schema.ResourceData, meta *Meta, locality string, name string) (string, error) {
+	zone, err := importZone(d, meta, locality)
+	if err != nil {
+		return "", err
+	}
+
+	res, err := instance.NewAPI(meta.scwClient).ListSecurityGroups(&instance.ListSecurityGroupsRequest{
+		Zone: zone,
+		Name: &name,
+	}, scw.WithContext(ctx), scw.WithAllPages())
+	if err != nil {
+		return "", err
+	}
+
+	candidates := make(map[string]string, len(res.SecurityGroups))
+	for _, securityGroup := range res.SecurityGroups {
+		candidates[securityGroup.ID] = securityGroup.Name
+	}
+	id, err := findIDByName("security group", name, candidates)
+	if err != nil {
+		return "", err
+	}
+	return newZonedIDString(zone, id), nil
+}
+
+// validateServerTypeChange checks that the architecture and the local volumes of server are compatible with commercialType.
+func validateServerTypeChange(ctx context.Context, instanceAPI *instance.API, zone scw.Zone, server *instance.Server, commercialType string) error {
+	serverType := getServerType(ctx, instanceAPI, zone, commercialType)
+	if serverType == nil {
+		return fmt.Errorf("server type %s is not available in zone %s", commercialType, zone)
+	}
+
+	if serverType.Arch != server.Arch {
+		return fmt.Errorf("server type %s has architecture %s, server %s has architecture %s", commercialType, serverType.Arch, server.ID, server.Arch)
 	}
 
-	return hostResolver(ctx, timeout, reverse, res.IP.Address.String())
+	volumes := make(map[string]*instance.VolumeServerTemplate, len(server.Volumes))
+	for key, volume := range server.Volumes {
+		volumes[key] = &instance.VolumeServerTemplate{
+			VolumeType: instance.VolumeVolumeType(volume.VolumeType),
+			Size:       expandInstanceVolumeSize(volume.Size),
+		}
+	}
+	return validateLocalVolumeSizes(volumes, serverType, commercialType)
+}
+
+// updateServerType changes the commercial type of a stopped server.
+func updateServerType(ctx context.Context, instanceAPI *instance.API, zone scw.Zone, serverID string, commercialType string) error {
+	_, err := instanceAPI.UpdateServer(&instance.UpdateServerRequest{
+		Zone:           zone,
+		ServerID:       serverID,
+		CommercialType: &commercialType,
+	}, scw.WithContext(ctx))
+	return err
+}
+
+// getServerCompatibleTypes returns the commercial types a server can be changed to in place.
+// The SDK does not support this endpoint yet, the request is sent with the SDK client.
+func getServerCompatibleTypes(ctx context.Context, client *scw.Client, zone scw.Zone, serverID string) ([]string, error) {
+	req := &scw.ScalewayRequest{
+		Method:  "GET",
+		Path:    "/instance/v1/zones/" + zone.String() + "/servers/" + serverID + "/compatible-types",
+		Headers: http.Header{},
+	}
+	res := &struct {
+		CompatibleTypes []string `json:"compatible_types"`
+	}{}
+	err := client.Do(req, res, scw.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	return res.CompatibleTypes, nil
+}
+
+// customizeDiffInstanceServerType replaces the server when its type changes, unless replace_on_type_change is false.
+// Whether the server can be migrated in place to the new type is only checked when the change is applied.
+func customizeDiffInstanceServerType(_ context.Context, diff *schema.ResourceDiff, _ interface{}) error {
+	if diff.Id() == "" || !diff.HasChange("type") || !diff.Get("replace_on_type_change").(bool) {
+		return nil
+	}
+	return diff.ForceNew("type")
+}
+
+// resizeInstanceVolume grows a block volume to sizeInGB, waiting for the volume to be available before and after.
+func resizeInstanceVolume(ctx context.Context, api *instance.API, zone scw.Zone, id string, sizeInGB int, timeout time.Duration) error {
+	_, err := waitForInstanceVolume(ctx, api, zone, id, timeout)
+	if err != nil {
+		return err
+	}
+
+	volumeSizeInBytes := scw.Size(uint64(sizeInGB) * gb)
+	_, err = api.UpdateVolume(&instance.UpdateVolumeRequest{
+		VolumeID: id,
+		Zone:     zone,
+		Size:     &volumeSizeInBytes,
+	}, scw.WithContext(ctx))
+	if err != nil {
+		return fmt.Errorf("couldn't resize volume: %s", err)
+	}
+
+	_, err = waitForInstanceVolume(ctx, api, zone, id, timeout)
+	return err
+}
+
+// validateInstanceVolumeGrowth returns an error when a volume of the given type cannot go from oldSize to newSize in place.
+func validateInstanceVolumeGrowth(volumeType string, oldSize int, newSize int) error {
+	if volumeType != instance.VolumeVolumeTypeBSSD.String() {
+		return fmt.Errorf("only block volume can be resized")
+	}
+	if oldSize > newSize {
+		return fmt.Errorf("block volumes cannot be resized down")
+	}
+	return nil
+}
+
+// customizeDiffInstanceVolumeSize rejects at plan time the size changes the API would refuse.
+func customizeDiffInstanceVolumeSize(_ context.Context, diff *schema.ResourceDiff, _ interface{}) error {
+	if diff.Id() == "" || !diff.HasChange("size_in_gb") {
+		return nil
+	}
+
+	oldSize, newSize := diff.GetChange("size_in_gb")
+	return validateInstanceVolumeGrowth(diff.Get("type").(string), oldSize.(int), newSize.(int))
+}
+
+// customizeDiffInstanceServerRootVolumeSize grows block root volumes in place, local root volumes still require a new server.
+func customizeDiffInstanceServerRootVolumeSize(_ context.Context, diff *schema.ResourceDiff, _ interface{}) error {
+	if diff.Id() == "" || !diff.HasChange("root_volume.0.size_in_gb") || diff.HasChange("root_volume.0.volume_type") {
+		return nil
+	}
+
+	oldSize, newSize := diff.GetChange("root_volume.0.size_in_gb")
+	if oldSize.(int) == 0 || newSize.(int) == 0 {
+		return nil
+	}
+
+	volumeType := diff.Get("root_volume.0.volume_type").(string)
+	if volumeType != instance.VolumeVolumeTypeBSSD.String() {
+		return diff.ForceNew("root_volume.0.size_in_gb")
+	}
+
+	err := validateInstanceVolumeGrowth(volumeType, oldSize.(int), newSize.(int))
+	if err != nil {
+		return fmt.Errorf("root volume of server %s: %w", diff.Id(), err)
+	}
+	return nil
+}
+
+// createInstanceFinalSnapshot snapshots a volume before its deletion and waits for the snapshot to be available.
+// The snapshot is tagged with the name of the destroyed resource and the time of the snapshot.
+// The zoned ID of the snapshot is returned as soon as it is created, even when waiting for it fails.
+func createInstanceFinalSnapshot(ctx context.Context, api *instance.API, zone scw.Zone, volumeID string, volumeName string, ownerName string, projectID string, timeout time.Duration) (string, error) {
+	timestamp := time.Now().UTC().Format("20060102-150405")
+	res, err := api.CreateSnapshot(&instance.CreateSnapshotRequest{
+		Zone:     zone,
+		Name:     fmt.Sprintf("%s-final-%s", volumeName, timestamp),
+		VolumeID: &volumeID,
+		Project:  &projectID,
+		Tags:     []string{"final-snapshot", ownerName, timestamp},
+	}, scw.WithContext(ctx))
+	if err != nil {
+		return "", fmt.Errorf("couldn't create the final snapshot of volume %s: %w", volumeID, err)
+	}
+
+	snapshotID := newZonedIDString(zone, res.Snapshot.ID)
+	_, err = waitForInstanceSnapshot(ctx, api, zone, res.Snapshot.ID, timeout)
+	if err != nil {
+		return snapshotID, fmt.Errorf("final snapshot %s of volume %s is not available: %w", snapshotID, volumeID, err)
+	}
+
+	return snapshotID, nil
+}
+
+// finalSnapshotWarning reports the final snapshots, as the state of a destroyed resource cannot hold them.
+func finalSnapshotWarning(ownerName string, snapshotIDs []string) diag.Diagnostic {
+	return diag.Diagnostic{
+		Severity: diag.Warning,
+		Summary:  fmt.Sprintf("final snapshots of %s were created", ownerName),
+		Detail:   fmt.Sprintf("the following snapshots are kept after the deletion: %s", strings.Join(snapshotIDs, ", ")),
+	}
+}
+
+// instancePrivateNICIPAddresses returns the IPAM addresses of a private NIC, private networks without IPAM have none.
+func instancePrivateNICIPAddresses(ctx context.Context, ipamAPI *ipam.API, zone scw.Zone, privateNICID string) ([]string, error) {
+	region, err := zone.Region()
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := ipamAPI.ListIPs(&ipam.ListIPsRequest{
+		Region:       region,
+		ResourceID:   &privateNICID,
+		ResourceType: ipam.ResourceTypeInstancePrivateNic,
+	}, scw.WithAllPages(), scw.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	addresses := []string(nil)
+	for _, ip := range res.IPs {
+		addresses = append(addresses, ip.Address.IP.String())
+	}
+	return addresses, nil
+}
+
+// instanceServerTemplateFields are the arguments of a server template stored in its content.
+var instanceServerTemplateFields = []string{
+	"image",
+	"type",
+	"root_volume",
+	"security_group_id",
+	"placement_group_id",
+	"cloud_init",
+	"user_data",
+	"private_network_ids",
+}
+
+// instanceServerTemplate is the content of a server template, passed as JSON to the servers and pools using it.
+// Scaleway has no server template API, templates only exist in the Terraform state.
+type instanceServerTemplate struct {
+	Image              string            `json:"image,omitempty"`
+	Type               string            `json:"type,omitempty"`
+	RootVolumeSizeInGB int               `json:"root_volume_size_in_gb,omitempty"`
+	RootVolumeType     string            `json:"root_volume_type,omitempty"`
+	SecurityGroupID    string            `json:"security_group_id,omitempty"`
+	PlacementGroupID   string            `json:"placement_group_id,omitempty"`
+	CloudInit          string            `json:"cloud_init,omitempty"`
+	UserData           map[string]string `json:"user_data,omitempty"`
+	PrivateNetworkIDs  []string          `json:"private_network_ids,omitempty"`
+}
+
+func expandInstanceServerTemplate(d terraformResourceData, zone scw.Zone) *instanceServerTemplate {
+	template := &instanceServerTemplate{
+		Image:              d.Get("image").(string),
+		Type:               d.Get("type").(string),
+		RootVolumeSizeInGB: d.Get("root_volume.0.size_in_gb").(int),
+		RootVolumeType:     d.Get("root_volume.0.volume_type").(string),
+		CloudInit:          d.Get("cloud_init").(string),
+		UserData:           *expandMapPtrStringString(d.Get("user_data")),
+	}
+	if securityGroupID, ok := d.GetOk("security_group_id"); ok {
+		template.SecurityGroupID = newZonedID(zone, expandID(securityGroupID)).String()
+	}
+	if placementGroupID, ok := d.GetOk("placement_group_id"); ok {
+		template.PlacementGroupID = newZonedID(zone, expandID(placementGroupID)).String()
+	}
+	for _, privateNetworkID := range expandStrings(d.Get("private_network_ids")) {
+		template.PrivateNetworkIDs = append(template.PrivateNetworkIDs, newZonedID(zone, expandID(privateNetworkID)).String())
+	}
+	return template
+}
+
+// flattenInstanceServerTemplateContent returns the content of a server template, as set in the template argument of servers and pools.
+func flattenInstanceServerTemplateContent(template *instanceServerTemplate) (string, error) {
+	content, err := json.Marshal(template)
+	if err != nil {
+		return "", err
+	}
+	return string(content), nil
+}
+
+// expandInstanceServerTemplateContent parses the template argument of a server or a pool, an empty content is an empty template.
+func expandInstanceServerTemplateContent(content string) (*instanceServerTemplate, error) {
+	template := &instanceServerTemplate{}
+	if content == "" {
+		return template, nil
+	}
+	err := json.Unmarshal([]byte(content), template)
+	if err != nil {
+		return nil, fmt.Errorf("invalid server template, template must be set to the content of a scaleway_instance_server_template: %w", err)
+	}
+	return template, nil
+}
+
+// customizeDiffInstanceServerTemplateContent plans the content and the revision of a server template when its arguments change,
+// so that the servers and pools using it plan the changes in the same run.
+func customizeDiffInstanceServerTemplateContent(_ context.Context, diff *schema.ResourceDiff, meta interface{}) error {
+	if diff.Id() != "" && !diff.HasChanges(instanceServerTemplateFields...) {
+		return nil
+	}
+
+	revision := 1
+	if diff.Id() != "" {
+		revision = diff.Get("revision").(int) + 1
+	}
+	err := diff.SetNew("revision", revision)
+	if err != nil {
+		return err
+	}
+
+	if !diff.GetRawConfig().IsWhollyKnown() {
+		return diff.SetNewComputed("content")
+	}
+	zone, err := extractZone(diff, meta.(*Meta))
+	if err != nil {
+		return err
+	}
+	content, err := flattenInstanceServerTemplateContent(expandInstanceServerTemplate(diff, zone))
+	if err != nil {
+		return err
+	}
+	return diff.SetNew("content", content)
+}
+
+// customizeDiffInstanceServerTemplate plans the arguments of a server that are not set but are set in its template.
+// The arguments taken from the template are listed in template_fields, so that the plan shows where they come from.
+func customizeDiffInstanceServerTemplate(ctx context.Context, diff *schema.ResourceDiff, meta interface{}) error {
+	rawConfig := diff.GetRawConfig()
+	isConfigured := func(key string) bool {
+		return !rawConfig.GetAttr(key).IsNull()
+	}
+	scalarFields := []string{"image", "type", "security_group_id", "placement_group_id", "cloud_init", "user_data"}
+
+	if !diff.NewValueKnown("template") {
+		// The template is created in the same apply, its values are not known yet.
+		for _, field := range scalarFields {
+			if !isConfigured(field) {
+				if err := diff.SetNewComputed(field); err != nil {
+					return err
+				}
+			}
+		}
+		return diff.SetNewComputed("template_fields")
+	}
+
+	template, err := expandInstanceServerTemplateContent(diff.Get("template").(string))
+	if err != nil {
+		return err
+	}
+	rootVolume := rawConfig.GetAttr("root_volume")
+	noRootVolumeID := rootVolume.IsNull() || rootVolume.LengthInt() == 0 || rootVolume.Index(cty.NumberIntVal(0)).GetAttr("volume_id").IsNull()
+
+	templateValues := map[string]interface{}{}
+	// The image of the template is not used when the root volume is an existing volume.
+	if template.Image != "" && noRootVolumeID {
+		templateValues["image"] = template.Image
+	}
+	if template.Type != "" {
+		templateValues["type"] = template.Type
+	}
+	if template.SecurityGroupID != "" {
+		templateValues["security_group_id"] = template.SecurityGroupID
+	}
+	if template.PlacementGroupID != "" {
+		templateValues["placement_group_id"] = template.PlacementGroupID
+	}
+	if template.CloudInit != "" {
+		templateValues["cloud_init"] = template.CloudInit
+	}
+	if len(template.UserData) > 0 {
+		userData := map[string]interface{}{}
+		for key, value := range template.UserData {
+			userData[key] = value
+		}
+		templateValues["user_data"] = userData
+	}
+
+	templateFields := []string(nil)
+	for _, field := range scalarFields {
+		if isConfigured(field) {
+			continue
+		}
+		value, isTemplated := templateValues[field]
+		switch {
+		case isTemplated:
+			templateFields = append(templateFields, field)
+			if err := diff.SetNew(field, value); err != nil {
+				return err
+			}
+		case field == "placement_group_id" && diff.Id() != "" && diff.Get(field).(string) != "":
+			// Without template, removing the placement group from the configuration still removes it from the server.
+			if err := diff.SetNew(field, ""); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Nested blocks are only taken from the template when the server is created.
+	if diff.Id() == "" {
+		rootVolumeConfigured := !rootVolume.IsNull() && rootVolume.LengthInt() > 0 &&
+			!(rootVolume.Index(cty.NumberIntVal(0)).GetAttr("size_in_gb").IsNull() && rootVolume.Index(cty.NumberIntVal(0)).GetAttr("volume_type").IsNull())
+		if !rootVolumeConfigured && (template.RootVolumeSizeInGB != 0 || template.RootVolumeType != "") {
+			templateFields = append(templateFields, "root_volume")
+		}
+		if !isConfigured("private_network") && len(template.PrivateNetworkIDs) > 0 {
+			templateFields = append(templateFields, "private_network")
+		}
+		if !isConfigured("type") && template.Type == "" {
+			return fmt.Errorf("type must be set, directly or in the server template")
+		}
+		if !isConfigured("image") && template.Image == "" && noRootVolumeID {
+			return fmt.Errorf("one of image or root_volume.0.volume_id must be set, image can be set in the server template")
+		}
+	} else if !isConfigured("private_network") && len(diff.Get("private_network").([]interface{})) > 0 && len(template.PrivateNetworkIDs) == 0 {
+		// Without template, removing the private networks from the configuration still detaches them.
+		if err := diff.SetNew("private_network", []interface{}{}); err != nil {
+			return err
+		}
+	}
+
+	return diff.SetNew("template_fields", templateFields)
+}
+
+const (
+	// instancePoolTag marks the placement groups of instance pools.
+	instancePoolTag = "scaleway_instance_pool"
+	// instancePoolServerTagPrefix prefixes the tag holding the ID of the pool of a server.
+	instancePoolServerTagPrefix = "instance-pool="
+	// instancePoolTemplateTagPrefix prefixes the tag holding the hash of the template a pool server was created from.
+	instancePoolTemplateTagPrefix = "instance-pool-template="
+)
+
+// instancePool maintains the servers of a scaleway_instance_pool, which are tagged with the ID of its placement group.
+type instancePool struct {
+	api             *instance.API
+	meta            interface{}
+	zone            scw.Zone
+	id              string
+	name            string
+	projectID       string
+	tags            []string
+	templateContent string
+	template        *instanceServerTemplate
+	lbBackendID     string
+	enableDynamicIP bool
+}
+
+func (p *instancePool) serverTag() string {
+	return instancePoolServerTagPrefix + p.id
+}
+
+// instancePoolTemplateTag identifies the template content a pool server was created from.
+func instancePoolTemplateTag(templateContent string) string {
+	sum := sha256.Sum256([]byte(templateContent))
+	return instancePoolTemplateTagPrefix + hex.EncodeToString(sum[:8])
+}
+
+// templateTag identifies the current template of the pool, servers with another one are outdated.
+func (p *instancePool) templateTag() string {
+	return instancePoolTemplateTag(p.templateContent)
+}
+
+func (p *instancePool) serverTags() []string {
+	return append(append([]string(nil), p.tags...), p.serverTag(), p.templateTag())
+}
+
+// listInstancePoolServers returns the servers of a pool, from the oldest to the newest.
+func listInstancePoolServers(ctx context.Context, api *instance.API, zone scw.Zone, poolID string) ([]*instance.Server, error) {
+	res, err := api.ListServers(&instance.ListServersRequest{
+		Zone: zone,
+		Tags: []string{instancePoolServerTagPrefix + poolID},
+	}, scw.WithContext(ctx), scw.WithAllPages())
+	if err != nil {
+		return nil, err
+	}
+	servers := res.Servers
+	sort.SliceStable(servers, func(i, j int) bool {
+		return servers[i].CreationDate.Before(*servers[j].CreationDate)
+	})
+	return servers, nil
+}
+
+// instancePoolServerTemplateTag returns the tag of the template a pool server was created from.
+func instancePoolServerTemplateTag(server *instance.Server) string {
+	for _, tag := range server.Tags {
+		if strings.HasPrefix(tag, instancePoolTemplateTagPrefix) {
+			return tag
+		}
+	}
+	return ""
+}
+
+// instancePoolServerIP returns the IP the load balancer forwards the traffic of a pool server to.
+// It is the IP of its first private network, its private IP or its public IP.
+func instancePoolServerIP(ctx context.Context, meta interface{}, zone scw.Zone, server *instance.Server) (string, error) {
+	if len(server.PrivateNics) > 0 {
+		ips, err := instancePrivateNICIPAddresses(ctx, ipam.NewAPI(meta.(*Meta).scwClient), zone, server.PrivateNics[0].ID)
+		if err != nil {
+			return "", fmt.Errorf("couldn't get the private network IP of server %s: %w", server.ID, err)
+		}
+		if len(ips) > 0 {
+			ip, _, _ := strings.Cut(ips[0], "/")
+			return ip, nil
+		}
+	}
+	if server.PrivateIP != nil {
+		return *server.PrivateIP, nil
+	}
+	if server.PublicIP != nil {
+		return server.PublicIP.Address.String(), nil
+	}
+	return "", nil
+}
+
+// createServer creates a server from the pool template, starts it and adds it to the load balancer backend.
+func (p *instancePool) createServer(ctx context.Context) error {
+	imageUUID, err := instanceImageUUID(ctx, p.meta, p.zone, p.template.Type, p.template.Image)
+	if err != nil {
+		return err
+	}
+
+	req := &instance.CreateServerRequest{
+		Zone:              p.zone,
+		Name:              newRandomName(p.name),
+		Project:           expandStringPtr(p.projectID),
+		Image:             imageUUID,
+		CommercialType:    p.template.Type,
+		SecurityGroup:     expandStringPtr(expandID(p.template.SecurityGroupID)),
+		PlacementGroup:    scw.StringPtr(p.id),
+		DynamicIPRequired: scw.BoolPtr(p.enableDynamicIP),
+		Tags:              p.serverTags(),
+	}
+	if p.template.RootVolumeType != "" || p.template.RootVolumeSizeInGB != 0 {
+		req.Volumes = map[string]*instance.VolumeServerTemplate{
+			"0": {
+				VolumeType: instance.VolumeVolumeType(p.template.RootVolumeType),
+				Size:       expandInstanceVolumeSize(scw.Size(uint64(p.template.RootVolumeSizeInGB) * gb)),
+			},
+		}
+		req.Volumes = sanitizeVolumeMap(req.Volumes)
+	}
+
+	res, err := p.api.CreateServer(req, scw.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	serverID := res.Server.ID
+
+	_, err = waitForInstanceServer(ctx, p.api, p.zone, serverID, defaultInstanceServerWaitTimeout)
+	if err != nil {
+		return err
+	}
+
+	userData := map[string]io.Reader{}
+	for key, value := range p.template.UserData {
+		userData[key] = bytes.NewBufferString(value)
+	}
+	if p.template.CloudInit != "" {
+		userData["cloud-init"] = bytes.NewBufferString(p.template.CloudInit)
+	}
+	if len(userData) > 0 {
+		err = p.api.SetAllServerUserData(&instance.SetAllServerUserDataRequest{
+			Zone:     p.zone,
+			ServerID: serverID,
+			UserData: userData,
+		}, scw.WithContext(ctx))
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, privateNetworkID := range p.template.PrivateNetworkIDs {
+		nic, err := p.api.CreatePrivateNIC(&instance.CreatePrivateNICRequest{
+			Zone:             p.zone,
+			ServerID:         serverID,
+			PrivateNetworkID: expandID(privateNetworkID),
+		}, scw.WithContext(ctx))
+		if err != nil {
+			return err
+		}
+		_, err = waitForPrivateNIC(ctx, p.api, p.zone, serverID, nic.PrivateNic.ID, defaultInstancePrivateNICWaitTimeout)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = reachState(ctx, p.api, p.zone, serverID, instance.ServerStateRunning, nil)
+	if err != nil {
+		return err
+	}
+
+	server, err := waitForInstanceServer(ctx, p.api, p.zone, serverID, defaultInstanceServerWaitTimeout)
+	if err != nil {
+		return err
+	}
+
+	return p.setBackendServer(ctx, p.lbBackendID, server, true)
+}
+
+// deleteServer removes a server from the load balancer backend, then stops and deletes it with its volumes.
+func (p *instancePool) deleteServer(ctx context.Context, server *instance.Server) error {
+	err := p.setBackendServer(ctx, p.lbBackendID, server, false)
+	if err != nil {
+		return err
+	}
+
+	return deleteInstanceServerAndVolumes(ctx, p.api, p.zone, server)
+}
+
+// deleteInstanceServerAndVolumes stops and deletes a server with its volumes.
+func deleteInstanceServerAndVolumes(ctx context.Context, api *instance.API, zone scw.Zone, server *instance.Server) error {
+	err := reachState(ctx, api, zone, server.ID, instance.ServerStateStopped, nil)
+	if err != nil && !is404Error(err) {
+		return err
+	}
+
+	err = api.DeleteServer(&instance.DeleteServerRequest{
+		Zone:     zone,
+		ServerID: server.ID,
+	}, scw.WithContext(ctx))
+	if err != nil && !is404Error(err) {
+		return err
+	}
+
+	_, err = waitForInstanceServer(ctx, api, zone, server.ID, defaultInstanceServerWaitTimeout)
+	if err != nil && !is404Error(err) {
+		return err
+	}
+
+	for _, volume := range server.Volumes {
+		err = api.DeleteVolume(&instance.DeleteVolumeRequest{
+			Zone:     zone,
+			VolumeID: volume.ID,
+		}, scw.WithContext(ctx))
+		if err != nil && !is404Error(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// setBackendServer adds or removes the IP of a server from a load balancer backend.
+func (p *instancePool) setBackendServer(ctx context.Context, backendID string, server *instance.Server, add bool) error {
+	if backendID == "" {
+		return nil
+	}
+	ip, err := instancePoolServerIP(ctx, p.meta, p.zone, server)
+	if err != nil || ip == "" {
+		return err
+	}
+
+	lbAPI, zone, id, err := lbAPIWithZoneAndID(p.meta, backendID)
+	if err != nil {
+		return err
+	}
+	if add {
+		_, err = lbAPI.AddBackendServers(&lbSDK.ZonedAPIAddBackendServersRequest{
+			Zone:      zone,
+			BackendID: id,
+			ServerIP:  []string{ip},
+		}, scw.WithContext(ctx))
+	} else {
+		_, err = lbAPI.RemoveBackendServers(&lbSDK.ZonedAPIRemoveBackendServersRequest{
+			Zone:      zone,
+			BackendID: id,
+			ServerIP:  []string{ip},
+		}, scw.WithContext(ctx))
+	}
+	if err != nil && !is404Error(err) {
+		return err
+	}
+	return nil
+}
+
+// rollout brings the pool to its size, replacing the servers created from another template revision.
+// Servers are replaced in batches of max_unavailable + max_surge: the surge servers are created first,
+// then the outdated servers are deleted and the remaining new servers are created.
+// At any time, at least size - max_unavailable servers are running and at most size + max_surge servers exist.
+func (p *instancePool) rollout(ctx context.Context, size int, maxUnavailable int, maxSurge int) error {
+	servers, err := listInstancePoolServers(ctx, p.api, p.zone, p.id)
+	if err != nil {
+		return err
+	}
+
+	var outdated, current []*instance.Server
+	for _, server := range servers {
+		if slices.Contains(server.Tags, p.templateTag()) {
+			current = append(current, server)
+		} else {
+			outdated = append(outdated, server)
+		}
+	}
+
+	// Scale down, deleting the outdated servers and then the newest servers first.
+	for len(outdated)+len(current) > size {
+		var server *instance.Server
+		if len(outdated) > 0 {
+			server, outdated = outdated[len(outdated)-1], outdated[:len(outdated)-1]
+		} else {
+			server, current = current[len(current)-1], current[:len(current)-1]
+		}
+		if err := p.deleteServer(ctx, server); err != nil {
+			return err
+		}
+	}
+	upToDate := len(current)
+
+	for len(outdated) > 0 {
+		batch := min(len(outdated), maxUnavailable+maxSurge)
+		surge := min(batch, maxSurge)
+		for i := 0; i < surge; i++ {
+			if err := p.createServer(ctx); err != nil {
+				return err
+			}
+		}
+		for _, server := range outdated[:batch] {
+			if err := p.deleteServer(ctx, server); err != nil {
+				return err
+			}
+		}
+		for i := surge; i < batch; i++ {
+			if err := p.createServer(ctx); err != nil {
+				return err
+			}
+		}
+		outdated = outdated[batch:]
+		upToDate += batch
+	}
+
+	// Scale up
+	for i := upToDate; i < size; i++ {
+		if err := p.createServer(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// expandInstancePool returns the pool of a scaleway_instance_pool, with its current template.
+func expandInstancePool(d *schema.ResourceData, meta interface{}) (*instancePool, error) {
+	instanceAPI, zone, id, err := instanceAPIWithZoneAndID(meta, d.Id())
+	if err != nil {
+		return nil, err
+	}
+	templateContent := d.Get("template").(string)
+	template, err := expandInstanceServerTemplateContent(templateContent)
+	if err != nil {
+		return nil, err
+	}
+	if template.Type == "" {
+		return nil, fmt.Errorf("server template has no type")
+	}
+
+	return &instancePool{
+		api:             instanceAPI,
+		meta:            meta,
+		zone:            zone,
+		id:              id,
+		name:            d.Get("name").(string),
+		projectID:       d.Get("project_id").(string),
+		tags:            expandTagsAll(d, meta),
+		templateContent: templateContent,
+		template:        template,
+		lbBackendID:     d.Get("lb_backend_id").(string),
+		enableDynamicIP: d.Get("enable_dynamic_ip").(bool),
+	}, nil
+}
+
+// customizeDiffInstancePool plans the replacement of the servers of a pool when its template changes.
+func customizeDiffInstancePool(_ context.Context, diff *schema.ResourceDiff, _ interface{}) error {
+	if diff.Get("max_unavailable").(int)+diff.Get("max_surge").(int) == 0 {
+		return fmt.Errorf("max_unavailable and max_surge cannot both be 0")
+	}
+
+	if diff.HasChange("size") || (diff.Id() != "" && diff.HasChange("template")) {
+		for _, key := range []string{"server_ids", "server_ips"} {
+			if err := diff.SetNewComputed(key); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// instanceSecurityGroupExternalRulesTag marks the security groups with external_rules set,
+// whose rules can be managed by scaleway_instance_security_group_rule resources.
+const instanceSecurityGroupExternalRulesTag = "scaleway_instance_security_group:external_rules"
+
+// instanceSecurityGroupRuleIdentity identifies a rule of a security group regardless of its position,
+// e.g. inbound,accept,TCP,22-22,0.0.0.0/0.
+func instanceSecurityGroupRuleIdentity(direction instance.SecurityGroupRuleDirection, rule *instance.SecurityGroupRule) (string, error) {
+	ipRange, err := flattenIPNet(rule.IPRange)
+	if err != nil {
+		return "", err
+	}
+	return strings.Join([]string{
+		direction.String(),
+		rule.Action.String(),
+		rule.Protocol.String(),
+		instanceSecurityGroupRulePortRange(rule),
+		ipRange,
+	}, ","), nil
+}
+
+// instanceSecurityGroupRulePortRange returns the port range of a rule, e.g. 22-22, or 0-0 for all ports.
+func instanceSecurityGroupRulePortRange(rule *instance.SecurityGroupRule) string {
+	portFrom, portTo := uint32(0), uint32(0)
+	if rule.DestPortFrom != nil {
+		portFrom = *rule.DestPortFrom
+		portTo = portFrom
+	}
+	if rule.DestPortTo != nil {
+		portTo = *rule.DestPortTo
+	}
+	return fmt.Sprintf("%d-%d", portFrom, portTo)
+}
+
+// parseInstanceSecurityGroupRuleID parses a {zone}/{security_group_id}/{identity} security group rule ID.
+func parseInstanceSecurityGroupRuleID(id string) (scw.Zone, string, instance.SecurityGroupRuleDirection, *instance.SecurityGroupRule, error) {
+	parts := strings.SplitN(id, "/", 3)
+	if len(parts) != 3 {
+		return "", "", "", nil, fmt.Errorf("invalid security group rule ID %q, expected {zone}/{security_group_id}/{direction},{action},{protocol},{port_from}-{port_to},{ip_range}", id)
+	}
+	fields := strings.Split(parts[2], ",")
+	if len(fields) != 5 {
+		return "", "", "", nil, fmt.Errorf("invalid security group rule %q, expected {direction},{action},{protocol},{port_from}-{port_to},{ip_range}", parts[2])
+	}
+	zone, err := scw.ParseZone(parts[0])
+	if err != nil {
+		return "", "", "", nil, err
+	}
+
+	portFrom, portTo := uint32(0), uint32(0)
+	if _, err := fmt.Sscanf(fields[3], "%d-%d", &portFrom, &portTo); err != nil {
+		return "", "", "", nil, fmt.Errorf("invalid port range %q: %w", fields[3], err)
+	}
+	ipRange, err := expandIPNet(fields[4])
+	if err != nil {
+		return "", "", "", nil, err
+	}
+	rule := &instance.SecurityGroupRule{
+		Action:   instance.SecurityGroupRuleAction(fields[1]),
+		Protocol: instance.SecurityGroupRuleProtocol(fields[2]),
+		IPRange:  ipRange,
+	}
+	if portFrom != 0 || portTo != 0 {
+		rule.DestPortFrom = &portFrom
+		if portTo != portFrom {
+			rule.DestPortTo = &portTo
+		}
+	}
+	return zone, parts[1], instance.SecurityGroupRuleDirection(fields[0]), rule, nil
+}
+
+// checkInstanceSecurityGroupExternalRules fails when the rules of a security group are managed by the security group itself.
+func checkInstanceSecurityGroupExternalRules(ctx context.Context, api *instance.API, zone scw.Zone, securityGroupID string) error {
+	res, err := api.GetSecurityGroup(&instance.GetSecurityGroupRequest{
+		Zone:            zone,
+		SecurityGroupID: securityGroupID,
+	}, scw.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	if !slices.Contains(res.SecurityGroup.Tags, instanceSecurityGroupExternalRulesTag) {
+		return fmt.Errorf("security group %s is not marked with external_rules, its rules would be overwritten by its scaleway_instance_security_group resource: set external_rules = true on the security group, or re-apply the security group if it is already set, to manage its rules separately", newZonedIDString(zone, securityGroupID))
+	}
+	return nil
+}
+
+// findInstanceSecurityGroupRule returns the editable rule of a security group with the given identity, or nil.
+func findInstanceSecurityGroupRule(ctx context.Context, api *instance.API, zone scw.Zone, securityGroupID string, identity string) (*instance.SecurityGroupRule, error) {
+	res, err := api.ListSecurityGroupRules(&instance.ListSecurityGroupRulesRequest{
+		Zone:            zone,
+		SecurityGroupID: securityGroupID,
+	}, scw.WithAllPages(), scw.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	for _, rule := range res.Rules {
+		if !rule.Editable {
+			continue
+		}
+		ruleIdentity, err := instanceSecurityGroupRuleIdentity(rule.Direction, rule)
+		if err != nil {
+			return nil, err
+		}
+		if ruleIdentity == identity {
+			return rule, nil
+		}
+	}
+	return nil, nil
+}
+
+const (
+	instanceImageBuildTag = "scaleway_instance_image_build"
+	// instanceImageBuildPrefixTagPrefix prefixes the tag holding the name prefix an image was built with.
+	instanceImageBuildPrefixTagPrefix = "instance-image-build-prefix="
+)
+
+// instanceImageBuildName returns the name of an image built with the prefix, suffixed by the build time.
+func instanceImageBuildName(namePrefix string) string {
+	return namePrefix + "-" + time.Now().UTC().Format("20060102-150405")
+}
+
+// instanceImageBuildNameRegexp matches the names returned by instanceImageBuildName for the prefix only,
+// e.g. web-20240101-120000 for web but not web-api-20240101-120000.
+func instanceImageBuildNameRegexp(namePrefix string) *regexp.Regexp {
+	return regexp.MustCompile(`^` + regexp.QuoteMeta(namePrefix) + `-\d{8}-\d{6}$`)
+}
+
+// instanceImageBuildTags returns the tags of an image built with the name prefix.
+func instanceImageBuildTags(tags []string, namePrefix string) []string {
+	return append(append([]string(nil), tags...), instanceImageBuildTag, instanceImageBuildPrefixTagPrefix+namePrefix)
+}
+
+// deleteInstanceImageAndSnapshots deletes an image, then the snapshots of its volumes.
+func deleteInstanceImageAndSnapshots(ctx context.Context, api *instance.API, zone scw.Zone, image *instance.Image, timeout time.Duration) error {
+	err := api.DeleteImage(&instance.DeleteImageRequest{
+		Zone:    zone,
+		ImageID: image.ID,
+	}, scw.WithContext(ctx))
+	if err != nil && !is404Error(err) {
+		return err
+	}
+	_, err = waitForInstanceImage(ctx, api, zone, image.ID, timeout)
+	if err != nil && !is404Error(err) {
+		return err
+	}
+
+	snapshotIDs := []string(nil)
+	if image.RootVolume != nil {
+		snapshotIDs = append(snapshotIDs, image.RootVolume.ID)
+	}
+	for _, volume := range image.ExtraVolumes {
+		snapshotIDs = append(snapshotIDs, volume.ID)
+	}
+	for _, snapshotID := range snapshotIDs {
+		err = api.DeleteSnapshot(&instance.DeleteSnapshotRequest{
+			Zone:       zone,
+			SnapshotID: snapshotID,
+		}, scw.WithContext(ctx))
+		if err != nil && !is404Error(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyInstanceImageBuildRetention keeps the newest images built with the name prefix, deleting the others with their snapshots.
+// A retention of 0 keeps every image, the image with keptID is never deleted.
+func applyInstanceImageBuildRetention(ctx context.Context, api *instance.API, zone scw.Zone, projectID string, namePrefix string, retention int, keptID string, timeout time.Duration) error {
+	if retention == 0 {
+		return nil
+	}
+
+	res, err := api.ListImages(&instance.ListImagesRequest{
+		Zone:    zone,
+		Project: &projectID,
+		Name:    &namePrefix,
+		Tags:    scw.StringPtr(instanceImageBuildTag),
+	}, scw.WithContext(ctx), scw.WithAllPages())
+	if err != nil {
+		return err
+	}
+
+	nameRegexp := instanceImageBuildNameRegexp(namePrefix)
+	images := []*instance.Image(nil)
+	for _, image := range res.Images {
+		if nameRegexp.MatchString(image.Name) && slices.Contains(image.Tags, instanceImageBuildPrefixTagPrefix+namePrefix) {
+			images = append(images, image)
+		}
+	}
+	sort.SliceStable(images, func(i, j int) bool {
+		if images[i].ID == keptID || images[j].ID == keptID {
+			return images[i].ID == keptID
+		}
+		creationI, creationJ := time.Time{}, time.Time{}
+		if images[i].CreationDate != nil {
+			creationI = *images[i].CreationDate
+		}
+		if images[j].CreationDate != nil {
+			creationJ = *images[j].CreationDate
+		}
+		return creationI.After(creationJ)
+	})
+
+	for i := retention; i < len(images); i++ {
+		err = deleteInstanceImageAndSnapshots(ctx, api, zone, images[i], timeout)
+		if err != nil {
+			return fmt.Errorf("couldn't delete image %s beyond the retention: %w", images[i].ID, err)
+		}
+	}
+	return nil
 }
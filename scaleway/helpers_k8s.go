@@ -1,15 +1,33 @@
 package scaleway
 
 import (
+	"bytes"
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	iam "github.com/scaleway/scaleway-sdk-go/api/iam/v1alpha1"
+	"github.com/scaleway/scaleway-sdk-go/api/instance/v1"
+	ipam "github.com/scaleway/scaleway-sdk-go/api/ipam/v1alpha1"
 	"github.com/scaleway/scaleway-sdk-go/api/k8s/v1"
 	"github.com/scaleway/scaleway-sdk-go/scw"
+	"golang.org/x/exp/slices"
 )
 
 const (
@@ -18,6 +36,44 @@ const (
 	defaultK8SRetryInterval  = 5 * time.Second
 )
 
+const (
+	k8sPoolReplacementStrategyRecreate  = "recreate"
+	k8sPoolReplacementStrategyBlueGreen = "blue_green"
+)
+
+const (
+	// k8sPoolLabelTagPrefix prefixes the pool tags Kapsule applies as node labels, e.g. noprefix=key=value.
+	k8sPoolLabelTagPrefix = "noprefix="
+	// k8sPoolTaintTagPrefix prefixes the pool tags Kapsule applies as node taints, e.g. taint=key=value:NoSchedule.
+	k8sPoolTaintTagPrefix = "taint="
+)
+
+var (
+	// k8sLabelKeyRegexp matches the Kubernetes label keys: a name, optionally prefixed by a DNS subdomain, e.g. example.com/name.
+	k8sLabelKeyRegexp = regexp.MustCompile(`^([a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*/)?[A-Za-z0-9]([-A-Za-z0-9_.]{0,61}[A-Za-z0-9])?$`)
+	// k8sLabelValueRegexp matches the Kubernetes label values, which may be empty.
+	k8sLabelValueRegexp = regexp.MustCompile(`^([A-Za-z0-9]([-A-Za-z0-9_.]{0,61}[A-Za-z0-9])?)?$`)
+)
+
+// validateK8SPoolLabels validates the keys and values of node labels, which Kapsule could not apply otherwise.
+func validateK8SPoolLabels() schema.SchemaValidateDiagFunc {
+	return validation.AllDiag(
+		validation.MapKeyMatch(k8sLabelKeyRegexp, "label keys must be a name of at most 63 alphanumeric characters, '-', '_' or '.', optionally prefixed by a DNS subdomain and '/'"),
+		validation.MapValueMatch(k8sLabelValueRegexp, "label values must be empty or at most 63 alphanumeric characters, '-', '_' or '.', starting and ending with an alphanumeric character"),
+	)
+}
+
+// k8sPoolBlueGreenFields are the fields of a pool that cannot be updated, a change replaces the pool according to its replacement strategy.
+var k8sPoolBlueGreenFields = []string{"node_type", "container_runtime", "root_volume_type", "placement_group_id"}
+
+// k8sPoolBlueGreenNameRegexp matches the names of the pools created by a blue/green replacement, see k8sPoolBlueGreenName.
+var k8sPoolBlueGreenNameRegexp = regexp.MustCompile(`^(.+)-bg[0-9a-z]+$`)
+
+// k8sPoolBlueGreenName returns the name of the pool replacing a pool, which cannot have the same name while both exist.
+func k8sPoolBlueGreenName(name string) string {
+	return fmt.Sprintf("%s-bg%s", name, strconv.FormatInt(time.Now().UnixNano(), 36))
+}
+
 func k8sAPIWithRegion(d *schema.ResourceData, m interface{}) (*k8s.API, scw.Region, error) {
 	meta := m.(*Meta)
 	k8sAPI := k8s.NewAPI(meta.scwClient)
@@ -75,6 +131,129 @@ func k8sGetLatestVersionFromMinor(ctx context.Context, k8sAPI *k8s.API, region s
 	return "", fmt.Errorf("no available upstream version found for %s", version)
 }
 
+// k8sVersion is a parsed x.y.z Kubernetes version
+type k8sVersion struct {
+	major, minor, patch int
+}
+
+func k8sParseVersion(version string) (k8sVersion, error) {
+	versionSplit := strings.Split(version, ".")
+	if len(versionSplit) != 3 {
+		return k8sVersion{}, fmt.Errorf("version should be like x.y.z not %s", version)
+	}
+	parsed := [3]int{}
+	for i, part := range versionSplit {
+		number, err := strconv.Atoi(part)
+		if err != nil {
+			return k8sVersion{}, fmt.Errorf("version should be like x.y.z not %s", version)
+		}
+		parsed[i] = number
+	}
+	return k8sVersion{major: parsed[0], minor: parsed[1], patch: parsed[2]}, nil
+}
+
+// canUpgradeTo returns true if a cluster can be upgraded in a single step to the target version:
+// the API only upgrades clusters to a newer patch of their minor version or to the next minor version.
+func (v k8sVersion) canUpgradeTo(target k8sVersion) bool {
+	if v.major != target.major {
+		return false
+	}
+	return (target.minor == v.minor && target.patch > v.patch) || target.minor == v.minor+1
+}
+
+// k8sGetUpgradePath returns the full versions a cluster goes through to be upgraded from a full version to another,
+// using the latest patch of each intermediate minor version.
+func k8sGetUpgradePath(ctx context.Context, k8sAPI *k8s.API, region scw.Region, from string, to string) ([]string, error) {
+	fromVersion, err := k8sParseVersion(from)
+	if err != nil {
+		return nil, err
+	}
+	toVersion, err := k8sParseVersion(to)
+	if err != nil {
+		return nil, err
+	}
+
+	// we let the API decide for the versions we cannot walk to, e.g. downgrades
+	path := []string(nil)
+	if fromVersion.major == toVersion.major {
+		for minor := fromVersion.minor + 1; minor < toVersion.minor; minor++ {
+			version, err := k8sGetLatestVersionFromMinor(ctx, k8sAPI, region, fmt.Sprintf("%d.%d", fromVersion.major, minor))
+			if err != nil {
+				return nil, err
+			}
+			path = append(path, version)
+		}
+	}
+
+	return append(path, to), nil
+}
+
+// upgradeK8SCluster upgrades a cluster through each version of the path, then its pools one at a time if upgradePools is set,
+// so that each pool is rolled out according to its upgrade policy. Each step is reported as a warning.
+func upgradeK8SCluster(ctx context.Context, k8sAPI *k8s.API, region scw.Region, clusterID string, path []string, upgradePools bool, timeout time.Duration) diag.Diagnostics {
+	diags := diag.Diagnostics(nil)
+
+	for _, version := range path {
+		cluster, err := k8sAPI.UpgradeCluster(&k8s.UpgradeClusterRequest{
+			Region:    region,
+			ClusterID: clusterID,
+			Version:   version,
+		}, scw.WithContext(ctx))
+		if err != nil {
+			return append(diags, diag.FromErr(err)...)
+		}
+
+		_, err = waitK8SCluster(ctx, k8sAPI, region, clusterID, timeout)
+		if err != nil {
+			return append(diags, diag.FromErr(err)...)
+		}
+
+		upgradedPools := []string(nil)
+		if upgradePools {
+			pools, err := k8sAPI.ListPools(&k8s.ListPoolsRequest{
+				Region:    region,
+				ClusterID: clusterID,
+			}, scw.WithAllPages(), scw.WithContext(ctx))
+			if err != nil {
+				return append(diags, diag.FromErr(err)...)
+			}
+
+			for _, pool := range pools.Pools {
+				if pool.Version == version {
+					continue
+				}
+				_, err = k8sAPI.UpgradePool(&k8s.UpgradePoolRequest{
+					Region:  region,
+					PoolID:  pool.ID,
+					Version: version,
+				}, scw.WithContext(ctx))
+				if err != nil {
+					return append(diags, diag.FromErr(fmt.Errorf("failed to upgrade pool %s to %s: %w", pool.Name, version, err))...)
+				}
+
+				// waitK8SClusterPool only waits for clusters without pools, each upgraded pool is waited for instead
+				_, err = waitK8SPoolReady(ctx, k8sAPI, region, pool.ID, timeout)
+				if err != nil {
+					return append(diags, diag.FromErr(err)...)
+				}
+				upgradedPools = append(upgradedPools, pool.Name)
+			}
+		}
+
+		detail := fmt.Sprintf("cluster %s was upgraded to %s", cluster.Name, version)
+		if len(upgradedPools) > 0 {
+			detail += fmt.Sprintf(", with its pools %s", strings.Join(upgradedPools, ", "))
+		}
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Warning,
+			Summary:  fmt.Sprintf("kubernetes cluster upgraded to %s", version),
+			Detail:   detail,
+		})
+	}
+
+	return diags
+}
+
 func waitK8SCluster(ctx context.Context, k8sAPI *k8s.API, region scw.Region, clusterID string, timeout time.Duration) (*k8s.Cluster, error) {
 	retryInterval := defaultK8SRetryInterval
 	if DefaultWaitRetryInterval != nil {
@@ -182,6 +361,149 @@ func getNodes(ctx context.Context, k8sAPI *k8s.API, pool *k8s.Pool) ([]map[strin
 	return convertNodes(nodes), nil
 }
 
+func waitK8SNodeReady(ctx context.Context, k8sAPI *k8s.API, region scw.Region, nodeID string, timeout time.Duration) (*k8s.Node, error) {
+	retryInterval := defaultK8SRetryInterval
+	if DefaultWaitRetryInterval != nil {
+		retryInterval = *DefaultWaitRetryInterval
+	}
+
+	node, err := k8sAPI.WaitForNode(&k8s.WaitForNodeRequest{
+		NodeID:        nodeID,
+		Region:        region,
+		Timeout:       scw.TimeDurationPtr(timeout),
+		RetryInterval: &retryInterval,
+	}, scw.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	if node.Status != k8s.NodeStatusReady {
+		if node.ErrorMessage != nil && *node.ErrorMessage != "" {
+			return nil, fmt.Errorf("node %s has state %s, wants %s: %s", nodeID, node.Status, k8s.NodeStatusReady, *node.ErrorMessage)
+		}
+		return nil, fmt.Errorf("node %s has state %s, wants %s", nodeID, node.Status, k8s.NodeStatusReady)
+	}
+	return node, nil
+}
+
+// k8sNodeInstanceServerID returns the ID of the instance server of a node from its provider ID, e.g. scaleway://instance/fr-par-1/<server-id>.
+// The nodes of external pools are not instance servers.
+func k8sNodeInstanceServerID(providerID string) (ZonedID, bool) {
+	zonedID, isInstance := strings.CutPrefix(providerID, "scaleway://instance/")
+	zone, serverID, found := strings.Cut(zonedID, "/")
+	if !isInstance || !found {
+		return ZonedID{}, false
+	}
+	return newZonedID(scw.Zone(zone), serverID), true
+}
+
+// k8sNodePrivateIPs returns the private IP addresses of a node: the ones of its private networks, then the one of its instance server.
+func k8sNodePrivateIPs(ctx context.Context, instanceAPI *instance.API, ipamAPI *ipam.API, node *k8s.Node) ([]string, error) {
+	serverID, isInstance := k8sNodeInstanceServerID(node.ProviderID)
+	if !isInstance {
+		return nil, nil
+	}
+
+	res, err := instanceAPI.GetServer(&instance.GetServerRequest{
+		Zone:     serverID.Zone,
+		ServerID: serverID.ID,
+	}, scw.WithContext(ctx))
+	if err != nil {
+		// The server of a node being replaced is deleted before the node gets a new provider ID.
+		if is404Error(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	privateIPs := []string(nil)
+	for _, nic := range res.Server.PrivateNics {
+		addresses, err := instancePrivateNICIPAddresses(ctx, ipamAPI, serverID.Zone, nic.ID)
+		if err != nil {
+			return nil, err
+		}
+		privateIPs = append(privateIPs, addresses...)
+	}
+	if res.Server.PrivateIP != nil {
+		privateIPs = append(privateIPs, *res.Server.PrivateIP)
+	}
+	return privateIPs, nil
+}
+
+func flattenK8SNode(node *k8s.Node, privateIPs []string) map[string]interface{} {
+	rawNode := map[string]interface{}{
+		"id":            newRegionalIDString(node.Region, node.ID),
+		"name":          node.Name,
+		"pool_id":       newRegionalIDString(node.Region, node.PoolID),
+		"status":        node.Status.String(),
+		"provider_id":   node.ProviderID,
+		"private_ips":   privateIPs,
+		"error_message": flattenStringPtr(node.ErrorMessage),
+		"created_at":    flattenTime(node.CreatedAt),
+		"updated_at":    flattenTime(node.UpdatedAt),
+	}
+	if node.PublicIPV4 != nil && node.PublicIPV4.String() != "<nil>" {
+		rawNode["public_ip"] = node.PublicIPV4.String()
+	}
+	if node.PublicIPV6 != nil && node.PublicIPV6.String() != "<nil>" {
+		rawNode["public_ip_v6"] = node.PublicIPV6.String()
+	}
+	if node.Conditions != nil {
+		rawNode["conditions"] = flattenMap(*node.Conditions)
+	}
+	return rawNode
+}
+
+// expandK8SPoolNodeTags returns the tags applying the labels and taints of a pool to its nodes.
+func expandK8SPoolNodeTags(d terraformResourceData) []string {
+	tags := []string(nil)
+	for key, value := range expandTagsMap(d.Get("labels")) {
+		tags = append(tags, k8sPoolLabelTagPrefix+key+"="+value)
+	}
+	for _, rawTaint := range d.Get("taints").(*schema.Set).List() {
+		taint := rawTaint.(map[string]interface{})
+		keyValue := taint["key"].(string)
+		if value := taint["value"].(string); value != "" {
+			keyValue += "=" + value
+		}
+		tags = append(tags, k8sPoolTaintTagPrefix+keyValue+":"+taint["effect"].(string))
+	}
+	sort.Strings(tags)
+	return tags
+}
+
+// flattenK8SPoolTags splits the tags of a pool into its other tags, node labels and taints.
+// Label and taint tags set in the configured tags are kept there, as before labels and taints were supported.
+func flattenK8SPoolTags(tags []string, configuredTags []string) ([]string, map[string]interface{}, []interface{}) {
+	otherTags := []string(nil)
+	labels := map[string]interface{}{}
+	taints := []interface{}(nil)
+	for _, tag := range tags {
+		if slices.Contains(configuredTags, tag) {
+			otherTags = append(otherTags, tag)
+			continue
+		}
+		if label, isLabel := strings.CutPrefix(tag, k8sPoolLabelTagPrefix); isLabel {
+			key, value, _ := strings.Cut(label, "=")
+			labels[key] = value
+			continue
+		}
+		if taint, isTaint := strings.CutPrefix(tag, k8sPoolTaintTagPrefix); isTaint {
+			if separator := strings.LastIndex(taint, ":"); separator > 0 {
+				key, value, _ := strings.Cut(taint[:separator], "=")
+				taints = append(taints, map[string]interface{}{
+					"key":    key,
+					"value":  value,
+					"effect": taint[separator+1:],
+				})
+				continue
+			}
+		}
+		otherTags = append(otherTags, tag)
+	}
+	return otherTags, labels, taints
+}
+
 func clusterAutoscalerConfigFlatten(cluster *k8s.Cluster) []map[string]interface{} {
 	autoscalerConfig := map[string]interface{}{}
 	autoscalerConfig["disable_scale_down"] = cluster.AutoscalerConfig.ScaleDownDisabled
@@ -256,3 +578,409 @@ func flattenKubeletArgs(args map[string]string) map[string]interface{} {
 
 	return kubeletArgs
 }
+
+// k8sFindClusterIDByName returns the ID of the cluster named name in the given region.
+func k8sFindClusterIDByName(ctx context.Context, k8sAPI *k8s.API, region scw.Region, name string) (string, error) {
+	res, err := k8sAPI.ListClusters(&k8s.ListClustersRequest{
+		Region: region,
+		Name:   &name,
+	}, scw.WithContext(ctx), scw.WithAllPages())
+	if err != nil {
+		return "", err
+	}
+
+	candidates := make(map[string]string, len(res.Clusters))
+	for _, cluster := range res.Clusters {
+		candidates[cluster.ID] = cluster.Name
+	}
+	return findIDByName("cluster", name, candidates)
+}
+
+// k8sClusterIDByName resolves the regional ID of a cluster imported by name.
+func k8sClusterIDByName(ctx context.Context, d *schema.ResourceData, meta *Meta, locality string, name string) (string, error) {
+	region, err := importRegion(d, meta, locality)
+	if err != nil {
+		return "", err
+	}
+
+	id, err := k8sFindClusterIDByName(ctx, k8s.NewAPI(meta.scwClient), region, name)
+	if err != nil {
+		return "", err
+	}
+	return newRegionalIDString(region, id), nil
+}
+
+// k8sPoolIDByName resolves the regional ID of a pool imported by name, the name being {cluster_name}/{pool_name}.
+func k8sPoolIDByName(ctx context.Context, d *schema.ResourceData, meta *Meta, locality string, name string) (string, error) {
+	region, err := importRegion(d, meta, locality)
+	if err != nil {
+		return "", err
+	}
+
+	clusterName, poolName, found := strings.Cut(name, "/")
+	if !found {
+		return "", fmt.Errorf("pools are imported by name using {region}/name:{cluster_name}/{pool_name}")
+	}
+
+	k8sAPI := k8s.NewAPI(meta.scwClient)
+	clusterID, err := k8sFindClusterIDByName(ctx, k8sAPI, region, clusterName)
+	if err != nil {
+		return "", err
+	}
+
+	res, err := k8sAPI.ListPools(&k8s.ListPoolsRequest{
+		Region:    region,
+		ClusterID: clusterID,
+		Name:      &poolName,
+	}, scw.WithContext(ctx), scw.WithAllPages())
+	if err != nil {
+		return "", err
+	}
+
+	candidates := make(map[string]string, len(res.Pools))
+	for _, pool := range res.Pools {
+		candidates[pool.ID] = pool.Name
+	}
+	id, err := findIDByName("pool", poolName, candidates)
+	if err != nil {
+		return "", err
+	}
+	return newRegionalIDString(region, id), nil
+}
+
+// k8sKubeClient calls the Kubernetes API of a cluster with the credentials of its admin kubeconfig.
+type k8sKubeClient struct {
+	httpClient *http.Client
+	server     string
+	token      string
+}
+
+// k8sKubeError is an error returned by the Kubernetes API of a cluster.
+type k8sKubeError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *k8sKubeError) Error() string {
+	return fmt.Sprintf("kubernetes API error %d: %s", e.StatusCode, e.Message)
+}
+
+func newK8SKubeClient(ctx context.Context, meta *Meta, k8sAPI *k8s.API, region scw.Region, clusterID string) (*k8sKubeClient, error) {
+	kubeconfig, err := k8sAPI.GetClusterKubeConfig(&k8s.GetClusterKubeConfigRequest{
+		Region:    region,
+		ClusterID: clusterID,
+	}, scw.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	server, err := kubeconfig.GetServer()
+	if err != nil {
+		return nil, err
+	}
+	token, err := kubeconfig.GetToken()
+	if err != nil {
+		return nil, err
+	}
+
+	httpClient := meta.kubeHTTPClient
+	if httpClient == nil {
+		rawCA, err := kubeconfig.GetCertificateAuthorityData()
+		if err != nil {
+			return nil, err
+		}
+		ca, err := base64.StdEncoding.DecodeString(rawCA)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode the CA certificate of cluster %s: %w", clusterID, err)
+		}
+		rootCAs := x509.NewCertPool()
+		if !rootCAs.AppendCertsFromPEM(ca) {
+			return nil, fmt.Errorf("invalid CA certificate for cluster %s", clusterID)
+		}
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.TLSClientConfig = &tls.Config{RootCAs: rootCAs, MinVersion: tls.VersionTLS12}
+		httpClient = &http.Client{Transport: transport}
+	}
+
+	return &k8sKubeClient{
+		httpClient: httpClient,
+		server:     strings.TrimSuffix(server, "/"),
+		token:      token,
+	}, nil
+}
+
+func (c *k8sKubeClient) do(ctx context.Context, method string, path string, contentType string, body interface{}, response interface{}) error {
+	var reqBody io.Reader
+	if body != nil {
+		rawBody, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reqBody = bytes.NewReader(rawBody)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.server+path, reqBody)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("Accept", "application/json")
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		status := struct {
+			Message string `json:"message"`
+		}{}
+		_ = json.NewDecoder(resp.Body).Decode(&status)
+		return &k8sKubeError{StatusCode: resp.StatusCode, Message: status.Message}
+	}
+	if response != nil {
+		return json.NewDecoder(resp.Body).Decode(response)
+	}
+	return nil
+}
+
+// k8sKubePod holds the fields of a pod needed to drain its node.
+type k8sKubePod struct {
+	Metadata struct {
+		Name            string            `json:"name"`
+		Namespace       string            `json:"namespace"`
+		Annotations     map[string]string `json:"annotations"`
+		OwnerReferences []struct {
+			Kind string `json:"kind"`
+		} `json:"ownerReferences"`
+	} `json:"metadata"`
+	Status struct {
+		Phase string `json:"phase"`
+	} `json:"status"`
+}
+
+// isEvictable returns false for the pods a drain leaves on the node, as kubectl drain does:
+// the ones managed by a DaemonSet, the static ones and the terminated ones.
+func (p *k8sKubePod) isEvictable() bool {
+	if _, isMirror := p.Metadata.Annotations["kubernetes.io/config.mirror"]; isMirror {
+		return false
+	}
+	for _, owner := range p.Metadata.OwnerReferences {
+		if owner.Kind == "DaemonSet" {
+			return false
+		}
+	}
+	return p.Status.Phase != "Succeeded" && p.Status.Phase != "Failed"
+}
+
+// cordonNode marks a node as unschedulable.
+func (c *k8sKubeClient) cordonNode(ctx context.Context, name string) error {
+	return c.do(ctx, http.MethodPatch, "/api/v1/nodes/"+url.PathEscape(name), "application/merge-patch+json", map[string]interface{}{
+		"spec": map[string]interface{}{"unschedulable": true},
+	}, nil)
+}
+
+// uncordonNode marks a node as schedulable again.
+func (c *k8sKubeClient) uncordonNode(ctx context.Context, name string) error {
+	return c.do(ctx, http.MethodPatch, "/api/v1/nodes/"+url.PathEscape(name), "application/merge-patch+json", map[string]interface{}{
+		"spec": map[string]interface{}{"unschedulable": false},
+	}, nil)
+}
+
+// drainNode evicts the pods of a node until none is left, retrying the evictions denied by a pod disruption budget.
+func (c *k8sKubeClient) drainNode(ctx context.Context, name string, timeout time.Duration) error {
+	retryInterval := defaultK8SRetryInterval
+	if DefaultWaitRetryInterval != nil {
+		retryInterval = *DefaultWaitRetryInterval
+	}
+	deadline := time.Now().Add(timeout)
+
+	for {
+		pods := struct {
+			Items []*k8sKubePod `json:"items"`
+		}{}
+		err := c.do(ctx, http.MethodGet, "/api/v1/pods?fieldSelector="+url.QueryEscape("spec.nodeName="+name), "", nil, &pods)
+		if err != nil {
+			return err
+		}
+
+		remaining := 0
+		for _, pod := range pods.Items {
+			if !pod.isEvictable() {
+				continue
+			}
+			remaining++
+			err = c.do(ctx, http.MethodPost, fmt.Sprintf("/api/v1/namespaces/%s/pods/%s/eviction", url.PathEscape(pod.Metadata.Namespace), url.PathEscape(pod.Metadata.Name)), "application/json", map[string]interface{}{
+				"apiVersion": "policy/v1",
+				"kind":       "Eviction",
+				"metadata": map[string]interface{}{
+					"name":      pod.Metadata.Name,
+					"namespace": pod.Metadata.Namespace,
+				},
+			}, nil)
+			kubeErr := (*k8sKubeError)(nil)
+			if err != nil && (!errors.As(err, &kubeErr) || (kubeErr.StatusCode != http.StatusNotFound && kubeErr.StatusCode != http.StatusTooManyRequests)) {
+				return fmt.Errorf("failed to evict pod %s/%s: %w", pod.Metadata.Namespace, pod.Metadata.Name, err)
+			}
+		}
+		if remaining == 0 {
+			return nil
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("timeout while draining node %s: %d pods left", name, remaining)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(retryInterval):
+		}
+	}
+}
+
+// replaceK8SPoolBlueGreen replaces a pool by a new one matching the configuration, without losing capacity:
+// the old pool is only deleted once the new one is ready and its nodes are drained.
+// The old pool is kept in the state until it is deleted, on failure its nodes are uncordoned and the new pool is deleted.
+func replaceK8SPoolBlueGreen(ctx context.Context, d *schema.ResourceData, meta interface{}, k8sAPI *k8s.API, region scw.Region, oldPoolID string) error {
+	timeout := d.Timeout(schema.TimeoutUpdate)
+
+	req := expandK8SPoolCreateRequest(d, meta, region)
+	req.Name = k8sPoolBlueGreenName(d.Get("name").(string))
+	pool, err := k8sAPI.CreatePool(req, scw.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+
+	var kubeClient *k8sKubeClient
+	cordonedNodes := []string(nil)
+	rollback := func(err error) error {
+		// the old pool and its configuration stay in the state
+		d.Partial(true)
+		// the rollback must also run when the update timed out
+		ctx := context.WithoutCancel(ctx)
+		for _, name := range cordonedNodes {
+			if uncordonErr := kubeClient.uncordonNode(ctx, name); uncordonErr != nil {
+				err = fmt.Errorf("%w, failed to uncordon node %s: %s", err, name, uncordonErr)
+			}
+		}
+		_, deleteErr := k8sAPI.DeletePool(&k8s.DeletePoolRequest{
+			Region: region,
+			PoolID: pool.ID,
+		}, scw.WithContext(ctx))
+		if deleteErr != nil && !is404Error(deleteErr) {
+			err = fmt.Errorf("%w, failed to delete replacing pool %s: %s", err, pool.ID, deleteErr)
+		}
+		return err
+	}
+
+	_, err = waitK8SPoolReady(ctx, k8sAPI, region, pool.ID, timeout)
+	if err != nil {
+		return rollback(fmt.Errorf("replacing pool %s was not ready, pool %s is kept: %w", pool.ID, oldPoolID, err))
+	}
+
+	nodes, err := k8sAPI.ListNodes(&k8s.ListNodesRequest{
+		Region:    region,
+		ClusterID: pool.ClusterID,
+		PoolID:    &oldPoolID,
+	}, scw.WithAllPages(), scw.WithContext(ctx))
+	if err != nil {
+		return rollback(err)
+	}
+
+	kubeClient, err = newK8SKubeClient(ctx, meta.(*Meta), k8sAPI, region, pool.ClusterID)
+	if err != nil {
+		return rollback(err)
+	}
+
+	// every node is cordoned first so that the evicted pods are not scheduled on the other nodes of the old pool
+	for _, node := range nodes.Nodes {
+		err = kubeClient.cordonNode(ctx, node.Name)
+		if err != nil {
+			return rollback(fmt.Errorf("failed to cordon node %s, pool %s is kept: %w", node.Name, oldPoolID, err))
+		}
+		cordonedNodes = append(cordonedNodes, node.Name)
+	}
+	for _, node := range nodes.Nodes {
+		err = kubeClient.drainNode(ctx, node.Name, timeout)
+		if err != nil {
+			return rollback(fmt.Errorf("failed to drain node %s, pool %s is kept: %w", node.Name, oldPoolID, err))
+		}
+	}
+
+	// the workloads now run on the new pool, which is the one matching the configuration: it is kept whatever happens to the old one
+	d.SetId(newRegionalIDString(region, pool.ID))
+
+	_, err = k8sAPI.DeletePool(&k8s.DeletePoolRequest{
+		Region: region,
+		PoolID: oldPoolID,
+	}, scw.WithContext(ctx))
+	if err != nil && !is404Error(err) {
+		return fmt.Errorf("pool %s was replaced by pool %s but could not be deleted, it must be removed outside of terraform: %w", newRegionalIDString(region, oldPoolID), d.Id(), err)
+	}
+
+	return nil
+}
+
+// k8sCredentialsCache keeps the API keys minted for the credentials of clusters by the provider, keyed by application and cluster.
+// The secret key of an API key is only returned on its creation, an API key minted by a previous run cannot be reused.
+type k8sCredentialsCache struct {
+	mu      sync.Mutex
+	apiKeys map[string]*iam.APIKey
+}
+
+// get returns the API key minted for the key, if it is still valid for at least the given duration.
+func (c *k8sCredentialsCache) get(key string, validFor time.Duration) *iam.APIKey {
+	if c == nil {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	apiKey, exists := c.apiKeys[key]
+	if !exists || apiKey.ExpiresAt == nil || apiKey.ExpiresAt.Before(time.Now().Add(validFor)) {
+		return nil
+	}
+	return apiKey
+}
+
+func (c *k8sCredentialsCache) set(key string, apiKey *iam.APIKey) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.apiKeys == nil {
+		c.apiKeys = map[string]*iam.APIKey{}
+	}
+	c.apiKeys[key] = apiKey
+}
+
+// k8sClusterCredentialsDescription is the description of the API keys minted for the credentials of a cluster.
+func k8sClusterCredentialsDescription(region scw.Region, clusterID string) string {
+	return "Credentials of the kubernetes cluster " + newRegionalIDString(region, clusterID)
+}
+
+// deleteExpiredK8SClusterCredentials deletes the expired API keys minted for the credentials of a cluster.
+func deleteExpiredK8SClusterCredentials(ctx context.Context, api *iam.API, applicationID string, description string) error {
+	res, err := api.ListAPIKeys(&iam.ListAPIKeysRequest{
+		ApplicationID: &applicationID,
+		Description:   &description,
+	}, scw.WithAllPages(), scw.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	for _, apiKey := range res.APIKeys {
+		if apiKey.Description != description || apiKey.ExpiresAt == nil || apiKey.ExpiresAt.After(time.Now()) {
+			continue
+		}
+		err = api.DeleteAPIKey(&iam.DeleteAPIKeyRequest{
+			AccessKey: apiKey.AccessKey,
+		}, scw.WithContext(ctx))
+		if err != nil && !is404Error(err) {
+			return err
+		}
+	}
+	return nil
+}
@@ -589,3 +589,29 @@ func ipv4Match(cidr, ipStr string) bool {
 
 	return cidrNet.Contains(ip)
 }
+
+// lbIDByName resolves the zoned ID of a load balancer imported by name.
+func lbIDByName(ctx context.Context, d *schema.ResourceData, meta *Meta, locality string, name string) (string, error) {
+	zone, err := importZone(d, meta, locality)
+	if err != nil {
+		return "", err
+	}
+
+	res, err := lbSDK.NewZonedAPI(meta.scwClient).ListLBs(&lbSDK.ZonedAPIListLBsRequest{
+		Zone: zone,
+		Name: &name,
+	}, scw.WithContext(ctx), scw.WithAllPages())
+	if err != nil {
+		return "", err
+	}
+
+	candidates := make(map[string]string, len(res.LBs))
+	for _, lb := range res.LBs {
+		candidates[lb.ID] = lb.Name
+	}
+	id, err := findIDByName("load balancer", name, candidates)
+	if err != nil {
+		return "", err
+	}
+	return newZonedIDString(zone, id), nil
+}
@@ -195,6 +195,19 @@ func expandObjectBucketTags(tags interface{}) []*s3.Tag {
 	return tagsSet
 }
 
+// expandObjectBucketTagsAll returns the object tags merged with the provider default tags.
+func expandObjectBucketTagsAll(d terraformResourceData, meta interface{}) []*s3.Tag {
+	tagsSet := []*s3.Tag(nil)
+	for key, value := range expandMapTagsAll(d, meta) {
+		tagsSet = append(tagsSet, &s3.Tag{
+			Key:   scw.StringPtr(key),
+			Value: scw.StringPtr(value),
+		})
+	}
+
+	return tagsSet
+}
+
 func objectBucketEndpointURL(bucketName string, region scw.Region) string {
 	return fmt.Sprintf("https://%s.s3.%s.scw.cloud", bucketName, region)
 }
@@ -361,3 +361,29 @@ func rdbPrivilegeUpgradeV1SchemaType() cty.Type {
 		"id": cty.String,
 	})
 }
+
+// rdbInstanceIDByName resolves the regional ID of a database instance imported by name.
+func rdbInstanceIDByName(ctx context.Context, d *schema.ResourceData, meta *Meta, locality string, name string) (string, error) {
+	region, err := importRegion(d, meta, locality)
+	if err != nil {
+		return "", err
+	}
+
+	res, err := newRdbAPI(meta).ListInstances(&rdb.ListInstancesRequest{
+		Region: region,
+		Name:   &name,
+	}, scw.WithContext(ctx), scw.WithAllPages())
+	if err != nil {
+		return "", err
+	}
+
+	candidates := make(map[string]string, len(res.Instances))
+	for _, instance := range res.Instances {
+		candidates[instance.ID] = instance.Name
+	}
+	id, err := findIDByName("database instance", name, candidates)
+	if err != nil {
+		return "", err
+	}
+	return newRegionalIDString(region, id), nil
+}
@@ -98,3 +98,29 @@ func waitForRegistryNamespaceDelete(ctx context.Context, api *registry.API, regi
 		}
 	}
 }
+
+// registryNamespaceIDByName resolves the regional ID of a namespace imported by name.
+func registryNamespaceIDByName(ctx context.Context, d *schema.ResourceData, meta *Meta, locality string, name string) (string, error) {
+	region, err := importRegion(d, meta, locality)
+	if err != nil {
+		return "", err
+	}
+
+	res, err := registry.NewAPI(meta.scwClient).ListNamespaces(&registry.ListNamespacesRequest{
+		Region: region,
+		Name:   &name,
+	}, scw.WithContext(ctx), scw.WithAllPages())
+	if err != nil {
+		return "", err
+	}
+
+	candidates := make(map[string]string, len(res.Namespaces))
+	for _, namespace := range res.Namespaces {
+		candidates[namespace.ID] = namespace.Name
+	}
+	id, err := findIDByName("namespace", name, candidates)
+	if err != nil {
+		return "", err
+	}
+	return newRegionalIDString(region, id), nil
+}
@@ -1,6 +1,7 @@
 package scaleway
 
 import (
+	"context"
 	"fmt"
 	"net"
 	"net/http"
@@ -9,7 +10,9 @@ import (
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/scaleway/scaleway-sdk-go/api/k8s/v1"
 	"github.com/scaleway/scaleway-sdk-go/scw"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -204,6 +207,111 @@ func TestNewRegionalId(t *testing.T) {
 	assert.Equal(t, "fr-par/my-id", newRegionalIDString(scw.RegionFrPar, "my-id"))
 }
 
+func TestParseImportByNameID(t *testing.T) {
+	testCases := []struct {
+		name     string
+		importID string
+		locality string
+		resource string
+		ok       bool
+	}{
+		{
+			name:     "zoned",
+			importID: "fr-par-1/name:my-server",
+			locality: "fr-par-1",
+			resource: "my-server",
+			ok:       true,
+		},
+		{
+			name:     "without locality",
+			importID: "name:my-server",
+			resource: "my-server",
+			ok:       true,
+		},
+		{
+			name:     "nested name",
+			importID: "fr-par/name:my-cluster/my-pool",
+			locality: "fr-par",
+			resource: "my-cluster/my-pool",
+			ok:       true,
+		},
+		{
+			name:     "id",
+			importID: "fr-par-1/11111111-1111-1111-1111-111111111111",
+		},
+		{
+			name:     "empty name",
+			importID: "fr-par-1/name:",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			locality, name, ok := parseImportByNameID(tc.importID)
+			assert.Equal(t, tc.ok, ok)
+			assert.Equal(t, tc.locality, locality)
+			assert.Equal(t, tc.resource, name)
+		})
+	}
+}
+
+func TestFindIDByName(t *testing.T) {
+	candidates := map[string]string{
+		"id-1": "web",
+		"id-2": "web-2",
+		"id-3": "db",
+		"id-4": "db",
+	}
+
+	id, err := findIDByName("server", "web", candidates)
+	require.NoError(t, err)
+	assert.Equal(t, "id-1", id)
+
+	_, err = findIDByName("server", "cache", candidates)
+	require.EqualError(t, err, "no server found with the name cache")
+
+	_, err = findIDByName("server", "db", candidates)
+	require.EqualError(t, err, "2 servers found with the name db, import one of them by id: id-3, id-4")
+}
+
+func TestAddImportDefaults(t *testing.T) {
+	r := &schema.Resource{
+		Importer: &schema.ResourceImporter{
+			StateContext: func(_ context.Context, d *schema.ResourceData, _ interface{}) ([]*schema.ResourceData, error) {
+				_ = d.Set("name", "imported")
+				return []*schema.ResourceData{d}, nil
+			},
+		},
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  "default",
+			},
+			"wait": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+			"size": {
+				Type:     schema.TypeInt,
+				Optional: true,
+			},
+		},
+	}
+	addImportDefaults(r)
+
+	d := r.Data(nil)
+	d.SetId("fr-par-1/11111111-1111-1111-1111-111111111111")
+	states, err := r.Importer.StateContext(context.Background(), d, nil)
+	require.NoError(t, err)
+	require.Len(t, states, 1)
+	assert.Equal(t, "imported", states[0].Get("name"))
+	assert.Equal(t, true, states[0].Get("wait"))
+	_, sizeIsSet := states[0].GetOk("size")
+	assert.False(t, sizeIsSet)
+}
+
 func TestIsHTTPCodeError(t *testing.T) {
 	assert.True(t, isHTTPCodeError(&scw.ResponseError{StatusCode: http.StatusBadRequest}, http.StatusBadRequest))
 	assert.False(t, isHTTPCodeError(nil, http.StatusBadRequest))
@@ -302,3 +410,67 @@ func TestStringHashcode_positiveIndex(t *testing.T) {
 		}
 	}
 }
+
+func TestFakeAPI_ImportByName(t *testing.T) {
+	tt := NewFakeAPITestTools(t)
+	defer tt.Cleanup()
+	ctx := context.Background()
+
+	importID := func(resource *schema.Resource, id string) (string, error) {
+		d := resource.Data(nil)
+		d.SetId(id)
+		states, err := resource.Importer.StateContext(ctx, d, tt.Meta)
+		if err != nil {
+			return "", err
+		}
+		return states[0].Id(), nil
+	}
+
+	// The fake API filters names by substring, like the real one, so network-2 also matches name=network.
+	var network *schema.ResourceData
+	for _, name := range []string{"network", "network-2", "network-2"} {
+		d := schema.TestResourceDataRaw(t, resourceScalewayVPCPrivateNetwork().Schema, map[string]interface{}{"name": name})
+		diags := resourceScalewayVPCPrivateNetworkCreate(ctx, d, tt.Meta)
+		require.False(t, diags.HasError(), diags)
+		if name == "network" {
+			network = d
+		}
+	}
+
+	id, err := importID(resourceScalewayVPCPrivateNetwork(), "fr-par-1/name:network")
+	require.NoError(t, err)
+	assert.Equal(t, network.Id(), id)
+
+	id, err = importID(resourceScalewayVPCPrivateNetwork(), "name:network")
+	require.NoError(t, err)
+	assert.Equal(t, network.Id(), id)
+
+	_, err = importID(resourceScalewayVPCPrivateNetwork(), "fr-par-1/name:network-2")
+	assert.ErrorContains(t, err, "2 private networks found with the name network-2")
+
+	_, err = importID(resourceScalewayVPCPrivateNetwork(), "fr-par-2/name:network")
+	assert.ErrorContains(t, err, "no private network found with the name network")
+
+	id, err = importID(resourceScalewayVPCPrivateNetwork(), network.Id())
+	require.NoError(t, err)
+	assert.Equal(t, network.Id(), id)
+
+	k8sAPI := k8s.NewAPI(tt.Meta.scwClient)
+	cluster, err := k8sAPI.CreateCluster(&k8s.CreateClusterRequest{
+		Region:  scw.RegionFrPar,
+		Name:    "cluster",
+		Version: "1.26.2",
+		Pools:   []*k8s.CreateClusterRequestPoolConfig{{Name: "default", Size: 1}},
+	})
+	require.NoError(t, err)
+	pools, err := k8sAPI.ListPools(&k8s.ListPoolsRequest{Region: scw.RegionFrPar, ClusterID: cluster.ID})
+	require.NoError(t, err)
+	require.Len(t, pools.Pools, 1)
+
+	id, err = importID(resourceScalewayK8SPool(), "fr-par/name:cluster/default")
+	require.NoError(t, err)
+	assert.Equal(t, newRegionalIDString(scw.RegionFrPar, pools.Pools[0].ID), id)
+
+	_, err = importID(resourceScalewayK8SPool(), "fr-par/name:default")
+	assert.ErrorContains(t, err, "{region}/name:{cluster_name}/{pool_name}")
+}
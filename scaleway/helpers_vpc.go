@@ -1,6 +1,7 @@
 package scaleway
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -40,3 +41,29 @@ func vpcAPI(m interface{}) (*vpc.API, error) {
 
 	return vpc.NewAPI(meta.scwClient), nil
 }
+
+// vpcPrivateNetworkIDByName resolves the zoned ID of a private network imported by name.
+func vpcPrivateNetworkIDByName(ctx context.Context, d *schema.ResourceData, meta *Meta, locality string, name string) (string, error) {
+	zone, err := importZone(d, meta, locality)
+	if err != nil {
+		return "", err
+	}
+
+	res, err := vpc.NewAPI(meta.scwClient).ListPrivateNetworks(&vpc.ListPrivateNetworksRequest{
+		Zone: zone,
+		Name: &name,
+	}, scw.WithContext(ctx), scw.WithAllPages())
+	if err != nil {
+		return "", err
+	}
+
+	candidates := make(map[string]string, len(res.PrivateNetworks))
+	for _, pn := range res.PrivateNetworks {
+		candidates[pn.ID] = pn.Name
+	}
+	id, err := findIDByName("private network", name, candidates)
+	if err != nil {
+		return "", err
+	}
+	return newZonedIDString(zone, id), nil
+}
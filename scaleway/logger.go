@@ -1,46 +1,189 @@
 package scaleway
 
 import (
-	"log"
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
 
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/logging"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	sdkLogger "github.com/scaleway/scaleway-sdk-go/logger"
 )
 
+const (
+	// loggerEnvPrefix is the prefix of the environment variables used to set the level of a subsystem,
+	// e.g. TF_LOG_PROVIDER_SCALEWAY_INSTANCE=TRACE.
+	loggerEnvPrefix = "TF_LOG_PROVIDER_SCALEWAY"
+	// sdkLoggerSubsystem is the subsystem used by logs emitted by the Scaleway SDK and the retryable HTTP client.
+	sdkLoggerSubsystem = "sdk"
+)
+
+// secretFieldKeys are the keys whose values are masked in logs.
+var secretFieldKeys = []string{"secret_key", "password", "token", "kubeconfig"}
+
+var (
+	secretJSONFieldRegexp = regexp.MustCompile(`("(?:secret_key|password|token|kubeconfig)"\s*:\s*)"(?:[^"\\]|\\.)*"`)
+	// secretDataJSONFieldRegexp matches the payload of secret versions and kubeconfigs.
+	secretDataJSONFieldRegexp = regexp.MustCompile(`("(?:data|content)"\s*:\s*)"(?:[^"\\]|\\.)*"`)
+	secretPathRegexp          = regexp.MustCompile(`/secret-manager/|/kubeconfig`)
+)
+
+// redactSecrets masks the values of secret fields found in free-form messages, e.g. SDK logs.
+// As the API path of such messages is unknown, secret versions data and kubeconfig contents are always masked.
+func redactSecrets(s string) string {
+	return redactSecretData(redactSecretFields(s))
+}
+
+// redactBody masks the values of secret fields found in a body sent to or received from the given API path.
+// Secret versions data and kubeconfig contents are only masked when the path is related to those APIs.
+func redactBody(path string, body string) string {
+	body = redactSecretFields(body)
+	if secretPathRegexp.MatchString(path) {
+		body = redactSecretData(body)
+	}
+	return body
+}
+
+func redactSecretFields(s string) string {
+	return secretJSONFieldRegexp.ReplaceAllString(s, `$1"***"`)
+}
+
+func redactSecretData(s string) string {
+	return secretDataJSONFieldRegexp.ReplaceAllString(s, `$1"***"`)
+}
+
+// newLoggerSubsystem creates a tflog subsystem masking secret fields.
+// It includes the fields of the provider logger, e.g. the resource fields set by addLoggerFields.
+// Its level can be set with the TF_LOG_PROVIDER_SCALEWAY_<SUBSYSTEM> environment variable.
+func newLoggerSubsystem(ctx context.Context, subsystem string) context.Context {
+	ctx = tflog.NewSubsystem(ctx, subsystem, tflog.WithLevelFromEnv(loggerEnvPrefix, subsystem), tflog.WithRootFields())
+	return tflog.SubsystemMaskFieldValuesWithFieldKeys(ctx, subsystem, secretFieldKeys...)
+}
+
+// addLoggerFields sets the type and the ID of a resource as fields of the logs emitted by its CRUD functions,
+// including the ones of the requests they send.
+// Terraform does not send resource addresses to providers, the ID is the closest identifier available.
+func addLoggerFields(resourceType string, resource *schema.Resource) {
+	withFields := func(ctx context.Context, d *schema.ResourceData) context.Context {
+		ctx = tflog.SetField(ctx, "scaleway_resource_type", resourceType)
+		if d.Id() != "" {
+			ctx = tflog.SetField(ctx, "scaleway_resource_id", d.Id())
+		}
+		return ctx
+	}
+	wrap := func(f func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics) func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics {
+		if f == nil {
+			return nil
+		}
+		return func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+			return f(withFields(ctx, d), d, m)
+		}
+	}
+
+	resource.CreateContext = wrap(resource.CreateContext)
+	resource.ReadContext = wrap(resource.ReadContext)
+	resource.UpdateContext = wrap(resource.UpdateContext)
+	resource.DeleteContext = wrap(resource.DeleteContext)
+}
+
+// apiLoggerSubsystem returns the subsystem used to log requests sent to an API, e.g. instance for /instance/v1.
+func apiLoggerSubsystem(host string, path string) string {
+	if prefix := apiPathPrefix(path); prefix != "" {
+		product := strings.SplitN(strings.TrimPrefix(prefix, "/"), "/", 2)[0]
+		return strings.ReplaceAll(product, "-", "_")
+	}
+	if strings.HasPrefix(host, "s3.") || strings.Contains(host, ".s3.") {
+		return "object"
+	}
+	return "api"
+}
+
 // logger is the implementation of the SDK Logger interface for this terraform plugin.
+// It also implements the retryablehttp LeveledLogger interface.
+//
+// As the SDK does not pass a context when logging, logs are sent to the tflog context given when configuring the provider.
 //
 // cf. https://godoc.org/github.com/scaleway/scaleway-sdk-go/logger#Logger
-type logger struct{}
+type logger struct {
+	mu  sync.RWMutex
+	ctx context.Context
+}
 
 // l is the global logger singleton
-var l = logger{}
+var l = &logger{ctx: context.Background()}
+
+// setContext sets the context logs are sent to.
+func (l *logger) setContext(ctx context.Context) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.ctx = newLoggerSubsystem(ctx, sdkLoggerSubsystem)
+}
+
+func (l *logger) context() context.Context {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.ctx
+}
 
 // Debugf logs to the DEBUG log. Arguments are handled in the manner of fmt.Printf.
-func (l logger) Debugf(format string, args ...interface{}) {
-	log.Printf("[DEBUG] "+format, args...)
+func (l *logger) Debugf(format string, args ...interface{}) {
+	tflog.SubsystemDebug(l.context(), sdkLoggerSubsystem, redactSecrets(fmt.Sprintf(format, args...)))
 }
 
 // Infof logs to the INFO log. Arguments are handled in the manner of fmt.Printf.
-func (l logger) Infof(format string, args ...interface{}) {
-	log.Printf("[INFO] "+format, args...)
+func (l *logger) Infof(format string, args ...interface{}) {
+	tflog.SubsystemInfo(l.context(), sdkLoggerSubsystem, redactSecrets(fmt.Sprintf(format, args...)))
 }
 
 // Warningf logs to the WARNING log. Arguments are handled in the manner of fmt.Printf.
-func (l logger) Warningf(format string, args ...interface{}) {
-	log.Printf("[WARN] "+format, args...)
+func (l *logger) Warningf(format string, args ...interface{}) {
+	tflog.SubsystemWarn(l.context(), sdkLoggerSubsystem, redactSecrets(fmt.Sprintf(format, args...)))
 }
 
 // Errorf logs to the ERROR log. Arguments are handled in the manner of fmt.Printf.
-func (l logger) Errorf(format string, args ...interface{}) {
-	log.Printf("[ERROR] "+format, args...)
+func (l *logger) Errorf(format string, args ...interface{}) {
+	tflog.SubsystemError(l.context(), sdkLoggerSubsystem, redactSecrets(fmt.Sprintf(format, args...)))
+}
+
+// ShouldLog allow the SDK to log at every level, filtering is done by tflog.
+// Debug is disabled as it makes the SDK dump whole requests, those are logged by the loggingTransport instead.
+func (l *logger) ShouldLog(level sdkLogger.LogLevel) bool {
+	return level != sdkLogger.LogLevelDebug
+}
+
+// keysAndValuesToFields converts retryablehttp key values to tflog fields.
+func keysAndValuesToFields(keysAndValues []interface{}) map[string]interface{} {
+	fields := make(map[string]interface{}, len(keysAndValues)/2)
+	for i := 0; i+1 < len(keysAndValues); i += 2 {
+		key := fmt.Sprint(keysAndValues[i])
+		value := keysAndValues[i+1]
+		if str, isString := value.(string); isString {
+			value = redactSecrets(str)
+		}
+		fields[key] = value
+	}
+	return fields
+}
+
+// Error logs to the ERROR log with retryablehttp key values.
+func (l *logger) Error(msg string, keysAndValues ...interface{}) {
+	tflog.SubsystemError(l.context(), sdkLoggerSubsystem, msg, keysAndValuesToFields(keysAndValues))
+}
+
+// Warn logs to the WARNING log with retryablehttp key values.
+func (l *logger) Warn(msg string, keysAndValues ...interface{}) {
+	tflog.SubsystemWarn(l.context(), sdkLoggerSubsystem, msg, keysAndValuesToFields(keysAndValues))
 }
 
-// Printf logs to the DEBUG log. Arguments are handled in the manner of fmt.Printf.
-func (l logger) Printf(format string, args ...interface{}) {
-	l.Debugf(format, args...)
+// Info logs to the INFO log with retryablehttp key values.
+func (l *logger) Info(msg string, keysAndValues ...interface{}) {
+	tflog.SubsystemInfo(l.context(), sdkLoggerSubsystem, msg, keysAndValuesToFields(keysAndValues))
 }
 
-// ShouldLog allow the SDK to log only in DEBUG or TRACE levels.
-func (l logger) ShouldLog(_ sdkLogger.LogLevel) bool {
-	return logging.IsDebugOrHigher()
+// Debug logs to the DEBUG log with retryablehttp key values.
+func (l *logger) Debug(msg string, keysAndValues ...interface{}) {
+	tflog.SubsystemDebug(l.context(), sdkLoggerSubsystem, msg, keysAndValuesToFields(keysAndValues))
 }
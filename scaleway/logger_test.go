@@ -0,0 +1,98 @@
+package scaleway
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-log/tflogtest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedactBody(t *testing.T) {
+	testCases := []struct {
+		name     string
+		path     string
+		body     string
+		expected string
+	}{
+		{
+			name:     "password",
+			path:     "/rdb/v1/regions/fr-par/instances",
+			body:     `{"name":"db","password":"my \"secret\" password","user_name":"admin"}`,
+			expected: `{"name":"db","password":"***","user_name":"admin"}`,
+		},
+		{
+			name:     "secret key and token",
+			path:     "/iam/v1alpha1/api-keys",
+			body:     `{"access_key":"SCWXXX","secret_key": "11111111-1111-1111-1111-111111111111","token":"abc"}`,
+			expected: `{"access_key":"SCWXXX","secret_key": "***","token":"***"}`,
+		},
+		{
+			name:     "secret version data",
+			path:     "/secret-manager/v1alpha1/regions/fr-par/secrets/uuid/versions",
+			body:     `{"data":"c2VjcmV0","description":"version"}`,
+			expected: `{"data":"***","description":"version"}`,
+		},
+		{
+			name:     "kubeconfig content",
+			path:     "/k8s/v1/regions/fr-par/clusters/uuid/kubeconfig",
+			body:     `{"name":"kubeconfig","content":"YXBpVmVyc2lvbjogdjE="}`,
+			expected: `{"name":"kubeconfig","content":"***"}`,
+		},
+		{
+			name:     "domain record data is kept",
+			path:     "/domain/v2beta1/dns-zones/example.com/records",
+			body:     `{"data":"1.2.3.4","type":"A"}`,
+			expected: `{"data":"1.2.3.4","type":"A"}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, redactBody(tc.path, tc.body))
+		})
+	}
+}
+
+func TestRedactSecrets(t *testing.T) {
+	assert.Equal(t, `response: {"password":"***","data":"***"}`, redactSecrets(`response: {"password":"secret","data":"c2VjcmV0"}`))
+	assert.Equal(t, `retrying request`, redactSecrets(`retrying request`))
+}
+
+func TestAPILoggerSubsystem(t *testing.T) {
+	assert.Equal(t, "instance", apiLoggerSubsystem("api.scaleway.com", "/instance/v1/zones/fr-par-1/servers"))
+	assert.Equal(t, "secret_manager", apiLoggerSubsystem("api.scaleway.com", "/secret-manager/v1alpha1/regions/fr-par/secrets"))
+	assert.Equal(t, "object", apiLoggerSubsystem("my-bucket.s3.fr-par.scw.cloud", "/my-object"))
+	assert.Equal(t, "api", apiLoggerSubsystem("api.scaleway.com", "/"))
+}
+
+func TestAddLoggerFields(t *testing.T) {
+	var output bytes.Buffer
+	ctx := tflogtest.RootLogger(context.Background(), &output)
+
+	resource := &schema.Resource{
+		Schema: map[string]*schema.Schema{},
+		ReadContext: func(ctx context.Context, _ *schema.ResourceData, _ interface{}) diag.Diagnostics {
+			ctx = newLoggerSubsystem(ctx, "instance")
+			tflog.SubsystemDebug(ctx, "instance", "sending request")
+			return nil
+		},
+	}
+	addLoggerFields("scaleway_instance_server", resource)
+	assert.Nil(t, resource.CreateContext)
+
+	d := resource.TestResourceData()
+	d.SetId("fr-par-1/11111111-1111-1111-1111-111111111111")
+	require.False(t, resource.ReadContext(ctx, d, nil).HasError())
+
+	entries, err := tflogtest.MultilineJSONDecode(&output)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "scaleway_instance_server", entries[0]["scaleway_resource_type"])
+	assert.Equal(t, "fr-par-1/11111111-1111-1111-1111-111111111111", entries[0]["scaleway_resource_id"])
+}
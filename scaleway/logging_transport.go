@@ -0,0 +1,83 @@
+package scaleway
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/logging"
+)
+
+// loggingTransport logs every request sent to Scaleway APIs in a tflog subsystem per product.
+// Bodies are only logged at TRACE level, with secret fields masked.
+type loggingTransport struct {
+	transport http.RoundTripper
+}
+
+func newLoggingTransport(transport http.RoundTripper) http.RoundTripper {
+	return &loggingTransport{transport: transport}
+}
+
+func isJSONContentType(header http.Header) bool {
+	return strings.Contains(header.Get("Content-Type"), "json")
+}
+
+// RoundTrip logs the request and its response.
+func (t *loggingTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	subsystem := apiLoggerSubsystem(r.URL.Host, r.URL.Path)
+	ctx := newLoggerSubsystem(r.Context(), subsystem)
+	fields := map[string]interface{}{
+		"http_method": r.Method,
+		"api_host":    r.URL.Host,
+		"api_path":    r.URL.Path,
+	}
+	logBodies := logging.IsDebugOrHigher()
+
+	if logBodies && r.Body != nil && isJSONContentType(r.Header) {
+		body, err := io.ReadAll(r.Body)
+		_ = r.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		tflog.SubsystemTrace(ctx, subsystem, "sending request body", map[string]interface{}{
+			"api_path":          r.URL.Path,
+			"http_request_body": redactBody(r.URL.Path, string(body)),
+		})
+	}
+
+	tflog.SubsystemDebug(ctx, subsystem, "sending request", fields)
+	start := time.Now()
+	resp, err := t.transport.RoundTrip(r)
+	fields["duration"] = time.Since(start).String()
+	if err != nil {
+		fields["error"] = err.Error()
+		tflog.SubsystemDebug(ctx, subsystem, "request failed", fields)
+		return resp, err
+	}
+
+	fields["status_code"] = resp.StatusCode
+	if requestID := resp.Header.Get("X-Request-Id"); requestID != "" {
+		fields["request_id"] = requestID
+	}
+	tflog.SubsystemDebug(ctx, subsystem, "received response", fields)
+
+	if logBodies && resp.Body != nil && isJSONContentType(resp.Header) {
+		body, err := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+		tflog.SubsystemTrace(ctx, subsystem, "received response body", map[string]interface{}{
+			"api_path":           r.URL.Path,
+			"request_id":         fields["request_id"],
+			"http_response_body": redactBody(r.URL.Path, string(body)),
+		})
+	}
+
+	return resp, nil
+}
@@ -5,11 +5,13 @@ import (
 	"fmt"
 	"net/http"
 	"os"
+	"sync"
 
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/plugin"
+	sdkLogger "github.com/scaleway/scaleway-sdk-go/logger"
 	"github.com/scaleway/scaleway-sdk-go/scw"
 )
 
@@ -20,6 +22,26 @@ type ProviderConfig struct {
 	// Meta can be used to override Meta that will be used by the provider.
 	// This is useful for tests.
 	Meta *Meta
+
+	// configuredMeta is the Meta built when configuring the SDKv2 provider, shared with the framework provider.
+	configuredMeta *Meta
+	mu             sync.Mutex
+}
+
+func (c *ProviderConfig) setConfiguredMeta(meta *Meta) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.configuredMeta = meta
+}
+
+// meta returns the Meta used by the provider once configured.
+func (c *ProviderConfig) meta() *Meta {
+	if c.Meta != nil {
+		return c.Meta
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.configuredMeta
 }
 
 // DefaultProviderConfig return default ProviderConfig struct
@@ -74,13 +96,27 @@ func Provider(config *ProviderConfig) plugin.ProviderFunc {
 					Description:  "The Scaleway organization ID.",
 					ValidateFunc: validationUUID(),
 				},
-				"region": regionSchema(),
-				"zone":   zoneSchema(),
+				// Provider attributes cannot be computed with terraform-plugin-framework, the schemas must match to be muxed.
+				"region": {
+					Type:             schema.TypeString,
+					Optional:         true,
+					Description:      "The region you want to attach the resource to",
+					ValidateDiagFunc: validateStringInSliceWithWarning(allRegions(), "region"),
+				},
+				"zone": {
+					Type:             schema.TypeString,
+					Optional:         true,
+					Description:      "The zone you want to attach the resource to",
+					ValidateDiagFunc: validateStringInSliceWithWarning(allZones(), "zone"),
+				},
 				"api_url": {
 					Type:        schema.TypeString,
 					Optional:    true,
 					Description: "The Scaleway API URL to use.",
 				},
+				"default_tags": defaultTagsSchema(),
+				"retry":        retrySchema(),
+				"rate_limit":   rateLimitSchema(),
 			},
 
 			ResourcesMap: map[string]*schema.Resource{
@@ -108,21 +144,26 @@ func Provider(config *ProviderConfig) plugin.ProviderFunc {
 				"scaleway_iam_policy":                          resourceScalewayIamPolicy(),
 				"scaleway_instance_user_data":                  resourceScalewayInstanceUserData(),
 				"scaleway_instance_image":                      resourceScalewayInstanceImage(),
+				"scaleway_instance_image_build":                resourceScalewayInstanceImageBuild(),
 				"scaleway_instance_ip":                         resourceScalewayInstanceIP(),
 				"scaleway_instance_ip_reverse_dns":             resourceScalewayInstanceIPReverseDNS(),
 				"scaleway_instance_volume":                     resourceScalewayInstanceVolume(),
 				"scaleway_instance_security_group":             resourceScalewayInstanceSecurityGroup(),
+				"scaleway_instance_security_group_rule":        resourceScalewayInstanceSecurityGroupRule(),
 				"scaleway_instance_security_group_rules":       resourceScalewayInstanceSecurityGroupRules(),
 				"scaleway_instance_server":                     resourceScalewayInstanceServer(),
+				"scaleway_instance_server_template":            resourceScalewayInstanceServerTemplate(),
 				"scaleway_instance_snapshot":                   resourceScalewayInstanceSnapshot(),
 				"scaleway_iam_ssh_key":                         resourceScalewayIamSSKKey(),
 				"scaleway_instance_placement_group":            resourceScalewayInstancePlacementGroup(),
+				"scaleway_instance_pool":                       resourceScalewayInstancePool(),
 				"scaleway_instance_private_nic":                resourceScalewayInstancePrivateNIC(),
 				"scaleway_iot_hub":                             resourceScalewayIotHub(),
 				"scaleway_iot_device":                          resourceScalewayIotDevice(),
 				"scaleway_iot_route":                           resourceScalewayIotRoute(),
 				"scaleway_iot_network":                         resourceScalewayIotNetwork(),
 				"scaleway_k8s_cluster":                         resourceScalewayK8SCluster(),
+				"scaleway_k8s_node_action":                     resourceScalewayK8SNodeAction(),
 				"scaleway_k8s_pool":                            resourceScalewayK8SPool(),
 				"scaleway_lb":                                  resourceScalewayLb(),
 				"scaleway_lb_ip":                               resourceScalewayLbIP(),
@@ -182,6 +223,7 @@ func Provider(config *ProviderConfig) plugin.ProviderFunc {
 				"scaleway_iam_ssh_key":                         dataSourceScalewayIamSSHKey(),
 				"scaleway_iam_user":                            dataSourceScalewayIamUser(),
 				"scaleway_instance_ip":                         dataSourceScalewayInstanceIP(),
+				"scaleway_instance_discovery":                  dataSourceScalewayInstanceDiscovery(),
 				"scaleway_instance_private_nic":                dataSourceScalewayInstancePrivateNIC(),
 				"scaleway_instance_security_group":             dataSourceScalewayInstanceSecurityGroup(),
 				"scaleway_instance_server":                     dataSourceScalewayInstanceServer(),
@@ -192,9 +234,13 @@ func Provider(config *ProviderConfig) plugin.ProviderFunc {
 				"scaleway_iot_hub":                             dataSourceScalewayIotHub(),
 				"scaleway_iot_device":                          dataSourceScalewayIotDevice(),
 				"scaleway_k8s_cluster":                         dataSourceScalewayK8SCluster(),
+				"scaleway_k8s_cluster_credentials":             dataSourceScalewayK8SClusterCredentials(),
+				"scaleway_k8s_discovery":                       dataSourceScalewayK8SDiscovery(),
+				"scaleway_k8s_nodes":                           dataSourceScalewayK8SNodes(),
 				"scaleway_k8s_pool":                            dataSourceScalewayK8SPool(),
 				"scaleway_k8s_version":                         dataSourceScalewayK8SVersion(),
 				"scaleway_lb":                                  dataSourceScalewayLb(),
+				"scaleway_lb_discovery":                        dataSourceScalewayLbDiscovery(),
 				"scaleway_lbs":                                 dataSourceScalewayLbs(),
 				"scaleway_lb_acls":                             dataSourceScalewayLbACLs(),
 				"scaleway_lb_backend":                          dataSourceScalewayLbBackend(),
@@ -210,11 +256,13 @@ func Provider(config *ProviderConfig) plugin.ProviderFunc {
 				"scaleway_object_bucket":                       dataSourceScalewayObjectBucket(),
 				"scaleway_rdb_acl":                             dataSourceScalewayRDBACL(),
 				"scaleway_rdb_instance":                        dataSourceScalewayRDBInstance(),
+				"scaleway_rdb_discovery":                       dataSourceScalewayRDBDiscovery(),
 				"scaleway_rdb_database":                        dataSourceScalewayRDBDatabase(),
 				"scaleway_rdb_database_backup":                 dataSourceScalewayRDBDatabaseBackup(),
 				"scaleway_rdb_privilege":                       dataSourceScalewayRDBPrivilege(),
 				"scaleway_redis_cluster":                       dataSourceScalewayRedisCluster(),
 				"scaleway_registry_namespace":                  dataSourceScalewayRegistryNamespace(),
+				"scaleway_registry_discovery":                  dataSourceScalewayRegistryDiscovery(),
 				"scaleway_tem_domain":                          dataSourceScalewayTemDomain(),
 				"scaleway_secret":                              dataSourceScalewaySecret(),
 				"scaleway_secret_version":                      dataSourceScalewaySecretVersion(),
@@ -225,6 +273,7 @@ func Provider(config *ProviderConfig) plugin.ProviderFunc {
 				"scaleway_vpc_public_gateway_dhcp_reservation": dataSourceScalewayVPCPublicGatewayDHCPReservation(),
 				"scaleway_vpc_public_gateway_ip":               dataSourceScalewayVPCPublicGatewayIP(),
 				"scaleway_vpc_private_network":                 dataSourceScalewayVPCPrivateNetwork(),
+				"scaleway_vpc_discovery":                       dataSourceScalewayVPCDiscovery(),
 				"scaleway_vpc_public_gateway_pat_rule":         dataSourceScalewayVPCPublicGatewayPATRule(),
 				"scaleway_webhosting_offer":                    dataSourceScalewayWebhostingOffer(),
 			},
@@ -232,9 +281,18 @@ func Provider(config *ProviderConfig) plugin.ProviderFunc {
 
 		addBetaResources(p)
 
+		for name, resource := range p.ResourcesMap {
+			addImportDefaults(resource)
+			addLoggerFields(name, resource)
+		}
+
 		p.ConfigureContextFunc = func(ctx context.Context, data *schema.ResourceData) (interface{}, diag.Diagnostics) {
 			terraformVersion := p.TerraformVersion
 
+			// Route SDK logs through tflog
+			l.setContext(ctx)
+			sdkLogger.SetLogger(l)
+
 			// If we provide meta in config use it. This is useful for tests
 			if config.Meta != nil {
 				return config.Meta, nil
@@ -247,6 +305,7 @@ func Provider(config *ProviderConfig) plugin.ProviderFunc {
 			if err != nil {
 				return nil, diag.FromErr(err)
 			}
+			config.setConfiguredMeta(meta)
 			return meta, nil
 		}
 
@@ -264,6 +323,12 @@ type Meta struct {
 	// or it can be a http.Client used to record and replay cassettes which is useful
 	// to replay recorded interactions with APIs locally
 	httpClient *http.Client
+	// kubeHTTPClient replaces the client trusting the CA of a cluster to call its Kubernetes API, e.g. in tests
+	kubeHTTPClient *http.Client
+	// defaultTags are the provider level tags merged into every taggable resource.
+	defaultTags map[string]string
+	// k8sCredentials are the API keys minted by scaleway_k8s_cluster_credentials, reused while they are valid.
+	k8sCredentials *k8sCredentialsCache
 }
 
 type metaConfig struct {
@@ -274,7 +339,9 @@ type metaConfig struct {
 	forceOrganizationID string
 	forceAccessKey      string
 	forceSecretKey      string
+	forceAPIURL         string
 	httpClient          *http.Client
+	kubeHTTPClient      *http.Client
 }
 
 // providerConfigure creates the Meta object containing the SDK client.
@@ -306,6 +373,9 @@ func buildMeta(ctx context.Context, config *metaConfig) (*Meta, error) {
 	if config.forceSecretKey != "" {
 		profile.SecretKey = scw.StringPtr(config.forceSecretKey)
 	}
+	if config.forceAPIURL != "" {
+		profile.APIURL = scw.StringPtr(config.forceAPIURL)
+	}
 
 	// TODO validated profile
 
@@ -317,7 +387,12 @@ func buildMeta(ctx context.Context, config *metaConfig) (*Meta, error) {
 		scw.WithProfile(profile),
 	}
 
-	httpClient := &http.Client{Transport: newRetryableTransport(http.DefaultTransport)}
+	retryOptions, err := expandRetryableTransportOptions(config.providerSchema)
+	if err != nil {
+		return nil, err
+	}
+	rateLimitedTransport := newRateLimitedTransport(newLoggingTransport(http.DefaultTransport), expandRateLimitedTransportOptions(config.providerSchema))
+	httpClient := &http.Client{Transport: newRetryableTransportWithOptions(rateLimitedTransport, retryOptions)}
 	if config.httpClient != nil {
 		httpClient = config.httpClient
 	}
@@ -329,8 +404,11 @@ func buildMeta(ctx context.Context, config *metaConfig) (*Meta, error) {
 	}
 
 	return &Meta{
-		scwClient:  scwClient,
-		httpClient: httpClient,
+		scwClient:      scwClient,
+		httpClient:     httpClient,
+		kubeHTTPClient: config.kubeHTTPClient,
+		defaultTags:    expandDefaultTags(config.providerSchema),
+		k8sCredentials: &k8sCredentialsCache{},
 	}, nil
 }
 
@@ -0,0 +1,171 @@
+package scaleway
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	providerSchema "github.com/hashicorp/terraform-plugin-framework/provider/schema"
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-mux/tf5muxserver"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// frameworkResources are the resources written with terraform-plugin-framework.
+var frameworkResources = []func() resource.Resource{}
+
+// frameworkDataSources are the data sources written with terraform-plugin-framework.
+var frameworkDataSources = []func() datasource.DataSource{}
+
+// frameworkProvider serves the resources, data sources and functions written with terraform-plugin-framework.
+// It is muxed with the SDKv2 provider and uses the Meta built when configuring it, see NewProviderServer.
+type frameworkProvider struct {
+	config *ProviderConfig
+	// sdkSchema is the schema of the SDKv2 provider, both providers must have the same schema to be muxed.
+	sdkSchema map[string]*schema.Schema
+}
+
+func newFrameworkProvider(config *ProviderConfig, sdkProvider *schema.Provider) provider.Provider {
+	return &frameworkProvider{
+		config:    config,
+		sdkSchema: sdkProvider.Schema,
+	}
+}
+
+func (p *frameworkProvider) Metadata(_ context.Context, _ provider.MetadataRequest, resp *provider.MetadataResponse) {
+	resp.TypeName = "scaleway"
+}
+
+func (p *frameworkProvider) Schema(_ context.Context, _ provider.SchemaRequest, resp *provider.SchemaResponse) {
+	attributes, blocks := frameworkProviderSchema(p.sdkSchema)
+	resp.Schema = providerSchema.Schema{
+		Attributes: attributes,
+		Blocks:     blocks,
+	}
+}
+
+// Configure passes the Meta of the SDKv2 provider to framework resources and data sources.
+// The mux server configures providers in order, so the SDKv2 provider has already been configured.
+func (p *frameworkProvider) Configure(_ context.Context, _ provider.ConfigureRequest, resp *provider.ConfigureResponse) {
+	meta := p.config.meta()
+	if meta == nil {
+		resp.Diagnostics.AddError("provider not configured", "the SDKv2 provider must be configured before the framework provider")
+		return
+	}
+	resp.ResourceData = meta
+	resp.DataSourceData = meta
+}
+
+func (p *frameworkProvider) Resources(_ context.Context) []func() resource.Resource {
+	return frameworkResources
+}
+
+func (p *frameworkProvider) DataSources(_ context.Context) []func() datasource.DataSource {
+	return frameworkDataSources
+}
+
+func (p *frameworkProvider) Functions(_ context.Context) []func() function.Function {
+	return frameworkFunctions
+}
+
+// frameworkProviderSchema converts the SDKv2 provider schema to framework attributes and blocks.
+func frameworkProviderSchema(sdkSchema map[string]*schema.Schema) (map[string]providerSchema.Attribute, map[string]providerSchema.Block) {
+	attributes := map[string]providerSchema.Attribute{}
+	blocks := map[string]providerSchema.Block{}
+
+	for name, s := range sdkSchema {
+		if elem, isResource := s.Elem.(*schema.Resource); isResource {
+			nestedAttributes, nestedBlocks := frameworkProviderSchema(elem.Schema)
+			nestedObject := providerSchema.NestedBlockObject{
+				Attributes: nestedAttributes,
+				Blocks:     nestedBlocks,
+			}
+			if s.Type == schema.TypeSet {
+				blocks[name] = providerSchema.SetNestedBlock{
+					NestedObject:       nestedObject,
+					Description:        s.Description,
+					DeprecationMessage: s.Deprecated,
+				}
+			} else {
+				blocks[name] = providerSchema.ListNestedBlock{
+					NestedObject:       nestedObject,
+					Description:        s.Description,
+					DeprecationMessage: s.Deprecated,
+				}
+			}
+			continue
+		}
+		attributes[name] = frameworkProviderAttribute(s)
+	}
+
+	return attributes, blocks
+}
+
+func frameworkAttrType(s *schema.Schema) attr.Type {
+	switch s.Type {
+	case schema.TypeBool:
+		return types.BoolType
+	case schema.TypeInt:
+		return types.Int64Type
+	case schema.TypeFloat:
+		return types.Float64Type
+	case schema.TypeList:
+		return types.ListType{ElemType: frameworkElemType(s)}
+	case schema.TypeSet:
+		return types.SetType{ElemType: frameworkElemType(s)}
+	case schema.TypeMap:
+		return types.MapType{ElemType: frameworkElemType(s)}
+	default:
+		return types.StringType
+	}
+}
+
+// frameworkElemType returns the element type of a collection, SDKv2 collections default to strings.
+func frameworkElemType(s *schema.Schema) attr.Type {
+	if elem, isSchema := s.Elem.(*schema.Schema); isSchema {
+		return frameworkAttrType(elem)
+	}
+	return types.StringType
+}
+
+func frameworkProviderAttribute(s *schema.Schema) providerSchema.Attribute {
+	switch s.Type {
+	case schema.TypeBool:
+		return providerSchema.BoolAttribute{Required: s.Required, Optional: s.Optional, Sensitive: s.Sensitive, Description: s.Description, DeprecationMessage: s.Deprecated}
+	case schema.TypeInt:
+		return providerSchema.Int64Attribute{Required: s.Required, Optional: s.Optional, Sensitive: s.Sensitive, Description: s.Description, DeprecationMessage: s.Deprecated}
+	case schema.TypeFloat:
+		return providerSchema.Float64Attribute{Required: s.Required, Optional: s.Optional, Sensitive: s.Sensitive, Description: s.Description, DeprecationMessage: s.Deprecated}
+	case schema.TypeList:
+		return providerSchema.ListAttribute{ElementType: frameworkElemType(s), Required: s.Required, Optional: s.Optional, Sensitive: s.Sensitive, Description: s.Description, DeprecationMessage: s.Deprecated}
+	case schema.TypeSet:
+		return providerSchema.SetAttribute{ElementType: frameworkElemType(s), Required: s.Required, Optional: s.Optional, Sensitive: s.Sensitive, Description: s.Description, DeprecationMessage: s.Deprecated}
+	case schema.TypeMap:
+		return providerSchema.MapAttribute{ElementType: frameworkElemType(s), Required: s.Required, Optional: s.Optional, Sensitive: s.Sensitive, Description: s.Description, DeprecationMessage: s.Deprecated}
+	default:
+		return providerSchema.StringAttribute{Required: s.Required, Optional: s.Optional, Sensitive: s.Sensitive, Description: s.Description, DeprecationMessage: s.Deprecated}
+	}
+}
+
+// NewProviderServer returns the provider server muxing the SDKv2 provider with the terraform-plugin-framework one.
+// Both providers share the same Meta, so framework resources can be added without changing existing ones.
+func NewProviderServer(ctx context.Context, config *ProviderConfig) (func() tfprotov5.ProviderServer, error) {
+	sdkProvider := Provider(config)()
+	servers := []func() tfprotov5.ProviderServer{
+		// The SDKv2 provider must stay first as it builds the Meta used by the framework provider.
+		sdkProvider.GRPCProvider,
+		providerserver.NewProtocol5(newFrameworkProvider(config, sdkProvider)),
+	}
+
+	muxServer, err := tf5muxserver.NewMuxServer(ctx, servers...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create mux server: %w", err)
+	}
+	return muxServer.ProviderServer, nil
+}
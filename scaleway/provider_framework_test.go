@@ -0,0 +1,44 @@
+package scaleway
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewProviderServer(t *testing.T) {
+	ctx := context.Background()
+	providerServer, err := NewProviderServer(ctx, DefaultProviderConfig())
+	require.NoError(t, err)
+
+	resp, err := providerServer().GetProviderSchema(ctx, &tfprotov5.GetProviderSchemaRequest{})
+	require.NoError(t, err)
+	// The mux server reports differences between the SDKv2 and framework provider schemas as diagnostics.
+	for _, diagnostic := range resp.Diagnostics {
+		t.Errorf("%s: %s", diagnostic.Summary, diagnostic.Detail)
+	}
+	assert.Contains(t, resp.ResourceSchemas, "scaleway_instance_server")
+	assert.Contains(t, resp.Functions, "zone_of")
+}
+
+func TestFrameworkProvider_Configure(t *testing.T) {
+	ctx := context.Background()
+	config := DefaultProviderConfig()
+	p := newFrameworkProvider(config, Provider(config)())
+
+	resp := &provider.ConfigureResponse{}
+	p.Configure(ctx, provider.ConfigureRequest{}, resp)
+	assert.True(t, resp.Diagnostics.HasError(), "framework provider should not be configured before the SDKv2 one")
+
+	meta := &Meta{}
+	config.setConfiguredMeta(meta)
+	resp = &provider.ConfigureResponse{}
+	p.Configure(ctx, provider.ConfigureRequest{}, resp)
+	require.False(t, resp.Diagnostics.HasError())
+	assert.Same(t, meta, resp.ResourceData)
+	assert.Same(t, meta, resp.DataSourceData)
+}
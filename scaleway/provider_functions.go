@@ -0,0 +1,209 @@
+package scaleway
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/scaleway/scaleway-sdk-go/scw"
+)
+
+// frameworkFunctions are the provider-defined functions, available as provider::scaleway::<name> since Terraform 1.8.
+var frameworkFunctions = []func() function.Function{
+	newZoneOfFunction,
+	newRegionOfFunction,
+	newBareIDFunction,
+	newZonedIDFunction,
+	newRegionalIDFunction,
+}
+
+// stringFunctionParameter describes a string parameter of a stringFunction.
+type stringFunctionParameter struct {
+	name        string
+	description string
+}
+
+// stringFunction is a provider-defined function taking string parameters and returning a string.
+type stringFunction struct {
+	name        string
+	summary     string
+	description string
+	parameters  []stringFunctionParameter
+	run         func(args []string) (string, error)
+}
+
+func (f *stringFunction) Metadata(_ context.Context, _ function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = f.name
+}
+
+func (f *stringFunction) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	parameters := make([]function.Parameter, 0, len(f.parameters))
+	for _, parameter := range f.parameters {
+		parameters = append(parameters, function.StringParameter{
+			Name:        parameter.name,
+			Description: parameter.description,
+		})
+	}
+
+	resp.Definition = function.Definition{
+		Summary:     f.summary,
+		Description: f.description,
+		Parameters:  parameters,
+		Return:      function.StringReturn{},
+	}
+}
+
+func (f *stringFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	args := make([]string, len(f.parameters))
+	targets := make([]any, len(f.parameters))
+	for i := range args {
+		targets[i] = &args[i]
+	}
+
+	resp.Error = req.Arguments.Get(ctx, targets...)
+	if resp.Error != nil {
+		return
+	}
+
+	result, err := f.run(args)
+	if err != nil {
+		resp.Error = function.NewFuncError(err.Error())
+		return
+	}
+
+	resp.Error = resp.Result.Set(ctx, result)
+}
+
+func newZoneOfFunction() function.Function {
+	return &stringFunction{
+		name:        "zone_of",
+		summary:     "Returns the zone of a zoned ID",
+		description: "Returns the zone of a zoned ID such as fr-par-1/11111111-1111-1111-1111-111111111111 or of a zoned nested ID such as fr-par-1/11111111-1111-1111-1111-111111111111/22222222-2222-2222-2222-222222222222.",
+		parameters: []stringFunctionParameter{
+			{name: "id", description: "The zoned ID."},
+		},
+		run: func(args []string) (string, error) {
+			locality, err := localityOfID(args[0])
+			if err != nil {
+				return "", err
+			}
+			zone, err := scw.ParseZone(locality)
+			if err != nil {
+				return "", fmt.Errorf("invalid zone in id %s: %w", args[0], err)
+			}
+			return zone.String(), nil
+		},
+	}
+}
+
+func newRegionOfFunction() function.Function {
+	return &stringFunction{
+		name:        "region_of",
+		summary:     "Returns the region of a zone, a region or a localized ID",
+		description: "Returns the region of a zone such as fr-par-1, of a region such as fr-par, or of a zoned or regional ID such as fr-par-1/11111111-1111-1111-1111-111111111111.",
+		parameters: []stringFunctionParameter{
+			{name: "value", description: "The zone, region or localized ID."},
+		},
+		run: func(args []string) (string, error) {
+			locality := args[0]
+			if strings.Contains(locality, "/") {
+				var err error
+				locality, err = localityOfID(locality)
+				if err != nil {
+					return "", err
+				}
+			}
+			return regionOfLocality(locality)
+		},
+	}
+}
+
+func newBareIDFunction() function.Function {
+	return &stringFunction{
+		name:        "bare_id",
+		summary:     "Returns the ID without its locality",
+		description: "Returns the ID without its locality, fr-par-1/11111111-1111-1111-1111-111111111111 becoming 11111111-1111-1111-1111-111111111111. The last part of nested IDs is returned and raw IDs are returned unchanged.",
+		parameters: []stringFunctionParameter{
+			{name: "id", description: "The localized or raw ID."},
+		},
+		run: func(args []string) (string, error) {
+			return bareID(args[0]), nil
+		},
+	}
+}
+
+func newZonedIDFunction() function.Function {
+	return &stringFunction{
+		name:        "zoned_id",
+		summary:     "Builds a zoned ID",
+		description: "Builds a zoned ID such as fr-par-1/11111111-1111-1111-1111-111111111111 from a zone and an ID. The ID may already be localized, in which case its locality is replaced.",
+		parameters: []stringFunctionParameter{
+			{name: "zone", description: "The zone of the resource."},
+			{name: "id", description: "The ID of the resource."},
+		},
+		run: func(args []string) (string, error) {
+			zone, err := scw.ParseZone(args[0])
+			if err != nil {
+				return "", err
+			}
+			return newZonedIDString(zone, bareID(args[1])), nil
+		},
+	}
+}
+
+func newRegionalIDFunction() function.Function {
+	return &stringFunction{
+		name:        "regional_id",
+		summary:     "Builds a regional ID",
+		description: "Builds a regional ID such as fr-par/11111111-1111-1111-1111-111111111111 from a region and an ID. The ID may already be localized, in which case its locality is replaced.",
+		parameters: []stringFunctionParameter{
+			{name: "region", description: "The region of the resource."},
+			{name: "id", description: "The ID of the resource."},
+		},
+		run: func(args []string) (string, error) {
+			region, err := scw.ParseRegion(args[0])
+			if err != nil {
+				return "", err
+			}
+			return newRegionalIDString(region, bareID(args[1])), nil
+		},
+	}
+}
+
+// localityOfID returns the locality of a localized ID or of a localized nested ID.
+func localityOfID(id string) (string, error) {
+	locality, _, err := parseLocalizedID(id)
+	if err != nil {
+		locality, _, _, err = parseLocalizedNestedID(id)
+	}
+	if err != nil {
+		return "", err
+	}
+	return locality, nil
+}
+
+// regionOfLocality returns the region of a zone or of a region.
+func regionOfLocality(locality string) (string, error) {
+	if zone, err := scw.ParseZone(locality); err == nil {
+		region, err := zone.Region()
+		if err != nil {
+			return "", err
+		}
+		return region.String(), nil
+	}
+
+	region, err := scw.ParseRegion(locality)
+	if err != nil {
+		return "", fmt.Errorf("%s is neither a zone nor a region", locality)
+	}
+	return region.String(), nil
+}
+
+// bareID returns the last part of a localized ID, or the ID itself when it is not localized.
+func bareID(id string) string {
+	if _, _, outerID, err := parseLocalizedNestedID(id); err == nil {
+		return bareID(outerID)
+	}
+	return expandID(id)
+}
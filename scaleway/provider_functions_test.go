@@ -0,0 +1,75 @@
+package scaleway
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func runStringFunction(t *testing.T, f function.Function, args ...string) (string, *function.FuncError) {
+	t.Helper()
+	ctx := context.Background()
+
+	values := make([]attr.Value, 0, len(args))
+	for _, arg := range args {
+		values = append(values, types.StringValue(arg))
+	}
+
+	resp := &function.RunResponse{Result: function.NewResultData(types.StringUnknown())}
+	f.Run(ctx, function.RunRequest{Arguments: function.NewArgumentsData(values)}, resp)
+	if resp.Error != nil {
+		return "", resp.Error
+	}
+
+	result, ok := resp.Result.Value().(types.String)
+	require.True(t, ok)
+	return result.ValueString(), nil
+}
+
+func TestProviderFunctions(t *testing.T) {
+	const id = "11111111-1111-1111-1111-111111111111"
+
+	tests := []struct {
+		name     string
+		function func() function.Function
+		args     []string
+		expected string
+		err      bool
+	}{
+		{name: "zone_of", function: newZoneOfFunction, args: []string{"fr-par-1/" + id}, expected: "fr-par-1"},
+		{name: "zone_of nested", function: newZoneOfFunction, args: []string{"nl-ams-2/" + id + "/name"}, expected: "nl-ams-2"},
+		{name: "zone_of legacy", function: newZoneOfFunction, args: []string{"par1/" + id}, expected: "fr-par-1"},
+		{name: "zone_of regional", function: newZoneOfFunction, args: []string{"fr-par/" + id}, err: true},
+		{name: "zone_of raw", function: newZoneOfFunction, args: []string{id}, err: true},
+		{name: "region_of zone", function: newRegionOfFunction, args: []string{"pl-waw-3"}, expected: "pl-waw"},
+		{name: "region_of region", function: newRegionOfFunction, args: []string{"fr-par"}, expected: "fr-par"},
+		{name: "region_of zoned id", function: newRegionOfFunction, args: []string{"nl-ams-1/" + id}, expected: "nl-ams"},
+		{name: "region_of regional id", function: newRegionOfFunction, args: []string{"fr-par/" + id}, expected: "fr-par"},
+		{name: "region_of invalid", function: newRegionOfFunction, args: []string{"paris"}, err: true},
+		{name: "bare_id zoned", function: newBareIDFunction, args: []string{"fr-par-1/" + id}, expected: id},
+		{name: "bare_id nested", function: newBareIDFunction, args: []string{"fr-par/cluster/" + id}, expected: id},
+		{name: "bare_id raw", function: newBareIDFunction, args: []string{id}, expected: id},
+		{name: "zoned_id", function: newZonedIDFunction, args: []string{"fr-par-2", id}, expected: "fr-par-2/" + id},
+		{name: "zoned_id relocalized", function: newZonedIDFunction, args: []string{"fr-par-2", "fr-par-1/" + id}, expected: "fr-par-2/" + id},
+		{name: "zoned_id invalid zone", function: newZonedIDFunction, args: []string{"fr-par", id}, err: true},
+		{name: "regional_id", function: newRegionalIDFunction, args: []string{"nl-ams", id}, expected: "nl-ams/" + id},
+		{name: "regional_id invalid region", function: newRegionalIDFunction, args: []string{"nl-ams-1", id}, err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := runStringFunction(t, tt.function(), tt.args...)
+			if tt.err {
+				assert.NotNil(t, err)
+				return
+			}
+			require.Nil(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
@@ -18,6 +18,7 @@ import (
 
 	"github.com/dnaeon/go-vcr/cassette"
 	"github.com/dnaeon/go-vcr/recorder"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
 	sdkacctest "github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -32,6 +33,9 @@ import (
 // UpdateCassettes will update all cassettes of a given test
 var UpdateCassettes = flag.Bool("cassettes", os.Getenv("TF_UPDATE_CASSETTES") == "true", "Record Cassettes")
 
+// StrictCassettes will fail tests that do not replay every interaction of their cassette
+var StrictCassettes = flag.Bool("strict-cassettes", os.Getenv("TF_STRICT_CASSETTES") == "true", "Fail tests leaving cassette interactions unused")
+
 // QueryMatcherIgnore contains the list of query value that should be ignored when matching requests with cassettes
 var QueryMatcherIgnore = []string{
 	"organization_id",
@@ -49,6 +53,8 @@ var BodyMatcherIgnore = []string{
 // value will be set in place of its old value
 var SensitiveFields = map[string]interface{}{
 	"secret_key": "00000000-0000-0000-0000-000000000000",
+	"password":   anonymizedValue,
+	"token":      anonymizedValue,
 }
 
 func testAccPreCheck(_ *testing.T) {}
@@ -164,6 +170,13 @@ func cassetteBodyMatcher(actualRequest *http.Request, cassetteRequest cassette.R
 		delete(actualJSON, key)
 		delete(cassetteJSON, key)
 	}
+	// Anonymized values cannot match the ones sent by the tests
+	for key, value := range SensitiveFields {
+		if cassetteJSON[key] == value {
+			delete(actualJSON, key)
+			delete(cassetteJSON, key)
+		}
+	}
 
 	return compareJSONBodies(cassetteJSON, actualJSON)
 }
@@ -213,23 +226,27 @@ func cassetteMatcher(actual *http.Request, expected cassette.Request) bool {
 		cassetteBodyMatcher(actual, expected)
 }
 
+// cassetteSensitiveFieldsAnonymizer replaces secrets found in request and response bodies with fixed values.
+// It handles SensitiveFields at any depth, PEM private keys and kubeconfig credentials.
 func cassetteSensitiveFieldsAnonymizer(i *cassette.Interaction) error {
-	var jsonBody map[string]interface{}
-	err := json.Unmarshal([]byte(i.Response.Body), &jsonBody)
-	if err != nil {
-		//nolint:nilerr
-		return nil
+	i.Request.Body = anonymizePrivateKeys(i.Request.Body)
+	i.Response.Body = anonymizePrivateKeys(i.Response.Body)
+	if isKubeconfigPath(i.Request.URL) {
+		i.Response.Body = anonymizeKubeconfig(i.Response.Body)
 	}
-	for key, value := range SensitiveFields {
-		if _, ok := jsonBody[key]; ok {
-			jsonBody[key] = value
+
+	for _, body := range []*string{&i.Request.Body, &i.Response.Body} {
+		var jsonBody interface{}
+		err := json.Unmarshal([]byte(*body), &jsonBody)
+		if err != nil || !anonymizeJSON(jsonBody) {
+			continue
 		}
+		anonymizedBody, err := json.Marshal(jsonBody)
+		if err != nil {
+			return fmt.Errorf("failed to marshal anonymized body: %w", err)
+		}
+		*body = string(anonymizedBody)
 	}
-	anonymizedBody, err := json.Marshal(jsonBody)
-	if err != nil {
-		return fmt.Errorf("failed to marshal anonymized body: %w", err)
-	}
-	i.Response.Body = string(anonymizedBody)
 	return nil
 }
 
@@ -253,7 +270,13 @@ func getHTTPRecoder(t *testing.T, update bool) (client *http.Client, cleanup fun
 	}
 
 	// Add custom matcher for requests and cassettes
-	r.SetMatcher(cassetteMatcher)
+	var tracker *cassetteUsageTracker
+	if !update && *StrictCassettes {
+		tracker = newCassetteUsageTracker()
+		r.SetMatcher(tracker.matcher(cassetteMatcher))
+	} else {
+		r.SetMatcher(cassetteMatcher)
+	}
 
 	// Add a filter which removes Authorization headers from all requests:
 	r.AddFilter(func(i *cassette.Interaction) error {
@@ -272,8 +295,16 @@ func getHTTPRecoder(t *testing.T, update bool) (client *http.Client, cleanup fun
 		retryOptions.RetryWaitMax = scw.TimeDurationPtr(0)
 	}
 
-	return &http.Client{Transport: newRetryableTransportWithOptions(r, retryOptions)}, func() {
+	var transport http.RoundTripper = r
+	if tracker != nil {
+		transport = tracker.transport(r)
+	}
+
+	return &http.Client{Transport: newRetryableTransportWithOptions(transport, retryOptions)}, func() {
 		assert.NoError(t, r.Stop()) // Make sure recorder is stopped once done with it
+		if tracker != nil {
+			tracker.check(t, getTestFilePath(t, ".cassette"))
+		}
 	}, nil
 }
 
@@ -507,7 +538,22 @@ type TestTools struct {
 	T                 *testing.T
 	Meta              *Meta
 	ProviderFactories map[string]func() (*schema.Provider, error)
-	Cleanup           func()
+	// ProtoV5ProviderFactories serve the muxed provider, they must be used to test terraform-plugin-framework resources.
+	ProtoV5ProviderFactories map[string]func() (tfprotov5.ProviderServer, error)
+	Cleanup                  func()
+}
+
+// protoV5ProviderFactories returns factories of the muxed provider using the given meta.
+func protoV5ProviderFactories(meta *Meta) map[string]func() (tfprotov5.ProviderServer, error) {
+	return map[string]func() (tfprotov5.ProviderServer, error){
+		"scaleway": func() (tfprotov5.ProviderServer, error) {
+			providerServer, err := NewProviderServer(context.Background(), &ProviderConfig{Meta: meta})
+			if err != nil {
+				return nil, err
+			}
+			return providerServer(), nil
+		},
+	}
 }
 
 func NewTestTools(t *testing.T) *TestTools {
@@ -539,7 +585,8 @@ func NewTestTools(t *testing.T) *TestTools {
 				return Provider(&ProviderConfig{Meta: meta})(), nil
 			},
 		},
-		Cleanup: cleanup,
+		ProtoV5ProviderFactories: protoV5ProviderFactories(meta),
+		Cleanup:                  cleanup,
 	}
 }
 
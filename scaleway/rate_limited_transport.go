@@ -0,0 +1,280 @@
+package scaleway
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// rateLimitOptions configures the rate limiter of an API. Zero values disable the matching limit.
+type rateLimitOptions struct {
+	RequestsPerSecond float64
+	Burst             int
+	MaxInFlight       int
+}
+
+func (o rateLimitOptions) isEnabled() bool {
+	return o.RequestsPerSecond > 0 || o.MaxInFlight > 0
+}
+
+type rateLimitedTransportOptions struct {
+	// Default is applied to every API that has no override.
+	Default rateLimitOptions
+	// APIs contains overrides keyed by API path prefix such as /instance/v1.
+	APIs map[string]rateLimitOptions
+}
+
+func rateLimitAttributesSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"requests_per_second": {
+			Type:         schema.TypeFloat,
+			Optional:     true,
+			Description:  "Maximum number of requests per second sent to an API.",
+			ValidateFunc: validation.FloatAtLeast(0),
+		},
+		"burst": {
+			Type:         schema.TypeInt,
+			Optional:     true,
+			Description:  "Number of requests that can be sent at once before being limited by requests_per_second.",
+			ValidateFunc: validation.IntAtLeast(0),
+		},
+		"max_in_flight": {
+			Type:         schema.TypeInt,
+			Optional:     true,
+			Description:  "Maximum number of concurrent requests sent to an API.",
+			ValidateFunc: validation.IntAtLeast(0),
+		},
+	}
+}
+
+// rateLimitSchema returns the provider level rate_limit block.
+func rateLimitSchema() *schema.Schema {
+	apiSchema := rateLimitAttributesSchema()
+	apiSchema["prefix"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Required:    true,
+		Description: "API path prefix the limits apply to, e.g. /instance/v1.",
+	}
+
+	rateLimitSchema := rateLimitAttributesSchema()
+	rateLimitSchema["api"] = &schema.Schema{
+		Type:        schema.TypeList,
+		Optional:    true,
+		Description: "Limits overriding the default ones for a given API.",
+		Elem: &schema.Resource{
+			Schema: apiSchema,
+		},
+	}
+
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Optional:    true,
+		MaxItems:    1,
+		Description: "Client-side rate limiting of the requests sent to Scaleway APIs, limits are applied per API.",
+		Elem: &schema.Resource{
+			Schema: rateLimitSchema,
+		},
+	}
+}
+
+func expandRateLimitOptions(raw map[string]interface{}) rateLimitOptions {
+	return rateLimitOptions{
+		RequestsPerSecond: raw["requests_per_second"].(float64),
+		Burst:             raw["burst"].(int),
+		MaxInFlight:       raw["max_in_flight"].(int),
+	}
+}
+
+func expandRateLimitedTransportOptions(d *schema.ResourceData) rateLimitedTransportOptions {
+	options := rateLimitedTransportOptions{}
+	if d == nil {
+		return options
+	}
+	rawRateLimits, ok := d.GetOk("rate_limit")
+	if !ok || len(rawRateLimits.([]interface{})) == 0 || rawRateLimits.([]interface{})[0] == nil {
+		return options
+	}
+
+	rawRateLimit := rawRateLimits.([]interface{})[0].(map[string]interface{})
+	options.Default = expandRateLimitOptions(rawRateLimit)
+	for _, rawAPI := range rawRateLimit["api"].([]interface{}) {
+		api := rawAPI.(map[string]interface{})
+		if options.APIs == nil {
+			options.APIs = map[string]rateLimitOptions{}
+		}
+		options.APIs["/"+strings.Trim(api["prefix"].(string), "/")] = expandRateLimitOptions(api)
+	}
+
+	return options
+}
+
+// tokenBucket is a simple token bucket refilled at rate tokens per second.
+type tokenBucket struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(rate float64, burst int) *tokenBucket {
+	if burst < 1 {
+		burst = 1
+	}
+	return &tokenBucket{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// reserve takes a token and returns how long to wait before retrying when none is available.
+func (b *tokenBucket) reserve(now time.Time) time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.tokens = math.Min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+	if b.tokens >= 1 {
+		b.tokens--
+		return 0
+	}
+	return time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+}
+
+func (b *tokenBucket) wait(ctx context.Context) error {
+	for {
+		delay := b.reserve(time.Now())
+		if delay == 0 {
+			return nil
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// apiRateLimiter holds the token bucket and the concurrency semaphore of an API.
+type apiRateLimiter struct {
+	bucket   *tokenBucket
+	inFlight chan struct{}
+}
+
+func newAPIRateLimiter(options rateLimitOptions) *apiRateLimiter {
+	limiter := &apiRateLimiter{}
+	if options.RequestsPerSecond > 0 {
+		limiter.bucket = newTokenBucket(options.RequestsPerSecond, options.Burst)
+	}
+	if options.MaxInFlight > 0 {
+		limiter.inFlight = make(chan struct{}, options.MaxInFlight)
+	}
+	return limiter
+}
+
+// acquire blocks until the request is allowed and returns a function releasing its concurrency slot.
+func (l *apiRateLimiter) acquire(ctx context.Context) (func(), error) {
+	release := func() {}
+	if l.inFlight != nil {
+		select {
+		case l.inFlight <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		release = func() { <-l.inFlight }
+	}
+	if l.bucket != nil {
+		if err := l.bucket.wait(ctx); err != nil {
+			release()
+			return nil, err
+		}
+	}
+	return release, nil
+}
+
+// rateLimitedTransport smooths the requests sent to each API to avoid being throttled.
+// Limiters are created lazily and shared by every request sent through the transport.
+type rateLimitedTransport struct {
+	transport http.RoundTripper
+	options   rateLimitedTransportOptions
+
+	mu       sync.Mutex
+	limiters map[string]*apiRateLimiter
+}
+
+func newRateLimitedTransport(transport http.RoundTripper, options rateLimitedTransportOptions) http.RoundTripper {
+	if !options.Default.isEnabled() && len(options.APIs) == 0 {
+		return transport
+	}
+	return &rateLimitedTransport{
+		transport: transport,
+		options:   options,
+		limiters:  map[string]*apiRateLimiter{},
+	}
+}
+
+// apiPathPrefix returns the API prefix of a path, e.g. /instance/v1 for /instance/v1/zones/fr-par-1/servers.
+func apiPathPrefix(path string) string {
+	parts := strings.SplitN(strings.TrimPrefix(path, "/"), "/", 3)
+	if len(parts) < 2 || !strings.HasPrefix(parts[1], "v") {
+		return ""
+	}
+	return "/" + parts[0] + "/" + parts[1]
+}
+
+func (t *rateLimitedTransport) limiter(r *http.Request) *apiRateLimiter {
+	prefix := apiPathPrefix(r.URL.Path)
+	options, hasOverride := t.options.APIs[prefix]
+	if !hasOverride {
+		options = t.options.Default
+	}
+	if !options.isEnabled() {
+		return nil
+	}
+
+	key := r.URL.Host + prefix
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	limiter, exists := t.limiters[key]
+	if !exists {
+		limiter = newAPIRateLimiter(options)
+		t.limiters[key] = limiter
+	}
+	return limiter
+}
+
+// RoundTrip waits for the API rate limiter before sending the request.
+// The concurrency slot is released as soon as the response headers are received,
+// so a response body that is never closed cannot starve other requests.
+func (t *rateLimitedTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	limiter := t.limiter(r)
+	if limiter == nil {
+		return t.transport.RoundTrip(r)
+	}
+
+	start := time.Now()
+	release, err := limiter.acquire(r.Context())
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	if waited := time.Since(start); waited > time.Second {
+		tflog.Debug(r.Context(), "request delayed by client-side rate limiter", map[string]interface{}{
+			"api_path": r.URL.Path,
+			"wait":     waited.String(),
+		})
+	}
+
+	return t.transport.RoundTrip(r)
+}
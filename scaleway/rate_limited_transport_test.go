@@ -0,0 +1,82 @@
+package scaleway
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIPathPrefix(t *testing.T) {
+	assert.Equal(t, "/instance/v1", apiPathPrefix("/instance/v1/zones/fr-par-1/servers"))
+	assert.Equal(t, "/k8s/v1", apiPathPrefix("/k8s/v1/regions/fr-par/clusters"))
+	assert.Equal(t, "/vpc/v2", apiPathPrefix("/vpc/v2"))
+	assert.Equal(t, "", apiPathPrefix("/my-bucket/my-object"))
+	assert.Equal(t, "", apiPathPrefix("/"))
+}
+
+func TestTokenBucket(t *testing.T) {
+	bucket := newTokenBucket(2, 2)
+	now := bucket.last
+
+	assert.Equal(t, time.Duration(0), bucket.reserve(now))
+	assert.Equal(t, time.Duration(0), bucket.reserve(now))
+	assert.Equal(t, 500*time.Millisecond, bucket.reserve(now))
+	assert.Equal(t, time.Duration(0), bucket.reserve(now.Add(500*time.Millisecond)))
+}
+
+func TestRateLimitedTransportMaxInFlight(t *testing.T) {
+	var inFlight, maxInFlight int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		current := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			observed := atomic.LoadInt32(&maxInFlight)
+			if current <= observed || atomic.CompareAndSwapInt32(&maxInFlight, observed, current) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: newRateLimitedTransport(http.DefaultTransport, rateLimitedTransportOptions{
+		APIs: map[string]rateLimitOptions{
+			"/instance/v1": {MaxInFlight: 2},
+		},
+	})}
+
+	// require must not be called outside of the test goroutine, the errors are checked once the requests are done.
+	errs := make(chan error, 10)
+	wg := sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, server.URL+"/instance/v1/zones/fr-par-1/servers", nil)
+			if err != nil {
+				errs <- err
+				return
+			}
+			resp, err := client.Do(req)
+			if err != nil {
+				errs <- err
+				return
+			}
+			_ = resp.Body.Close()
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		require.NoError(t, err)
+	}
+
+	assert.LessOrEqual(t, atomic.LoadInt32(&maxInFlight), int32(2))
+}
@@ -128,6 +128,7 @@ If this behaviour is wanted, please set 'reinstall_on_ssh_key_changes' argument
 				Computed:    true,
 				Description: "Array of tags to associate with the server",
 			},
+			"tags_all":        tagsAllSchema(),
 			"zone":            zoneSchema(),
 			"organization_id": organizationIDSchema(),
 			"project_id":      projectIDSchema(),
@@ -231,6 +232,7 @@ If this behaviour is wanted, please set 'reinstall_on_ssh_key_changes' argument
 		CustomizeDiff: customdiff.Sequence(
 			customizeDiffLocalityCheck("private_network.#.id"),
 			customDiffBaremetalPrivateNetworkOption(),
+			customizeDiffTagsAll,
 		),
 	}
 }
@@ -267,7 +269,7 @@ func resourceScalewayBaremetalServerCreate(ctx context.Context, d *schema.Resour
 		ProjectID:   expandStringPtr(d.Get("project_id")),
 		Description: d.Get("description").(string),
 		OfferID:     offerID.ID,
-		Tags:        expandStrings(d.Get("tags")),
+		Tags:        expandTagsAll(d, meta),
 	}, scw.WithContext(ctx))
 	if err != nil {
 		return diag.FromErr(err)
@@ -391,8 +393,12 @@ func resourceScalewayBaremetalServerRead(ctx context.Context, d *schema.Resource
 	_ = d.Set("organization_id", server.OrganizationID)
 	_ = d.Set("project_id", server.ProjectID)
 	_ = d.Set("offer_id", newZonedIDString(server.Zone, offer.ID))
+	// offer may be configured with the offer name, only set it when importing.
+	if _, exists := d.GetOk("offer"); !exists {
+		_ = d.Set("offer", newZonedIDString(server.Zone, offer.ID))
+	}
 	_ = d.Set("offer_name", offer.Name)
-	_ = d.Set("tags", server.Tags)
+	setTags(d, meta, server.Tags)
 	_ = d.Set("domain", server.Domain)
 	_ = d.Set("ips", flattenBaremetalIPs(server.IPs))
 	if server.Install != nil {
@@ -516,8 +522,8 @@ func resourceScalewayBaremetalServerUpdate(ctx context.Context, d *schema.Resour
 		hasChanged = true
 	}
 
-	if d.HasChange("tags") {
-		req.Tags = expandUpdatedStringsPtr(d.Get("tags"))
+	if d.HasChanges("tags", "tags_all") {
+		req.Tags = expandUpdatedTagsAll(d, meta)
 		hasChanged = true
 	}
 
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	flexibleip "github.com/scaleway/scaleway-sdk-go/api/flexibleip/v1alpha1"
 	"github.com/scaleway/scaleway-sdk-go/scw"
@@ -60,6 +61,7 @@ func resourceScalewayFlexibleIP() *schema.Resource {
 				Optional:    true,
 				Description: "The tags associated with the flexible IP",
 			},
+			"tags_all":        tagsAllSchema(),
 			"zone":            zoneSchema(),
 			"organization_id": organizationIDSchema(),
 			"project_id":      projectIDSchema(),
@@ -74,7 +76,10 @@ func resourceScalewayFlexibleIP() *schema.Resource {
 				Description: "The date and time of the last update of the Flexible IP (Format ISO 8601)",
 			},
 		},
-		CustomizeDiff: customizeDiffLocalityCheck("server_id"),
+		CustomizeDiff: customdiff.Sequence(
+			customizeDiffLocalityCheck("server_id"),
+			customizeDiffTagsAll,
+		),
 	}
 }
 
@@ -88,7 +93,7 @@ func resourceScalewayFlexibleIPCreate(ctx context.Context, d *schema.ResourceDat
 		Zone:        zone,
 		ProjectID:   d.Get("project_id").(string),
 		Description: d.Get("description").(string),
-		Tags:        expandStrings(d.Get("tags")),
+		Tags:        expandTagsAll(d, meta),
 		ServerID:    expandStringPtr(expandID(d.Get("server_id"))),
 		Reverse:     expandStringPtr(d.Get("reverse")),
 	}, scw.WithContext(ctx))
@@ -131,6 +136,7 @@ func resourceScalewayFlexibleIPRead(ctx context.Context, d *schema.ResourceData,
 	}
 
 	_ = d.Set("ip_address", flexibleIP.IPAddress.String())
+	_ = d.Set("description", flexibleIP.Description)
 	_ = d.Set("zone", flexibleIP.Zone)
 	_ = d.Set("organization_id", flexibleIP.OrganizationID)
 	_ = d.Set("project_id", flexibleIP.ProjectID)
@@ -169,8 +175,8 @@ func resourceScalewayFlexibleIPUpdate(ctx context.Context, d *schema.ResourceDat
 		hasChanged = true
 	}
 
-	if d.HasChange("tags") {
-		updateRequest.Tags = expandUpdatedStringsPtr(d.Get("tags"))
+	if d.HasChanges("tags", "tags_all") {
+		updateRequest.Tags = expandUpdatedTagsAll(d, meta)
 		hasChanged = true
 	}
 
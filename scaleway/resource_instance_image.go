@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/scaleway/scaleway-sdk-go/api/instance/v1"
@@ -69,6 +70,7 @@ func resourceScalewayInstanceImage() *schema.Resource {
 					Type: schema.TypeString,
 				},
 			},
+			"tags_all": tagsAllSchema(),
 			"public": {
 				Type:        schema.TypeBool,
 				Optional:    true,
@@ -168,7 +170,10 @@ func resourceScalewayInstanceImage() *schema.Resource {
 			"project_id":      projectIDSchema(),
 			"organization_id": organizationIDSchema(),
 		},
-		CustomizeDiff: customizeDiffLocalityCheck("root_volume_id", "additional_volume_ids.#"),
+		CustomizeDiff: customdiff.Sequence(
+			customizeDiffLocalityCheck("root_volume_id", "additional_volume_ids.#"),
+			customizeDiffTagsAll,
+		),
 	}
 }
 
@@ -195,9 +200,8 @@ func resourceScalewayInstanceImageCreate(ctx context.Context, d *schema.Resource
 		}
 		req.ExtraVolumes = expandInstanceImageExtraVolumesTemplates(snapResponses)
 	}
-	tags, tagsExist := d.GetOk("tags")
-	if tagsExist {
-		req.Tags = expandStrings(tags)
+	if tags := expandTagsAll(d, meta); len(tags) > 0 {
+		req.Tags = tags
 	}
 	if _, exist := d.GetOk("public"); exist {
 		req.Public = expandBoolPtr(getBool(d, "public"))
@@ -245,7 +249,15 @@ func resourceScalewayInstanceImageRead(ctx context.Context, d *schema.ResourceDa
 	_ = d.Set("root_volume_id", newZonedIDString(image.Image.Zone, image.Image.RootVolume.ID))
 	_ = d.Set("architecture", image.Image.Arch)
 	_ = d.Set("additional_volumes", flattenInstanceImageExtraVolumes(image.Image.ExtraVolumes, zone))
-	_ = d.Set("tags", image.Image.Tags)
+	// Only set when importing, the image volumes are the snapshots the image was created from.
+	if _, exists := d.GetOk("additional_volume_ids"); !exists && len(image.Image.ExtraVolumes) > 0 {
+		additionalVolumeIDs := []string(nil)
+		for _, volume := range orderVolumes(image.Image.ExtraVolumes) {
+			additionalVolumeIDs = append(additionalVolumeIDs, newZonedIDString(zone, volume.ID))
+		}
+		_ = d.Set("additional_volume_ids", additionalVolumeIDs)
+	}
+	setTags(d, meta, image.Image.Tags)
 	_ = d.Set("public", image.Image.Public)
 	_ = d.Set("creation_date", flattenTime(image.Image.CreationDate))
 	_ = d.Set("modification_date", flattenTime(image.Image.ModificationDate))
@@ -278,7 +290,7 @@ func resourceScalewayInstanceImageUpdate(ctx context.Context, d *schema.Resource
 	if d.HasChange("public") {
 		req.Public = *expandBoolPtr(getBool(d, "public"))
 	}
-	req.Tags = expandUpdatedStringsPtr(d.Get("tags"))
+	req.Tags = expandUpdatedTagsAll(d, meta)
 
 	image, err := instanceAPI.GetImage(&instance.GetImageRequest{
 		Zone:    zone,
@@ -0,0 +1,433 @@
+package scaleway
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/scaleway/scaleway-sdk-go/api/instance/v1"
+	"github.com/scaleway/scaleway-sdk-go/scw"
+)
+
+const defaultInstanceImageBuildCompletionKey = "image-build-complete"
+
+func resourceScalewayInstanceImageBuild() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceScalewayInstanceImageBuildCreate,
+		ReadContext:   resourceScalewayInstanceImageBuildRead,
+		UpdateContext: resourceScalewayInstanceImageBuildUpdate,
+		DeleteContext: resourceScalewayInstanceImageBuildDelete,
+		Timeouts: &schema.ResourceTimeout{
+			Create:  schema.DefaultTimeout(defaultInstanceImageTimeout),
+			Update:  schema.DefaultTimeout(defaultInstanceImageTimeout),
+			Delete:  schema.DefaultTimeout(defaultInstanceImageTimeout),
+			Default: schema.DefaultTimeout(defaultInstanceImageTimeout),
+		},
+		SchemaVersion: 0,
+		Schema: map[string]*schema.Schema{
+			"name_prefix": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The prefix of the name of the image, followed by the build time",
+			},
+			"base_image": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				Description:      "The UUID or the label of the image the builder server boots from",
+				DiffSuppressFunc: diffSuppressFuncLocality,
+			},
+			"type": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				Description:      "The instance type of the builder server",
+				DiffSuppressFunc: diffSuppressFuncIgnoreCase,
+			},
+			"root_volume_size_in_gb": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "The size of the root volume of the builder server, and of the image",
+			},
+			"security_group_id": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ForceNew:         true,
+				Description:      "The security group of the builder server",
+				ValidateFunc:     validationUUIDorUUIDWithLocality(),
+				DiffSuppressFunc: diffSuppressFuncLocality,
+			},
+			"enable_dynamic_ip": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     true,
+				Description: "Attach a dynamic public IP to the builder server, to reach the internet while provisioning",
+			},
+			"cloud_init": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				Description:  "The cloud init script provisioning the builder server",
+				ValidateFunc: validation.StringLenBetween(0, 127998),
+			},
+			"user_data": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "The user data of the builder server",
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.StringLenBetween(0, 127998),
+				},
+			},
+			"completion_user_data_key": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     defaultInstanceImageBuildCompletionKey,
+				Description: "The user data key the provisioning sets on the builder server once done",
+			},
+			"retention": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      0,
+				Description:  "The number of images built with the name prefix to keep, the older ones are deleted (0 keeps them all)",
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"keep_builder_on_failure": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Keep the builder server when the build fails, to investigate it",
+			},
+			"tags": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: "The tags of the image",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"tags_all": tagsAllSchema(),
+			// Computed
+			"name": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The name of the image",
+			},
+			"architecture": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The architecture of the image",
+			},
+			"root_snapshot_id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The snapshot of the root volume of the image",
+			},
+			"creation_date": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The date and time of the creation of the image",
+			},
+			"state": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The state of the image",
+			},
+			"zone":            zoneSchema(),
+			"project_id":      projectIDSchema(),
+			"organization_id": organizationIDSchema(),
+		},
+		CustomizeDiff: customdiff.Sequence(
+			customizeDiffLocalityCheck("security_group_id"),
+			customizeDiffTagsAll,
+		),
+	}
+}
+
+func resourceScalewayInstanceImageBuildCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	instanceAPI, zone, err := instanceAPIWithZone(d, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	projectID, _, err := extractProjectID(d, meta.(*Meta))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	commercialType := d.Get("type").(string)
+	imageUUID, err := instanceImageUUID(ctx, meta, zone, commercialType, d.Get("base_image").(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	namePrefix := d.Get("name_prefix").(string)
+	req := &instance.CreateServerRequest{
+		Zone:              zone,
+		Name:              namePrefix + "-builder",
+		Project:           &projectID,
+		Image:             imageUUID,
+		CommercialType:    commercialType,
+		SecurityGroup:     expandStringPtr(expandID(d.Get("security_group_id"))),
+		DynamicIPRequired: scw.BoolPtr(d.Get("enable_dynamic_ip").(bool)),
+		Tags:              []string{instanceImageBuildTag},
+	}
+	if sizeInGB := d.Get("root_volume_size_in_gb").(int); sizeInGB != 0 {
+		req.Volumes = map[string]*instance.VolumeServerTemplate{
+			"0": {Size: expandInstanceVolumeSize(scw.Size(uint64(sizeInGB) * gb))},
+		}
+		req.Volumes = sanitizeVolumeMap(req.Volumes)
+	}
+
+	res, err := instanceAPI.CreateServer(req, scw.WithContext(ctx))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	image, err := buildInstanceImage(ctx, d, meta, instanceAPI, zone, projectID, res.Server.ID)
+	if err != nil {
+		if d.Get("keep_builder_on_failure").(bool) {
+			return diag.FromErr(fmt.Errorf("%w, builder server %s is kept", err, newZonedIDString(zone, res.Server.ID)))
+		}
+		if deleteErr := deleteInstanceImageBuilder(ctx, instanceAPI, zone, res.Server.ID, d.Timeout(schema.TimeoutCreate)); deleteErr != nil {
+			return diag.FromErr(fmt.Errorf("%w, couldn't delete builder server %s: %s", err, res.Server.ID, deleteErr))
+		}
+		return diag.FromErr(err)
+	}
+
+	d.SetId(newZonedIDString(zone, image.ID))
+
+	err = deleteInstanceImageBuilder(ctx, instanceAPI, zone, res.Server.ID, d.Timeout(schema.TimeoutCreate))
+	if err != nil {
+		return diag.FromErr(fmt.Errorf("couldn't delete builder server %s: %w", res.Server.ID, err))
+	}
+
+	err = applyInstanceImageBuildRetention(ctx, instanceAPI, zone, projectID, namePrefix, d.Get("retention").(int), image.ID, d.Timeout(schema.TimeoutCreate))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	return resourceScalewayInstanceImageBuildRead(ctx, d, meta)
+}
+
+// buildInstanceImage provisions the builder server, powers it off once the completion user data key is set,
+// and creates the image from a snapshot of its root volume.
+func buildInstanceImage(ctx context.Context, d *schema.ResourceData, meta interface{}, instanceAPI *instance.API, zone scw.Zone, projectID string, serverID string) (*instance.Image, error) {
+	_, err := waitForInstanceServer(ctx, instanceAPI, zone, serverID, d.Timeout(schema.TimeoutCreate))
+	if err != nil {
+		return nil, err
+	}
+
+	userData := map[string]io.Reader{}
+	if rawUserData, ok := d.GetOk("user_data"); ok {
+		for key, value := range rawUserData.(map[string]interface{}) {
+			userData[key] = bytes.NewBufferString(value.(string))
+		}
+	}
+	if cloudInit, ok := d.GetOk("cloud_init"); ok {
+		userData["cloud-init"] = bytes.NewBufferString(cloudInit.(string))
+	}
+	if len(userData) > 0 {
+		err = instanceAPI.SetAllServerUserData(&instance.SetAllServerUserDataRequest{
+			Zone:     zone,
+			ServerID: serverID,
+			UserData: userData,
+		}, scw.WithContext(ctx))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = reachState(ctx, instanceAPI, zone, serverID, instance.ServerStateRunning, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// The server is shut down, so that its filesystem is consistent in the snapshot.
+	err = reachState(ctx, instanceAPI, zone, serverID, instance.ServerStateStopped, &serverLifecycle{
+		StopAction: InstanceServerStopActionGraceful,
+		PreStop: &serverWaitCondition{
+			UserDataKey: d.Get("completion_user_data_key").(string),
+			Timeout:     d.Timeout(schema.TimeoutCreate),
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("provisioning of the builder server did not complete: %w", err)
+	}
+
+	server, err := waitForInstanceServer(ctx, instanceAPI, zone, serverID, d.Timeout(schema.TimeoutCreate))
+	if err != nil {
+		return nil, err
+	}
+	rootVolume, hasRootVolume := server.Volumes["0"]
+	if !hasRootVolume {
+		return nil, fmt.Errorf("builder server %s has no root volume", serverID)
+	}
+
+	name := instanceImageBuildName(d.Get("name_prefix").(string))
+	snapshot, err := instanceAPI.CreateSnapshot(&instance.CreateSnapshotRequest{
+		Zone:     zone,
+		Name:     name + "-root",
+		VolumeID: &rootVolume.ID,
+		Project:  &projectID,
+		Tags:     []string{instanceImageBuildTag},
+	}, scw.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	_, err = waitForInstanceSnapshot(ctx, instanceAPI, zone, snapshot.Snapshot.ID, d.Timeout(schema.TimeoutCreate))
+	if err != nil {
+		return nil, err
+	}
+
+	image, err := instanceAPI.CreateImage(&instance.CreateImageRequest{
+		Zone:       zone,
+		Name:       name,
+		RootVolume: snapshot.Snapshot.ID,
+		Arch:       server.Arch,
+		Project:    &projectID,
+		Tags:       instanceImageBuildTags(expandTagsAll(d, meta), d.Get("name_prefix").(string)),
+	}, scw.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	return waitForInstanceImage(ctx, instanceAPI, zone, image.Image.ID, d.Timeout(schema.TimeoutCreate))
+}
+
+// deleteInstanceImageBuilder deletes the builder server with its volumes, the image only relies on the snapshots.
+func deleteInstanceImageBuilder(ctx context.Context, instanceAPI *instance.API, zone scw.Zone, serverID string, timeout time.Duration) error {
+	server, err := waitForInstanceServer(ctx, instanceAPI, zone, serverID, timeout)
+	if err != nil {
+		if is404Error(err) {
+			return nil
+		}
+		return err
+	}
+
+	return deleteInstanceServerAndVolumes(ctx, instanceAPI, zone, server)
+}
+
+func resourceScalewayInstanceImageBuildRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	instanceAPI, zone, id, err := instanceAPIWithZoneAndID(meta, d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	res, err := instanceAPI.GetImage(&instance.GetImageRequest{
+		Zone:    zone,
+		ImageID: id,
+	}, scw.WithContext(ctx))
+	if err != nil {
+		if is404Error(err) {
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(err)
+	}
+	image := res.Image
+
+	_ = d.Set("name", image.Name)
+	_ = d.Set("architecture", image.Arch.String())
+	if image.RootVolume != nil {
+		_ = d.Set("root_snapshot_id", newZonedIDString(zone, image.RootVolume.ID))
+	}
+	_ = d.Set("creation_date", flattenTime(image.CreationDate))
+	_ = d.Set("state", image.State.String())
+	_ = d.Set("zone", zone.String())
+	_ = d.Set("project_id", image.Project)
+	_ = d.Set("organization_id", image.Organization)
+	tags := []string(nil)
+	for _, tag := range image.Tags {
+		if tag != instanceImageBuildTag && !strings.HasPrefix(tag, instanceImageBuildPrefixTagPrefix) {
+			tags = append(tags, tag)
+		}
+	}
+	setTags(d, meta, tags)
+
+	return nil
+}
+
+func resourceScalewayInstanceImageBuildUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	instanceAPI, zone, id, err := instanceAPIWithZoneAndID(meta, d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if d.HasChanges("tags", "tags_all") {
+		image, err := waitForInstanceImage(ctx, instanceAPI, zone, id, d.Timeout(schema.TimeoutUpdate))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		// The image is sent back with its new tags, as UpdateImage expects every field of the image.
+		tags := instanceImageBuildTags(expandTagsAll(d, meta), d.Get("name_prefix").(string))
+		extraVolumes := map[string]*instance.VolumeTemplate{}
+		for key, volume := range image.ExtraVolumes {
+			extraVolumes[key] = &instance.VolumeTemplate{
+				ID:         volume.ID,
+				Name:       volume.Name,
+				Size:       volume.Size,
+				VolumeType: volume.VolumeType,
+			}
+		}
+		_, err = instanceAPI.UpdateImage(&instance.UpdateImageRequest{
+			Zone:             zone,
+			ImageID:          id,
+			Name:             &image.Name,
+			Arch:             image.Arch,
+			RootVolume:       image.RootVolume,
+			ExtraVolumes:     extraVolumes,
+			FromServer:       image.FromServer,
+			CreationDate:     image.CreationDate,
+			ModificationDate: image.ModificationDate,
+			Public:           image.Public,
+			Tags:             &tags,
+		}, scw.WithContext(ctx))
+		if err != nil {
+			return diag.FromErr(fmt.Errorf("couldn't update image: %s", err))
+		}
+	}
+
+	if d.HasChange("retention") {
+		err = applyInstanceImageBuildRetention(ctx, instanceAPI, zone, d.Get("project_id").(string), d.Get("name_prefix").(string), d.Get("retention").(int), id, d.Timeout(schema.TimeoutUpdate))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return resourceScalewayInstanceImageBuildRead(ctx, d, meta)
+}
+
+func resourceScalewayInstanceImageBuildDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	instanceAPI, zone, id, err := instanceAPIWithZoneAndID(meta, d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	image, err := waitForInstanceImage(ctx, instanceAPI, zone, id, d.Timeout(schema.TimeoutDelete))
+	if err != nil {
+		if is404Error(err) {
+			return nil
+		}
+		return diag.FromErr(err)
+	}
+
+	err = deleteInstanceImageAndSnapshots(ctx, instanceAPI, zone, image, d.Timeout(schema.TimeoutDelete))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
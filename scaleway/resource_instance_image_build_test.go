@@ -0,0 +1,193 @@
+package scaleway
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/scaleway/scaleway-sdk-go/api/instance/v1"
+	"github.com/scaleway/scaleway-sdk-go/scw"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccScalewayInstanceImageBuild_Basic(t *testing.T) {
+	tt := NewTestTools(t)
+	defer tt.Cleanup()
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: tt.ProviderFactories,
+		CheckDestroy:      testAccCheckScalewayInstanceImageBuildDestroy(tt),
+		Steps: []resource.TestStep{
+			{
+				// The completion key is set from the start, the build does not wait for any provisioning.
+				Config: `
+					resource "scaleway_instance_image_build" "main" {
+						name_prefix = "tf-tests-image-build"
+						base_image  = "ubuntu_jammy"
+						type        = "DEV1-S"
+						retention   = 1
+						user_data   = {
+							image-build-complete = "true"
+						}
+						tags = ["terraform-test", "scaleway_instance_image_build", "basic"]
+					}
+				`,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckScalewayInstanceImageExists(tt, "scaleway_instance_image_build.main"),
+					resource.TestMatchResourceAttr("scaleway_instance_image_build.main", "name", regexp.MustCompile(`^tf-tests-image-build-\d{8}-\d{6}$`)),
+					resource.TestCheckResourceAttr("scaleway_instance_image_build.main", "architecture", "x86_64"),
+					resource.TestCheckResourceAttr("scaleway_instance_image_build.main", "state", "available"),
+					resource.TestCheckResourceAttrSet("scaleway_instance_image_build.main", "root_snapshot_id"),
+					resource.TestCheckResourceAttr("scaleway_instance_image_build.main", "tags.#", "3"),
+				),
+			},
+			{
+				Config: `
+					resource "scaleway_instance_image_build" "main" {
+						name_prefix = "tf-tests-image-build"
+						base_image  = "ubuntu_jammy"
+						type        = "DEV1-S"
+						retention   = 2
+						user_data   = {
+							image-build-complete = "true"
+						}
+						tags = ["terraform-test", "scaleway_instance_image_build"]
+					}
+				`,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckScalewayInstanceImageExists(tt, "scaleway_instance_image_build.main"),
+					resource.TestCheckResourceAttr("scaleway_instance_image_build.main", "retention", "2"),
+					resource.TestCheckResourceAttr("scaleway_instance_image_build.main", "tags.#", "2"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckScalewayInstanceImageBuildDestroy(tt *TestTools) resource.TestCheckFunc {
+	return func(state *terraform.State) error {
+		for _, rs := range state.RootModule().Resources {
+			if rs.Type != "scaleway_instance_image_build" {
+				continue
+			}
+			instanceAPI, zone, ID, err := instanceAPIWithZoneAndID(tt.Meta, rs.Primary.ID)
+			if err != nil {
+				return err
+			}
+			_, err = instanceAPI.GetImage(&instance.GetImageRequest{
+				ImageID: ID,
+				Zone:    zone,
+			})
+			// If no error resource still exist
+			if err == nil {
+				return fmt.Errorf("image (%s) still exists", rs.Primary.ID)
+			}
+			// Unexpected api error we return it
+			if !is404Error(err) {
+				return err
+			}
+			_, err = instanceAPI.GetSnapshot(&instance.GetSnapshotRequest{
+				SnapshotID: expandID(rs.Primary.Attributes["root_snapshot_id"]),
+				Zone:       zone,
+			})
+			if err == nil {
+				return fmt.Errorf("snapshot of image (%s) still exists", rs.Primary.ID)
+			}
+			if !is404Error(err) {
+				return err
+			}
+		}
+
+		return nil
+	}
+}
+
+func TestFakeAPI_InstanceImageBuild(t *testing.T) {
+	tt := NewFakeAPITestTools(t)
+	defer tt.Cleanup()
+	ctx := context.Background()
+
+	instanceAPI := instance.NewAPI(tt.Meta.scwClient)
+	zone := scw.ZoneFrPar1
+	listBuilders := func() []*instance.Server {
+		res, err := instanceAPI.ListServers(&instance.ListServersRequest{Zone: zone, Tags: []string{instanceImageBuildTag}})
+		require.NoError(t, err)
+		return res.Servers
+	}
+	build := func(ctx context.Context, raw map[string]interface{}) (*schema.ResourceData, diag.Diagnostics) {
+		config := map[string]interface{}{
+			"name_prefix": "web",
+			"base_image":  "11111111-1111-1111-1111-111111111111",
+			"type":        "DEV1-S",
+			"cloud_init":  "#cloud-config",
+			"retention":   2,
+			"tags":        []interface{}{"golden"},
+			// The provisioning would set the completion key once done.
+			"user_data": map[string]interface{}{defaultInstanceImageBuildCompletionKey: "true"},
+		}
+		for key, value := range raw {
+			config[key] = value
+		}
+		d := schema.TestResourceDataRaw(t, resourceScalewayInstanceImageBuild().Schema, config)
+		return d, resourceScalewayInstanceImageBuildCreate(ctx, d, tt.Meta)
+	}
+
+	first, diags := build(ctx, nil)
+	require.False(t, diags.HasError(), diags)
+	assert.Contains(t, first.Get("name"), "web-")
+	assert.Equal(t, "x86_64", first.Get("architecture"))
+	assert.Equal(t, []interface{}{"golden"}, first.Get("tags"))
+	assert.Empty(t, listBuilders())
+	_, err := instanceAPI.GetSnapshot(&instance.GetSnapshotRequest{Zone: zone, SnapshotID: expandID(first.Get("root_snapshot_id"))})
+	require.NoError(t, err)
+
+	// The retention of a prefix does not apply to the images of the prefixes starting with it, and the other way around.
+	api, diags := build(ctx, map[string]interface{}{"name_prefix": "web-api", "retention": 1})
+	require.False(t, diags.HasError(), diags)
+	assert.Regexp(t, `^web-api-\d{8}-\d{6}$`, api.Get("name"))
+
+	// Only the last 2 images are kept, the older ones are deleted with their snapshot.
+	second, diags := build(ctx, nil)
+	require.False(t, diags.HasError(), diags)
+	third, diags := build(ctx, nil)
+	require.False(t, diags.HasError(), diags)
+	_, err = instanceAPI.GetImage(&instance.GetImageRequest{Zone: zone, ImageID: expandID(first.Id())})
+	assert.True(t, is404Error(err))
+	_, err = instanceAPI.GetSnapshot(&instance.GetSnapshotRequest{Zone: zone, SnapshotID: expandID(first.Get("root_snapshot_id"))})
+	assert.True(t, is404Error(err))
+	_, err = instanceAPI.GetImage(&instance.GetImageRequest{Zone: zone, ImageID: expandID(second.Id())})
+	require.NoError(t, err)
+	_, err = instanceAPI.GetImage(&instance.GetImageRequest{Zone: zone, ImageID: expandID(api.Id())})
+	require.NoError(t, err)
+	_, diags = build(ctx, map[string]interface{}{"name_prefix": "web-api", "retention": 1})
+	require.False(t, diags.HasError(), diags)
+	_, err = instanceAPI.GetImage(&instance.GetImageRequest{Zone: zone, ImageID: expandID(api.Id())})
+	assert.True(t, is404Error(err))
+	_, err = instanceAPI.GetImage(&instance.GetImageRequest{Zone: zone, ImageID: expandID(third.Id())})
+	require.NoError(t, err)
+
+	// The builder can be kept when the provisioning does not complete.
+	timeoutCtx, cancel := context.WithTimeout(ctx, 200*time.Millisecond)
+	defer cancel()
+	_, diags = build(timeoutCtx, map[string]interface{}{
+		"user_data":               map[string]interface{}{},
+		"keep_builder_on_failure": true,
+	})
+	require.True(t, diags.HasError())
+	assert.Contains(t, diags[0].Summary, "is kept")
+	assert.Len(t, listBuilders(), 1)
+
+	diags = resourceScalewayInstanceImageBuildDelete(ctx, third, tt.Meta)
+	require.False(t, diags.HasError(), diags)
+	_, err = instanceAPI.GetImage(&instance.GetImageRequest{Zone: zone, ImageID: expandID(third.Id())})
+	assert.True(t, is404Error(err))
+	_, err = instanceAPI.GetSnapshot(&instance.GetSnapshotRequest{Zone: zone, SnapshotID: expandID(third.Get("root_snapshot_id"))})
+	assert.True(t, is404Error(err))
+}
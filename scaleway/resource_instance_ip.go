@@ -5,6 +5,7 @@ import (
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/scaleway/scaleway-sdk-go/api/instance/v1"
 	"github.com/scaleway/scaleway-sdk-go/scw"
 )
@@ -33,6 +34,23 @@ func resourceScalewayInstanceIP() *schema.Resource {
 				Computed:    true,
 				Description: "The reverse DNS for this IP",
 			},
+			"type": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Description: "The type of the IP (nat, routed_ipv4 or routed_ipv6)",
+				ValidateFunc: validation.StringInSlice([]string{
+					instanceIPTypeNAT,
+					instanceIPTypeRoutedIPv4,
+					instanceIPTypeRoutedIPv6,
+				}, false),
+			},
+			"prefix": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The IPv6 prefix of a routed_ipv6 IP",
+			},
 			"server_id": {
 				Type:        schema.TypeString,
 				Computed:    true,
@@ -46,10 +64,12 @@ func resourceScalewayInstanceIP() *schema.Resource {
 				Optional:    true,
 				Description: "The tags associated with the ip",
 			},
+			"tags_all":        tagsAllSchema(),
 			"zone":            zoneSchema(),
 			"organization_id": organizationIDSchema(),
 			"project_id":      projectIDSchema(),
 		},
+		CustomizeDiff: customizeDiffTagsAll,
 	}
 }
 
@@ -62,20 +82,31 @@ func resourceScalewayInstanceIPCreate(ctx context.Context, d *schema.ResourceDat
 		Zone:    zone,
 		Project: expandStringPtr(d.Get("project_id")),
 	}
-	tags := expandStrings(d.Get("tags"))
+	tags := expandTagsAll(d, meta)
 	if len(tags) > 0 {
 		iprequest.Tags = tags
 	}
-	res, err := instanceAPI.CreateIP(iprequest, scw.WithContext(ctx))
-	if err != nil {
-		return diag.FromErr(err)
+
+	var ipID string
+	if ipType, ok := d.GetOk("type"); ok {
+		ip, err := createInstanceIPWithType(ctx, meta.(*Meta).scwClient, iprequest, ipType.(string))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		ipID = ip.ID
+	} else {
+		res, err := instanceAPI.CreateIP(iprequest, scw.WithContext(ctx))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		ipID = res.IP.ID
 	}
 
 	reverseRaw, ok := d.GetOk("reverse")
 	if ok {
 		reverseStrPtr := expandStringPtr(reverseRaw)
 		req := &instance.UpdateIPRequest{
-			IP:      res.IP.ID,
+			IP:      ipID,
 			Reverse: &instance.NullableStringValue{Value: *reverseStrPtr},
 			Zone:    zone,
 		}
@@ -85,7 +116,7 @@ func resourceScalewayInstanceIPCreate(ctx context.Context, d *schema.ResourceDat
 		}
 	}
 
-	d.SetId(newZonedIDString(zone, res.IP.ID))
+	d.SetId(newZonedIDString(zone, ipID))
 	return resourceScalewayInstanceIPRead(ctx, d, meta)
 }
 
@@ -99,8 +130,8 @@ func resourceScalewayInstanceIPUpdate(ctx context.Context, d *schema.ResourceDat
 		Zone: zone,
 	}
 
-	if d.HasChange("tags") {
-		req.Tags = expandUpdatedStringsPtr(d.Get("tags"))
+	if d.HasChanges("tags", "tags_all") {
+		req.Tags = expandUpdatedTagsAll(d, meta)
 	}
 
 	_, err = instanceAPI.UpdateIP(req, scw.WithContext(ctx))
@@ -112,15 +143,12 @@ func resourceScalewayInstanceIPUpdate(ctx context.Context, d *schema.ResourceDat
 }
 
 func resourceScalewayInstanceIPRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	instanceAPI, zone, ID, err := instanceAPIWithZoneAndID(meta, d.Id())
+	_, zone, ID, err := instanceAPIWithZoneAndID(meta, d.Id())
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
-	res, err := instanceAPI.GetIP(&instance.GetIPRequest{
-		IP:   ID,
-		Zone: zone,
-	}, scw.WithContext(ctx))
+	ip, err := getInstanceIP(ctx, meta.(*Meta).scwClient, zone, ID)
 	if err != nil {
 		// We check for 403 because instance API returns 403 for a deleted IP
 		if is404Error(err) || is403Error(err) {
@@ -130,17 +158,27 @@ func resourceScalewayInstanceIPRead(ctx context.Context, d *schema.ResourceData,
 		return diag.FromErr(err)
 	}
 
-	_ = d.Set("address", res.IP.Address.String())
+	if ip.Address != nil {
+		_ = d.Set("address", ip.Address.String())
+	} else {
+		_ = d.Set("address", "")
+	}
+	ipType := ip.Type
+	if ipType == "" {
+		ipType = instanceIPTypeNAT
+	}
+	_ = d.Set("type", ipType)
+	_ = d.Set("prefix", ip.Prefix)
 	_ = d.Set("zone", zone)
-	_ = d.Set("organization_id", res.IP.Organization)
-	_ = d.Set("project_id", res.IP.Project)
-	_ = d.Set("reverse", res.IP.Reverse)
-	if len(res.IP.Tags) > 0 {
-		_ = d.Set("tags", flattenSliceString(res.IP.Tags))
+	_ = d.Set("organization_id", ip.Organization)
+	_ = d.Set("project_id", ip.Project)
+	_ = d.Set("reverse", ip.Reverse)
+	if len(ip.Tags) > 0 {
+		setTags(d, meta, ip.Tags)
 	}
 
-	if res.IP.Server != nil {
-		_ = d.Set("server_id", newZonedIDString(res.IP.Zone, res.IP.Server.ID))
+	if ip.Server != nil {
+		_ = d.Set("server_id", newZonedIDString(zone, ip.Server.ID))
 	} else {
 		_ = d.Set("server_id", "")
 	}
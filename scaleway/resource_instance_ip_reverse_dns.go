@@ -67,7 +67,7 @@ func resourceScalewayInstanceIPReverseDNSCreate(ctx context.Context, d *schema.R
 		}
 
 		if reverse, ok := d.GetOk("reverse"); ok {
-			if isInstanceIPReverseResolved(ctx, instanceAPI, reverse.(string), d.Timeout(schema.TimeoutCreate), res.IP.ID, zone) {
+			if isInstanceIPReverseResolved(ctx, meta.(*Meta).scwClient, reverse.(string), d.Timeout(schema.TimeoutCreate), res.IP.ID, zone) {
 				updateReverseReq.Reverse = &instance.NullableStringValue{Value: reverse.(string)}
 			} else {
 				return diag.FromErr(fmt.Errorf("your reverse must resolve. Ensure the command 'dig +short %s' matches your IP address ", reverse.(string)))
@@ -123,7 +123,7 @@ func resourceScalewayInstanceIPReverseDNSUpdate(ctx context.Context, d *schema.R
 		}
 
 		if reverse, ok := d.GetOk("reverse"); ok {
-			if isInstanceIPReverseResolved(ctx, instanceAPI, reverse.(string), d.Timeout(schema.TimeoutUpdate), ID, zone) {
+			if isInstanceIPReverseResolved(ctx, meta.(*Meta).scwClient, reverse.(string), d.Timeout(schema.TimeoutUpdate), ID, zone) {
 				updateReverseReq.Reverse = &instance.NullableStringValue{Value: reverse.(string)}
 			} else {
 				return diag.FromErr(fmt.Errorf("your reverse must resolve. Ensure the command 'dig +short %s' matches your IP address ", reverse.(string)))
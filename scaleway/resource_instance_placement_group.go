@@ -63,10 +63,12 @@ func resourceScalewayInstancePlacementGroup() *schema.Resource {
 				Optional:    true,
 				Description: "The tags associated with the placement group",
 			},
+			"tags_all":        tagsAllSchema(),
 			"zone":            zoneSchema(),
 			"organization_id": organizationIDSchema(),
 			"project_id":      projectIDSchema(),
 		},
+		CustomizeDiff: customizeDiffTagsAll,
 	}
 }
 
@@ -82,7 +84,7 @@ func resourceScalewayInstancePlacementGroupCreate(ctx context.Context, d *schema
 		Project:    expandStringPtr(d.Get("project_id")),
 		PolicyMode: instance.PlacementGroupPolicyMode(d.Get("policy_mode").(string)),
 		PolicyType: instance.PlacementGroupPolicyType(d.Get("policy_type").(string)),
-		Tags:       expandStrings(d.Get("tags")),
+		Tags:       expandTagsAll(d, meta),
 	}, scw.WithContext(ctx))
 	if err != nil {
 		return diag.FromErr(err)
@@ -117,7 +119,7 @@ func resourceScalewayInstancePlacementGroupRead(ctx context.Context, d *schema.R
 	_ = d.Set("policy_mode", res.PlacementGroup.PolicyMode.String())
 	_ = d.Set("policy_type", res.PlacementGroup.PolicyType.String())
 	_ = d.Set("policy_respected", res.PlacementGroup.PolicyRespected)
-	_ = d.Set("tags", res.PlacementGroup.Tags)
+	setTags(d, meta, res.PlacementGroup.Tags)
 
 	return nil
 }
@@ -152,8 +154,8 @@ func resourceScalewayInstancePlacementGroupUpdate(ctx context.Context, d *schema
 		hasChanged = true
 	}
 
-	if d.HasChange("tags") {
-		req.Tags = expandUpdatedStringsPtr(d.Get("tags"))
+	if d.HasChanges("tags", "tags_all") {
+		req.Tags = expandUpdatedTagsAll(d, meta)
 		hasChanged = true
 	}
 
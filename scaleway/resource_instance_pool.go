@@ -0,0 +1,312 @@
+package scaleway
+
+import (
+	"context"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/scaleway/scaleway-sdk-go/api/instance/v1"
+	"github.com/scaleway/scaleway-sdk-go/scw"
+)
+
+const defaultInstancePoolTimeout = 1 * time.Hour
+
+func resourceScalewayInstancePool() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceScalewayInstancePoolCreate,
+		ReadContext:   resourceScalewayInstancePoolRead,
+		UpdateContext: resourceScalewayInstancePoolUpdate,
+		DeleteContext: resourceScalewayInstancePoolDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create:  schema.DefaultTimeout(defaultInstancePoolTimeout),
+			Update:  schema.DefaultTimeout(defaultInstancePoolTimeout),
+			Delete:  schema.DefaultTimeout(defaultInstancePoolTimeout),
+			Default: schema.DefaultTimeout(defaultInstancePoolTimeout),
+		},
+		SchemaVersion: 0,
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "The name of the pool, used as the prefix of the names of its servers",
+			},
+			"template": {
+				Type:         schema.TypeString,
+				Required:     true,
+				Description:  "The content of the server template of the servers of the pool",
+				ValidateFunc: validation.StringIsJSON,
+			},
+			"size": {
+				Type:         schema.TypeInt,
+				Required:     true,
+				Description:  "The number of servers of the pool",
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"max_unavailable": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      1,
+				Description:  "The maximum number of servers that can be unavailable during a rolling replacement",
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"max_surge": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      0,
+				Description:  "The maximum number of servers that can be created above the size of the pool during a rolling replacement",
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"lb_backend_id": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Description:      "The load balancer backend the servers of the pool are added to",
+				ValidateFunc:     validationUUIDorUUIDWithLocality(),
+				DiffSuppressFunc: diffSuppressFuncLocality,
+			},
+			"enable_dynamic_ip": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Attach a dynamic public IP to the servers of the pool",
+			},
+			"tags": {
+				Type: schema.TypeList,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Optional:    true,
+				Description: "The tags of the pool, applied to its servers",
+			},
+			"tags_all": tagsAllSchema(),
+			"placement_group_id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The placement group spreading the servers of the pool",
+			},
+			"server_ids": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The IDs of the servers of the pool",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"server_ips": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The IPs the servers of the pool are reachable at from the load balancer",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"zone":       zoneSchema(),
+			"project_id": projectIDSchema(),
+		},
+		CustomizeDiff: customdiff.Sequence(
+			customizeDiffTagsAll,
+			customizeDiffInstancePool,
+		),
+	}
+}
+
+func resourceScalewayInstancePoolCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	instanceAPI, zone, err := instanceAPIWithZone(d, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	res, err := instanceAPI.CreatePlacementGroup(&instance.CreatePlacementGroupRequest{
+		Zone:       zone,
+		Name:       expandOrGenerateString(d.Get("name"), "pool"),
+		Project:    expandStringPtr(d.Get("project_id")),
+		PolicyMode: instance.PlacementGroupPolicyModeOptional,
+		PolicyType: instance.PlacementGroupPolicyTypeMaxAvailability,
+		Tags:       append(expandTagsAll(d, meta), instancePoolTag),
+	}, scw.WithContext(ctx))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(newZonedIDString(zone, res.PlacementGroup.ID))
+
+	pool, err := expandInstancePool(d, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	pool.name = res.PlacementGroup.Name
+
+	err = pool.rollout(ctx, d.Get("size").(int), d.Get("max_unavailable").(int), d.Get("max_surge").(int))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	return resourceScalewayInstancePoolRead(ctx, d, meta)
+}
+
+func resourceScalewayInstancePoolRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	instanceAPI, zone, id, err := instanceAPIWithZoneAndID(meta, d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	res, err := instanceAPI.GetPlacementGroup(&instance.GetPlacementGroupRequest{
+		Zone:             zone,
+		PlacementGroupID: id,
+	}, scw.WithContext(ctx))
+	if err != nil {
+		if is404Error(err) {
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(err)
+	}
+
+	servers, err := listInstancePoolServers(ctx, instanceAPI, zone, id)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	serverIDs := []string(nil)
+	serverIPs := []string(nil)
+	for _, server := range servers {
+		serverIDs = append(serverIDs, newZonedIDString(zone, server.ID))
+		ip, err := instancePoolServerIP(ctx, meta, zone, server)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		if ip != "" {
+			serverIPs = append(serverIPs, ip)
+		}
+	}
+
+	// The template is only kept when every server was created from it,
+	// otherwise it is reset so that the next apply replaces the outdated servers.
+	templateTag := instancePoolTemplateTag(d.Get("template").(string))
+	for _, server := range servers {
+		if instancePoolServerTemplateTag(server) != templateTag {
+			_ = d.Set("template", "")
+			break
+		}
+	}
+
+	_ = d.Set("name", res.PlacementGroup.Name)
+	_ = d.Set("zone", zone.String())
+	_ = d.Set("project_id", res.PlacementGroup.Project)
+	_ = d.Set("placement_group_id", newZonedIDString(zone, id))
+	_ = d.Set("size", len(servers))
+	_ = d.Set("server_ids", serverIDs)
+	_ = d.Set("server_ips", serverIPs)
+	tags := []string(nil)
+	for _, tag := range res.PlacementGroup.Tags {
+		if tag != instancePoolTag {
+			tags = append(tags, tag)
+		}
+	}
+	setTags(d, meta, tags)
+
+	return nil
+}
+
+func resourceScalewayInstancePoolUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	instanceAPI, zone, id, err := instanceAPIWithZoneAndID(meta, d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if d.HasChanges("name", "tags", "tags_all") {
+		_, err = instanceAPI.UpdatePlacementGroup(&instance.UpdatePlacementGroupRequest{
+			Zone:             zone,
+			PlacementGroupID: id,
+			Name:             expandStringPtr(d.Get("name")),
+			Tags:             scw.StringsPtr(append(expandTagsAll(d, meta), instancePoolTag)),
+		}, scw.WithContext(ctx))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	pool, err := expandInstancePool(d, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if d.HasChanges("tags", "tags_all", "enable_dynamic_ip", "lb_backend_id") {
+		oldBackendID, _ := d.GetChange("lb_backend_id")
+		servers, err := listInstancePoolServers(ctx, instanceAPI, zone, id)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		for _, server := range servers {
+			tags := append(append([]string(nil), pool.tags...), pool.serverTag(), instancePoolServerTemplateTag(server))
+			_, err = instanceAPI.UpdateServer(&instance.UpdateServerRequest{
+				Zone:              zone,
+				ServerID:          server.ID,
+				Tags:              &tags,
+				DynamicIPRequired: scw.BoolPtr(pool.enableDynamicIP),
+			}, scw.WithContext(ctx))
+			if err != nil {
+				return diag.FromErr(err)
+			}
+
+			if d.HasChange("lb_backend_id") {
+				err = pool.setBackendServer(ctx, oldBackendID.(string), server, false)
+				if err != nil {
+					return diag.FromErr(err)
+				}
+				err = pool.setBackendServer(ctx, pool.lbBackendID, server, true)
+				if err != nil {
+					return diag.FromErr(err)
+				}
+			}
+		}
+	}
+
+	err = pool.rollout(ctx, d.Get("size").(int), d.Get("max_unavailable").(int), d.Get("max_surge").(int))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	return resourceScalewayInstancePoolRead(ctx, d, meta)
+}
+
+func resourceScalewayInstancePoolDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	instanceAPI, zone, id, err := instanceAPIWithZoneAndID(meta, d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	pool := &instancePool{
+		api:         instanceAPI,
+		meta:        meta,
+		zone:        zone,
+		id:          id,
+		lbBackendID: d.Get("lb_backend_id").(string),
+	}
+	servers, err := listInstancePoolServers(ctx, instanceAPI, zone, id)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	for _, server := range servers {
+		err = pool.deleteServer(ctx, server)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	err = instanceAPI.DeletePlacementGroup(&instance.DeletePlacementGroupRequest{
+		Zone:             zone,
+		PlacementGroupID: id,
+	}, scw.WithContext(ctx))
+	if err != nil && !is404Error(err) {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
@@ -0,0 +1,205 @@
+package scaleway
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/scaleway/scaleway-sdk-go/api/instance/v1"
+	lbSDK "github.com/scaleway/scaleway-sdk-go/api/lb/v1"
+	"github.com/scaleway/scaleway-sdk-go/scw"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccScalewayInstancePool_Basic(t *testing.T) {
+	tt := NewTestTools(t)
+	defer tt.Cleanup()
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: tt.ProviderFactories,
+		CheckDestroy:      testAccCheckScalewayInstancePoolDestroy(tt),
+		Steps: []resource.TestStep{
+			{
+				Config: `
+					resource "scaleway_instance_server_template" "main" {
+						type  = "DEV1-S"
+						image = "ubuntu_jammy"
+					}
+
+					resource "scaleway_instance_pool" "main" {
+						name     = "tf-tests-instance-pool"
+						template = scaleway_instance_server_template.main.content
+						size     = 2
+						tags     = ["terraform-test", "scaleway_instance_pool", "basic"]
+					}
+				`,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckScalewayInstancePlacementGroupExists(tt, "scaleway_instance_pool.main"),
+					resource.TestCheckResourceAttr("scaleway_instance_pool.main", "server_ids.#", "2"),
+					resource.TestCheckResourceAttrPair("scaleway_instance_pool.main", "placement_group_id", "scaleway_instance_pool.main", "id"),
+				),
+			},
+			{
+				Config: `
+					resource "scaleway_instance_server_template" "main" {
+						type       = "DEV1-S"
+						image      = "ubuntu_jammy"
+						cloud_init = "#cloud-config"
+					}
+
+					resource "scaleway_instance_pool" "main" {
+						name      = "tf-tests-instance-pool"
+						template  = scaleway_instance_server_template.main.content
+						size      = 3
+						max_surge = 1
+						tags      = ["terraform-test", "scaleway_instance_pool", "basic"]
+					}
+				`,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckScalewayInstancePlacementGroupExists(tt, "scaleway_instance_pool.main"),
+					resource.TestCheckResourceAttr("scaleway_instance_pool.main", "server_ids.#", "3"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckScalewayInstancePoolDestroy(tt *TestTools) resource.TestCheckFunc {
+	return func(state *terraform.State) error {
+		for _, rs := range state.RootModule().Resources {
+			if rs.Type != "scaleway_instance_pool" {
+				continue
+			}
+
+			instanceAPI, zone, ID, err := instanceAPIWithZoneAndID(tt.Meta, rs.Primary.ID)
+			if err != nil {
+				return err
+			}
+
+			_, err = instanceAPI.GetPlacementGroup(&instance.GetPlacementGroupRequest{
+				Zone:             zone,
+				PlacementGroupID: ID,
+			})
+
+			// If no error resource still exist
+			if err == nil {
+				return fmt.Errorf("pool (%s) still exists", rs.Primary.ID)
+			}
+
+			// Unexpected api error we return it
+			if !is404Error(err) {
+				return err
+			}
+
+			servers, err := listInstancePoolServers(context.Background(), instanceAPI, zone, ID)
+			if err != nil {
+				return err
+			}
+			if len(servers) > 0 {
+				return fmt.Errorf("servers of pool (%s) still exist", rs.Primary.ID)
+			}
+		}
+		return nil
+	}
+}
+
+func TestFakeAPI_InstancePool(t *testing.T) {
+	tt := NewFakeAPITestTools(t)
+	defer tt.Cleanup()
+	ctx := context.Background()
+	zone := scw.ZoneFrPar1
+	instanceAPI := instance.NewAPI(tt.Meta.scwClient)
+	lbAPI := lbSDK.NewZonedAPI(tt.Meta.scwClient)
+
+	backend, err := lbAPI.CreateBackend(&lbSDK.ZonedAPICreateBackendRequest{Zone: zone, LBID: "22222222-2222-2222-2222-222222222222", Name: "web"})
+	require.NoError(t, err)
+	getBackendIPs := func() []string {
+		res, err := lbAPI.GetBackend(&lbSDK.ZonedAPIGetBackendRequest{Zone: zone, BackendID: backend.ID})
+		require.NoError(t, err)
+		return res.Pool
+	}
+
+	pool := resourceScalewayInstancePool()
+	config := map[string]cty.Value{
+		"name":              cty.StringVal("web"),
+		"template":          cty.StringVal(`{"image": "11111111-1111-1111-1111-111111111111", "type": "DEV1-S", "cloud_init": "#cloud-config"}`),
+		"size":              cty.NumberIntVal(3),
+		"max_unavailable":   cty.NumberIntVal(0),
+		"max_surge":         cty.NumberIntVal(1),
+		"lb_backend_id":     cty.StringVal(newZonedIDString(zone, backend.ID)),
+		"enable_dynamic_ip": cty.True,
+	}
+	diff, err := testPlanResource(t, pool, nil, tt.Meta, config)
+	require.NoError(t, err)
+	state, diags := pool.Apply(ctx, nil, diff, tt.Meta)
+	require.False(t, diags.HasError(), diags)
+	assert.Equal(t, "3", state.Attributes["server_ids.#"])
+	oldServerIDs := []string{state.Attributes["server_ids.0"], state.Attributes["server_ids.1"], state.Attributes["server_ids.2"]}
+	assert.ElementsMatch(t, []string{state.Attributes["server_ips.0"], state.Attributes["server_ips.1"], state.Attributes["server_ips.2"]}, getBackendIPs())
+
+	server, err := instanceAPI.GetServer(&instance.GetServerRequest{Zone: zone, ServerID: expandID(oldServerIDs[0])})
+	require.NoError(t, err)
+	assert.Equal(t, "DEV1-S", server.Server.CommercialType)
+	assert.Equal(t, instance.ServerStateRunning, server.Server.State)
+	assert.Equal(t, expandID(state.ID), server.Server.PlacementGroup.ID)
+	userData, err := instanceAPI.GetServerUserData(&instance.GetServerUserDataRequest{Zone: zone, ServerID: server.Server.ID, Key: "cloud-init"})
+	require.NoError(t, err)
+	content, err := io.ReadAll(userData)
+	require.NoError(t, err)
+	assert.Equal(t, "#cloud-config", string(content))
+
+	// A change of the template replaces every server.
+	config["template"] = cty.StringVal(`{"image": "33333333-3333-3333-3333-333333333333", "type": "DEV1-S", "cloud_init": "#cloud-config"}`)
+	diff, err = testPlanResource(t, pool, state, tt.Meta, config)
+	require.NoError(t, err)
+	assert.True(t, diff.Attributes["server_ids.#"].NewComputed)
+	state, diags = pool.Apply(ctx, state, diff, tt.Meta)
+	require.False(t, diags.HasError(), diags)
+	assert.Equal(t, "3", state.Attributes["server_ids.#"])
+	for _, id := range oldServerIDs {
+		_, err = instanceAPI.GetServer(&instance.GetServerRequest{Zone: zone, ServerID: expandID(id)})
+		assert.True(t, is404Error(err))
+		assert.NotContains(t, []string{state.Attributes["server_ids.0"], state.Attributes["server_ids.1"], state.Attributes["server_ids.2"]}, id)
+	}
+	assert.ElementsMatch(t, []string{state.Attributes["server_ips.0"], state.Attributes["server_ips.1"], state.Attributes["server_ips.2"]}, getBackendIPs())
+	server, err = instanceAPI.GetServer(&instance.GetServerRequest{Zone: zone, ServerID: expandID(state.Attributes["server_ids.0"])})
+	require.NoError(t, err)
+	assert.Equal(t, "33333333-3333-3333-3333-333333333333", server.Server.Image.ID)
+
+	// A server created from another template, e.g. by an interrupted rollout, is replaced by the next apply.
+	_, err = instanceAPI.UpdateServer(&instance.UpdateServerRequest{
+		Zone:     zone,
+		ServerID: expandID(state.Attributes["server_ids.2"]),
+		Tags:     scw.StringsPtr([]string{instancePoolServerTagPrefix + expandID(state.ID), instancePoolTemplateTag("{}")}),
+	})
+	require.NoError(t, err)
+	refreshedState, diags := pool.RefreshWithoutUpgrade(ctx, state, tt.Meta)
+	require.False(t, diags.HasError(), diags)
+	assert.Equal(t, "", refreshedState.Attributes["template"])
+
+	// Scaling down deletes the newest servers.
+	config["size"] = cty.NumberIntVal(1)
+	diff, err = testPlanResource(t, pool, state, tt.Meta, config)
+	require.NoError(t, err)
+	state, diags = pool.Apply(ctx, state, diff, tt.Meta)
+	require.False(t, diags.HasError(), diags)
+	assert.Equal(t, "1", state.Attributes["server_ids.#"])
+	assert.Equal(t, server.Server.ID, expandID(state.Attributes["server_ids.0"]))
+	assert.Equal(t, []string{state.Attributes["server_ips.0"]}, getBackendIPs())
+
+	// Both limits cannot be 0.
+	config["max_surge"] = cty.NumberIntVal(0)
+	_, err = testPlanResource(t, pool, state, tt.Meta, config)
+	assert.ErrorContains(t, err, "max_unavailable and max_surge cannot both be 0")
+
+	diags = resourceScalewayInstancePoolDelete(ctx, pool.Data(state), tt.Meta)
+	require.False(t, diags.HasError(), diags)
+	_, err = instanceAPI.GetPlacementGroup(&instance.GetPlacementGroupRequest{Zone: zone, PlacementGroupID: expandID(state.ID)})
+	assert.True(t, is404Error(err))
+	assert.Empty(t, getBackendIPs())
+}
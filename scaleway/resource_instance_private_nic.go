@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/scaleway/scaleway-sdk-go/api/instance/v1"
 	"github.com/scaleway/scaleway-sdk-go/scw"
@@ -49,9 +50,13 @@ func resourceScalewayInstancePrivateNIC() *schema.Resource {
 				Optional:    true,
 				Description: "The tags associated with the private-nic",
 			},
-			"zone": zoneSchema(),
+			"tags_all": tagsAllSchema(),
+			"zone":     zoneSchema(),
 		},
-		CustomizeDiff: customizeDiffLocalityCheck("server_id", "private_network_id"),
+		CustomizeDiff: customdiff.Sequence(
+			customizeDiffLocalityCheck("server_id", "private_network_id"),
+			customizeDiffTagsAll,
+		),
 	}
 }
 
@@ -70,7 +75,7 @@ func resourceScalewayInstancePrivateNICCreate(ctx context.Context, d *schema.Res
 		Zone:             zone,
 		ServerID:         expandZonedID(d.Get("server_id").(string)).ID,
 		PrivateNetworkID: expandZonedID(d.Get("private_network_id").(string)).ID,
-		Tags:             expandStrings(d.Get("tags")),
+		Tags:             expandTagsAll(d, meta),
 	}
 
 	privateNIC, err := instanceAPI.CreatePrivateNIC(
@@ -122,7 +127,7 @@ func resourceScalewayInstancePrivateNICRead(ctx context.Context, d *schema.Resou
 	_ = d.Set("mac_address", privateNIC.MacAddress)
 
 	if len(privateNIC.Tags) > 0 {
-		_ = d.Set("tags", privateNIC.Tags)
+		setTags(d, meta, privateNIC.Tags)
 	}
 
 	return nil
@@ -166,7 +171,7 @@ func resourceScalewayInstancePrivateNICUpdate(ctx context.Context, d *schema.Res
 			Zone:             zone,
 			ServerID:         expandZonedID(d.Get("server_id").(string)).ID,
 			PrivateNetworkID: expandZonedID(d.Get("private_network_id").(string)).ID,
-			Tags:             expandStrings(d.Get("tags")),
+			Tags:             expandTagsAll(d, meta),
 		}
 
 		privateNIC, err := instanceAPI.CreatePrivateNIC(
@@ -189,13 +194,13 @@ func resourceScalewayInstancePrivateNICUpdate(ctx context.Context, d *schema.Res
 				privateNIC.PrivateNic.ID,
 			),
 		)
-	} else if d.HasChange("tags") {
+	} else if d.HasChanges("tags", "tags_all") {
 		_, err := instanceAPI.UpdatePrivateNIC(
 			&instance.UpdatePrivateNICRequest{
 				Zone:         zone,
 				ServerID:     serverID,
 				PrivateNicID: privateNICID,
-				Tags:         expandUpdatedStringsPtr(d.Get("tags")),
+				Tags:         expandUpdatedTagsAll(d, meta),
 			},
 			scw.WithContext(ctx),
 		)
@@ -19,7 +19,7 @@ func resourceScalewayInstanceSecurityGroup() *schema.Resource {
 		UpdateContext: resourceScalewayInstanceSecurityGroupUpdate,
 		DeleteContext: resourceScalewayInstanceSecurityGroupDelete,
 		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
+			StateContext: importStateByName(instanceSecurityGroupIDByName),
 		},
 		Timeouts: &schema.ResourceTimeout{
 			Default: schema.DefaultTimeout(defaultInstanceSecurityGroupTimeout),
@@ -96,10 +96,12 @@ func resourceScalewayInstanceSecurityGroup() *schema.Resource {
 				Optional:    true,
 				Description: "The tags associated with the security group",
 			},
+			"tags_all":        tagsAllSchema(),
 			"zone":            zoneSchema(),
 			"organization_id": organizationIDSchema(),
 			"project_id":      projectIDSchema(),
 		},
+		CustomizeDiff: customizeDiffTagsAll,
 	}
 }
 
@@ -119,7 +121,7 @@ func resourceScalewayInstanceSecurityGroupCreate(ctx context.Context, d *schema.
 		OutboundDefaultPolicy: instance.SecurityGroupPolicy(d.Get("outbound_default_policy").(string)),
 		EnableDefaultSecurity: expandBoolPtr(d.Get("enable_default_security")),
 	}
-	tags := expandStrings(d.Get("tags"))
+	tags := expandInstanceSecurityGroupTags(d, meta)
 	if len(tags) > 0 {
 		req.Tags = tags
 	}
@@ -164,9 +166,14 @@ func resourceScalewayInstanceSecurityGroupRead(ctx context.Context, d *schema.Re
 	_ = d.Set("inbound_default_policy", res.SecurityGroup.InboundDefaultPolicy.String())
 	_ = d.Set("outbound_default_policy", res.SecurityGroup.OutboundDefaultPolicy.String())
 	_ = d.Set("enable_default_security", res.SecurityGroup.EnableDefaultSecurity)
-	_ = d.Set("tags", res.SecurityGroup.Tags)
-
-	if !d.Get("external_rules").(bool) {
+	wasExternalRules := d.Get("external_rules").(bool)
+	tags, externalRules := flattenInstanceSecurityGroupTags(res.SecurityGroup.Tags)
+	setTags(d, meta, tags)
+	// Groups with external_rules set before the mark existed are not marked: reading external_rules as false
+	// makes the next apply mark them, and their rules are still not read as they are managed by other resources.
+	_ = d.Set("external_rules", externalRules)
+
+	if !externalRules && !wasExternalRules {
 		inboundRules, outboundRules, err := getSecurityGroupRules(ctx, instanceAPI, zone, ID, d)
 		if err != nil {
 			return diag.FromErr(err)
@@ -270,9 +277,9 @@ func resourceScalewayInstanceSecurityGroupUpdate(ctx context.Context, d *schema.
 		Tags:                  scw.StringsPtr([]string{}),
 	}
 
-	tags := expandStrings(d.Get("tags"))
+	tags := expandInstanceSecurityGroupTags(d, meta)
 	if len(tags) > 0 {
-		updateReq.Tags = scw.StringsPtr(expandStrings(d.Get("tags")))
+		updateReq.Tags = scw.StringsPtr(tags)
 	}
 
 	if d.HasChange("enable_default_security") {
@@ -362,6 +369,29 @@ func resourceScalewayInstanceSecurityGroupDelete(ctx context.Context, d *schema.
 	return nil
 }
 
+// expandInstanceSecurityGroupTags returns the tags of a security group, marking the groups whose rules are managed by other resources.
+func expandInstanceSecurityGroupTags(d *schema.ResourceData, meta interface{}) []string {
+	tags := expandTagsAll(d, meta)
+	if d.Get("external_rules").(bool) {
+		tags = append(tags, instanceSecurityGroupExternalRulesTag)
+	}
+	return tags
+}
+
+// flattenInstanceSecurityGroupTags returns the tags of a security group without its external rules mark, and whether it is marked.
+func flattenInstanceSecurityGroupTags(tags []string) ([]string, bool) {
+	externalRules := false
+	flattenedTags := []string(nil)
+	for _, tag := range tags {
+		if tag == instanceSecurityGroupExternalRulesTag {
+			externalRules = true
+			continue
+		}
+		flattenedTags = append(flattenedTags, tag)
+	}
+	return flattenedTags, externalRules
+}
+
 // securityGroupRuleSchema returns schema for inbound/outbound rule in security group
 func securityGroupRuleSchema() *schema.Resource {
 	return &schema.Resource{
@@ -0,0 +1,243 @@
+package scaleway
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/scaleway/scaleway-sdk-go/api/instance/v1"
+	"github.com/scaleway/scaleway-sdk-go/scw"
+)
+
+func resourceScalewayInstanceSecurityGroupRule() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceScalewayInstanceSecurityGroupRuleCreate,
+		ReadContext:   resourceScalewayInstanceSecurityGroupRuleRead,
+		DeleteContext: resourceScalewayInstanceSecurityGroupRuleDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Default: schema.DefaultTimeout(defaultInstanceSecurityGroupRuleTimeout),
+		},
+		Schema: map[string]*schema.Schema{
+			"security_group_id": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "The security group of the rule, which must have external_rules set to true",
+				ValidateFunc: validationUUIDWithLocality(),
+			},
+			"direction": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+				ValidateFunc: validation.StringInSlice([]string{
+					instance.SecurityGroupRuleDirectionInbound.String(),
+					instance.SecurityGroupRuleDirectionOutbound.String(),
+				}, false),
+				Description: "Direction of the traffic matched by the rule (inbound or outbound)",
+			},
+			"action": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+				ValidateFunc: validation.StringInSlice([]string{
+					instance.SecurityGroupRuleActionAccept.String(),
+					instance.SecurityGroupRuleActionDrop.String(),
+				}, false),
+				Description: "Action when rule match request (drop or accept)",
+			},
+			"protocol": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+				Default:  instance.SecurityGroupRuleProtocolTCP.String(),
+				ValidateFunc: validation.StringInSlice([]string{
+					instance.SecurityGroupRuleProtocolICMP.String(),
+					instance.SecurityGroupRuleProtocolTCP.String(),
+					instance.SecurityGroupRuleProtocolUDP.String(),
+					instance.SecurityGroupRuleProtocolANY.String(),
+				}, false),
+				Description: "Protocol for this rule (TCP, UDP, ICMP or ANY)",
+			},
+			"port_range": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringMatch(regexp.MustCompile(`^\d+(-\d+)?$`), "must be a port or a port range, e.g. 22 or 1-1024"),
+				DiffSuppressFunc: func(_, oldValue, newValue string, _ *schema.ResourceData) bool {
+					return oldValue == newValue+"-"+newValue
+				},
+				Description: "Port or port range for this rule (e.g: 22, 1-1024), all ports when not set",
+			},
+			"ip_range": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				Default:      "0.0.0.0/0",
+				ValidateFunc: validation.IsCIDRNetwork(0, 128),
+				Description:  "Ip range for this rule (e.g: 192.168.1.0/24)",
+			},
+			"position": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The position of the rule in the security group",
+			},
+		},
+	}
+}
+
+// expandInstanceSecurityGroupRule returns the rule of a scaleway_instance_security_group_rule.
+func expandInstanceSecurityGroupRule(d *schema.ResourceData) (*instance.SecurityGroupRule, error) {
+	portRange := d.Get("port_range").(string)
+	if portRange != "" && !strings.Contains(portRange, "-") {
+		portRange = portRange + "-" + portRange
+	}
+	return securityGroupRuleExpand(map[string]interface{}{
+		"action":     d.Get("action"),
+		"protocol":   d.Get("protocol"),
+		"port_range": portRange,
+		"port":       0,
+		"ip":         "",
+		"ip_range":   d.Get("ip_range"),
+	})
+}
+
+func resourceScalewayInstanceSecurityGroupRuleCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	instanceAPI, zone, securityGroupID, err := instanceAPIWithZoneAndID(meta, d.Get("security_group_id").(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	err = checkInstanceSecurityGroupExternalRules(ctx, instanceAPI, zone, securityGroupID)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	direction := instance.SecurityGroupRuleDirection(d.Get("direction").(string))
+	rule, err := expandInstanceSecurityGroupRule(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	identity, err := instanceSecurityGroupRuleIdentity(direction, rule)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	id := fmt.Sprintf("%s/%s/%s", zone, securityGroupID, identity)
+
+	existingRule, err := findInstanceSecurityGroupRule(ctx, instanceAPI, zone, securityGroupID, identity)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if existingRule != nil {
+		return diag.Errorf("security group rule %s already exists, it can be imported with this ID", id)
+	}
+
+	_, err = instanceAPI.CreateSecurityGroupRule(&instance.CreateSecurityGroupRuleRequest{
+		Zone:            zone,
+		SecurityGroupID: securityGroupID,
+		Direction:       direction,
+		Action:          rule.Action,
+		Protocol:        rule.Protocol,
+		IPRange:         rule.IPRange,
+		DestPortFrom:    rule.DestPortFrom,
+		DestPortTo:      rule.DestPortTo,
+		Editable:        true,
+	}, scw.WithContext(ctx))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(id)
+
+	return resourceScalewayInstanceSecurityGroupRuleRead(ctx, d, meta)
+}
+
+func resourceScalewayInstanceSecurityGroupRuleRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	zone, securityGroupID, direction, rule, err := parseInstanceSecurityGroupRuleID(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	instanceAPI, _, _, err := instanceAPIWithZoneAndID(meta, newZonedIDString(zone, securityGroupID))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	identity, err := instanceSecurityGroupRuleIdentity(direction, rule)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	apiRule, err := findInstanceSecurityGroupRule(ctx, instanceAPI, zone, securityGroupID, identity)
+	if err != nil {
+		if is404Error(err) {
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(err)
+	}
+	if apiRule == nil {
+		d.SetId("")
+		return nil
+	}
+
+	rawRule, err := securityGroupRuleFlatten(apiRule)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	_ = d.Set("security_group_id", newZonedIDString(zone, securityGroupID))
+	_ = d.Set("direction", direction.String())
+	_ = d.Set("action", rawRule["action"])
+	_ = d.Set("protocol", rawRule["protocol"])
+	_ = d.Set("ip_range", rawRule["ip_range"])
+	_ = d.Set("position", int(apiRule.Position))
+	if apiRule.DestPortFrom != nil {
+		_ = d.Set("port_range", instanceSecurityGroupRulePortRange(apiRule))
+	} else {
+		_ = d.Set("port_range", "")
+	}
+
+	return nil
+}
+
+func resourceScalewayInstanceSecurityGroupRuleDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	zone, securityGroupID, direction, rule, err := parseInstanceSecurityGroupRuleID(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	instanceAPI, _, _, err := instanceAPIWithZoneAndID(meta, newZonedIDString(zone, securityGroupID))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	identity, err := instanceSecurityGroupRuleIdentity(direction, rule)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	apiRule, err := findInstanceSecurityGroupRule(ctx, instanceAPI, zone, securityGroupID, identity)
+	if err != nil {
+		if is404Error(err) {
+			return nil
+		}
+		return diag.FromErr(err)
+	}
+	if apiRule == nil {
+		return nil
+	}
+
+	err = instanceAPI.DeleteSecurityGroupRule(&instance.DeleteSecurityGroupRuleRequest{
+		Zone:                zone,
+		SecurityGroupID:     securityGroupID,
+		SecurityGroupRuleID: apiRule.ID,
+	}, scw.WithContext(ctx))
+	if err != nil && !is404Error(err) {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
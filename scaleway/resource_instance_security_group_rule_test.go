@@ -0,0 +1,211 @@
+package scaleway
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/scaleway/scaleway-sdk-go/api/instance/v1"
+	"github.com/scaleway/scaleway-sdk-go/scw"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccScalewayInstanceSecurityGroupRule_Basic(t *testing.T) {
+	tt := NewTestTools(t)
+	defer tt.Cleanup()
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: tt.ProviderFactories,
+		CheckDestroy:      testAccCheckScalewayInstanceSecurityGroupDestroy(tt),
+		Steps: []resource.TestStep{
+			{
+				Config: `
+					resource "scaleway_instance_security_group" "main" {
+						inbound_default_policy = "drop"
+						external_rules         = true
+						tags                   = ["terraform-test", "scaleway_instance_security_group_rule", "basic"]
+					}
+
+					resource "scaleway_instance_security_group_rule" "https" {
+						security_group_id = scaleway_instance_security_group.main.id
+						direction         = "inbound"
+						action            = "accept"
+						port_range        = "443"
+					}
+
+					resource "scaleway_instance_security_group_rule" "ssh" {
+						security_group_id = scaleway_instance_security_group.main.id
+						direction         = "inbound"
+						action            = "accept"
+						port_range        = "22"
+						ip_range          = "192.168.1.0/24"
+					}
+				`,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckScalewayInstanceSecurityGroupExists(tt, "scaleway_instance_security_group.main"),
+					resource.TestCheckResourceAttr("scaleway_instance_security_group.main", "external_rules", "true"),
+					resource.TestCheckResourceAttr("scaleway_instance_security_group.main", "tags.#", "3"),
+					resource.TestCheckResourceAttr("scaleway_instance_security_group_rule.https", "protocol", "TCP"),
+					resource.TestCheckResourceAttr("scaleway_instance_security_group_rule.https", "ip_range", "0.0.0.0/0"),
+					resource.TestCheckResourceAttrSet("scaleway_instance_security_group_rule.https", "position"),
+					resource.TestCheckResourceAttr("scaleway_instance_security_group_rule.ssh", "port_range", "22"),
+				),
+			},
+			{
+				ResourceName:      "scaleway_instance_security_group_rule.ssh",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				Config: `
+					resource "scaleway_instance_security_group" "main" {
+						inbound_default_policy = "drop"
+						external_rules         = true
+						tags                   = ["terraform-test", "scaleway_instance_security_group_rule", "basic"]
+					}
+
+					resource "scaleway_instance_security_group_rule" "https" {
+						security_group_id = scaleway_instance_security_group.main.id
+						direction         = "inbound"
+						action            = "accept"
+						port_range        = "443"
+					}
+				`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("scaleway_instance_security_group_rule.https", "port_range", "443"),
+				),
+			},
+		},
+	})
+}
+
+func TestFakeAPI_InstanceSecurityGroupRule(t *testing.T) {
+	tt := NewFakeAPITestTools(t)
+	defer tt.Cleanup()
+	ctx := context.Background()
+
+	newSecurityGroup := func(externalRules bool) *schema.ResourceData {
+		d := schema.TestResourceDataRaw(t, resourceScalewayInstanceSecurityGroup().Schema, map[string]interface{}{
+			"external_rules": externalRules,
+		})
+		diags := resourceScalewayInstanceSecurityGroupCreate(ctx, d, tt.Meta)
+		require.False(t, diags.HasError(), diags)
+		return d
+	}
+	newRule := func(raw map[string]interface{}) (*schema.ResourceData, diag.Diagnostics) {
+		d := schema.TestResourceDataRaw(t, resourceScalewayInstanceSecurityGroupRule().Schema, raw)
+		return d, resourceScalewayInstanceSecurityGroupRuleCreate(ctx, d, tt.Meta)
+	}
+
+	// The rules of a security group without external_rules are managed by the security group.
+	managedGroup := newSecurityGroup(false)
+	_, diags := newRule(map[string]interface{}{
+		"security_group_id": managedGroup.Id(),
+		"direction":         "inbound",
+		"action":            "accept",
+	})
+	require.True(t, diags.HasError())
+	assert.Contains(t, diags[0].Summary, "is not marked with external_rules")
+
+	group := newSecurityGroup(true)
+	assert.True(t, group.Get("external_rules").(bool))
+	assert.Empty(t, group.Get("tags"))
+
+	ssh, diags := newRule(map[string]interface{}{
+		"security_group_id": group.Id(),
+		"direction":         "inbound",
+		"action":            "accept",
+		"port_range":        "22",
+	})
+	require.False(t, diags.HasError(), diags)
+	assert.Equal(t, group.Id()+"/inbound,accept,TCP,22-22,0.0.0.0/0", ssh.Id())
+	assert.Equal(t, 1, ssh.Get("position"))
+
+	drop, diags := newRule(map[string]interface{}{
+		"security_group_id": group.Id(),
+		"direction":         "outbound",
+		"action":            "drop",
+		"protocol":          "ANY",
+		"ip_range":          "10.0.0.0/8",
+	})
+	require.False(t, diags.HasError(), diags)
+	assert.Equal(t, "", drop.Get("port_range"))
+	assert.Equal(t, 2, drop.Get("position"))
+
+	// A rule is identified by its content, an identical rule cannot be created twice.
+	_, diags = newRule(map[string]interface{}{
+		"security_group_id": group.Id(),
+		"direction":         "inbound",
+		"action":            "accept",
+		"port_range":        "22-22",
+	})
+	require.True(t, diags.HasError())
+	assert.Contains(t, diags[0].Summary, "already exists")
+
+	imported := resourceScalewayInstanceSecurityGroupRule().Data(nil)
+	imported.SetId(drop.Id())
+	diags = resourceScalewayInstanceSecurityGroupRuleRead(ctx, imported, tt.Meta)
+	require.False(t, diags.HasError(), diags)
+	assert.Equal(t, group.Id(), imported.Get("security_group_id"))
+	assert.Equal(t, "outbound", imported.Get("direction"))
+	assert.Equal(t, "drop", imported.Get("action"))
+	assert.Equal(t, "ANY", imported.Get("protocol"))
+	assert.Equal(t, "10.0.0.0/8", imported.Get("ip_range"))
+
+	diags = resourceScalewayInstanceSecurityGroupRuleDelete(ctx, ssh, tt.Meta)
+	require.False(t, diags.HasError(), diags)
+	diags = resourceScalewayInstanceSecurityGroupRuleRead(ctx, ssh, tt.Meta)
+	require.False(t, diags.HasError(), diags)
+	assert.Equal(t, "", ssh.Id())
+	diags = resourceScalewayInstanceSecurityGroupRuleRead(ctx, drop, tt.Meta)
+	require.False(t, diags.HasError(), diags)
+	assert.NotEqual(t, "", drop.Id())
+
+	_, _, _, _, err := parseInstanceSecurityGroupRuleID(group.Id() + "/inbound,accept")
+	assert.ErrorContains(t, err, "invalid security group rule")
+
+	// Groups with external_rules set before they were marked must be re-applied to be marked.
+	securityGroup := resourceScalewayInstanceSecurityGroup()
+	config := map[string]cty.Value{"external_rules": cty.True}
+	diff, err := testPlanResource(t, securityGroup, nil, tt.Meta, config)
+	require.NoError(t, err)
+	state, diags := securityGroup.Apply(ctx, nil, diff, tt.Meta)
+	require.False(t, diags.HasError(), diags)
+	instanceAPI, zone, id, err := instanceAPIWithZoneAndID(tt.Meta, state.ID)
+	require.NoError(t, err)
+	_, err = instanceAPI.UpdateSecurityGroup(&instance.UpdateSecurityGroupRequest{
+		Zone:            zone,
+		SecurityGroupID: id,
+		Tags:            scw.StringsPtr([]string{}),
+	})
+	require.NoError(t, err)
+
+	_, diags = newRule(map[string]interface{}{
+		"security_group_id": state.ID,
+		"direction":         "inbound",
+		"action":            "accept",
+	})
+	require.True(t, diags.HasError())
+	assert.Contains(t, diags[0].Summary, "re-apply the security group")
+
+	state, diags = securityGroup.RefreshWithoutUpgrade(ctx, state, tt.Meta)
+	require.False(t, diags.HasError(), diags)
+	assert.Equal(t, "false", state.Attributes["external_rules"])
+	assert.Empty(t, state.Attributes["inbound_rule.#"])
+	diff, err = testPlanResource(t, securityGroup, state, tt.Meta, config)
+	require.NoError(t, err)
+	assert.Equal(t, "true", diff.Attributes["external_rules"].New)
+	_, diags = securityGroup.Apply(ctx, state, diff, tt.Meta)
+	require.False(t, diags.HasError(), diags)
+
+	_, diags = newRule(map[string]interface{}{
+		"security_group_id": state.ID,
+		"direction":         "inbound",
+		"action":            "accept",
+	})
+	require.False(t, diags.HasError(), diags)
+}
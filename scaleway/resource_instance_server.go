@@ -11,12 +11,13 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/scaleway/scaleway-sdk-go/api/instance/v1"
-	"github.com/scaleway/scaleway-sdk-go/api/marketplace/v2"
 	"github.com/scaleway/scaleway-sdk-go/scw"
 	scwvalidation "github.com/scaleway/scaleway-sdk-go/validation"
+	"golang.org/x/exp/slices"
 )
 
 func resourceScalewayInstanceServer() *schema.Resource {
@@ -26,7 +27,7 @@ func resourceScalewayInstanceServer() *schema.Resource {
 		UpdateContext: resourceScalewayInstanceServerUpdate,
 		DeleteContext: resourceScalewayInstanceServerDelete,
 		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
+			StateContext: importStateByName(instanceServerIDByName),
 		},
 		Timeouts: &schema.ResourceTimeout{
 			Create:  schema.DefaultTimeout(defaultInstanceServerWaitTimeout),
@@ -46,17 +47,50 @@ func resourceScalewayInstanceServer() *schema.Resource {
 			"image": {
 				Type:             schema.TypeString,
 				Optional:         true,
+				Computed:         true,
 				ForceNew:         true,
 				Description:      "The UUID or the label of the base image used by the server",
 				DiffSuppressFunc: diffSuppressFuncLocality,
-				ExactlyOneOf:     []string{"image", "root_volume.0.volume_id"},
+				ConflictsWith:    []string{"root_volume.0.volume_id"},
+				AtLeastOneOf:     []string{"image", "root_volume.0.volume_id", "template"},
 			},
 			"type": {
 				Type:             schema.TypeString,
-				Required:         true,
-				ForceNew:         true,
+				Optional:         true,
+				Computed:         true,
 				Description:      "The instance type of the server", // TODO: link to scaleway pricing in the doc
 				DiffSuppressFunc: diffSuppressFuncIgnoreCase,
+				AtLeastOneOf:     []string{"type", "template"},
+			},
+			"template": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  "The content of the server template providing the arguments that are not set",
+				ValidateFunc: validation.StringIsJSON,
+			},
+			"template_fields": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The arguments taken from the server template",
+			},
+			"replace_on_type_change": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Replace the server when its type changes, set to false to stop the server and change its type in place",
+			},
+			"final_snapshot": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Snapshot the volumes of the server before destroying it",
+			},
+			"final_snapshot_ids": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The snapshots of the volumes taken when the server was destroyed",
 			},
 			"tags": {
 				Type: schema.TypeList,
@@ -66,6 +100,7 @@ func resourceScalewayInstanceServer() *schema.Resource {
 				Optional:    true,
 				Description: "The tags associated with the server",
 			},
+			"tags_all": tagsAllSchema(),
 			"security_group_id": {
 				Type:             schema.TypeString,
 				Optional:         true,
@@ -76,6 +111,7 @@ func resourceScalewayInstanceServer() *schema.Resource {
 			"placement_group_id": {
 				Type:             schema.TypeString,
 				Optional:         true,
+				Computed:         true,
 				DiffSuppressFunc: diffSuppressFuncLocality,
 				Description:      "The placement group the server is attached to",
 			},
@@ -101,8 +137,7 @@ func resourceScalewayInstanceServer() *schema.Resource {
 							Type:        schema.TypeInt,
 							Optional:    true,
 							Computed:    true,
-							ForceNew:    true,
-							Description: "Size of the root volume in gigabytes",
+							Description: "Size of the root volume in gigabytes, block volumes can be grown without replacing the server",
 						},
 						"volume_type": {
 							Type:        schema.TypeString,
@@ -128,11 +163,11 @@ func resourceScalewayInstanceServer() *schema.Resource {
 							Description: "Set the volume where the boot the server",
 						},
 						"volume_id": {
-							Type:         schema.TypeString,
-							Computed:     true,
-							Optional:     true,
-							Description:  "Volume ID of the root volume",
-							ExactlyOneOf: []string{"image", "root_volume.0.volume_id"},
+							Type:          schema.TypeString,
+							Computed:      true,
+							Optional:      true,
+							Description:   "Volume ID of the root volume",
+							ConflictsWith: []string{"image"},
 						},
 					},
 				},
@@ -168,6 +203,42 @@ func resourceScalewayInstanceServer() *schema.Resource {
 				Optional:         true,
 				Description:      "The ID of the reserved IP for the server",
 				DiffSuppressFunc: diffSuppressFuncLocality,
+				ConflictsWith:    []string{"ip_ids"},
+			},
+			"ip_ids": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type:             schema.TypeString,
+					ValidateFunc:     validationUUIDorUUIDWithLocality(),
+					DiffSuppressFunc: diffSuppressFuncLocality,
+				},
+				Description:   "The IDs of the reserved routed IPs attached to the server",
+				ConflictsWith: []string{"ip_id"},
+			},
+			"public_ips": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The IPs attached to the server",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The ID of the IP",
+						},
+						"address": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The address of the IP",
+						},
+						"family": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The family of the IP (inet or inet6)",
+						},
+					},
+				},
 			},
 			"ipv6_address": {
 				Type:        schema.TypeString,
@@ -201,6 +272,26 @@ func resourceScalewayInstanceServer() *schema.Resource {
 					InstanceServerStateStandby,
 				}, false),
 			},
+			"stop_action": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     InstanceServerStopActionPoweroff,
+				Description: "How the server is stopped: poweroff, graceful or terminate",
+				ValidateFunc: validation.StringInSlice([]string{
+					InstanceServerStopActionPoweroff,
+					InstanceServerStopActionGraceful,
+					InstanceServerStopActionTerminate,
+				}, false),
+			},
+			"shutdown_timeout": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				DiffSuppressFunc: diffSuppressFuncDuration,
+				ValidateFunc:     validateDuration(),
+				Description:      "Maximum time to wait for the server to stop",
+			},
+			"pre_stop":   instanceServerWaitConditionSchema("Condition to wait for before stopping the server"),
+			"post_start": instanceServerWaitConditionSchema("Condition to wait for after starting the server"),
 			"boot_type": {
 				Type:        schema.TypeString,
 				Optional:    true,
@@ -238,6 +329,7 @@ func resourceScalewayInstanceServer() *schema.Resource {
 			"private_network": {
 				Type:        schema.TypeList,
 				Optional:    true,
+				Computed:    true,
 				MaxItems:    8,
 				Description: "List of private network to connect with your instance",
 				Elem: &schema.Resource{
@@ -270,10 +362,18 @@ func resourceScalewayInstanceServer() *schema.Resource {
 			"organization_id": organizationIDSchema(),
 			"project_id":      projectIDSchema(),
 		},
-		CustomizeDiff: customizeDiffLocalityCheck(
-			"placement_group_id",
-			"additional_volume_ids.#",
-			"ip_id",
+		CustomizeDiff: customdiff.Sequence(
+			customizeDiffLocalityCheck(
+				"placement_group_id",
+				"additional_volume_ids.#",
+				"ip_id",
+				"ip_ids.#",
+			),
+			customizeDiffTagsAll,
+			// The template must be applied first, for the other checks to see the arguments it sets.
+			customizeDiffInstanceServerTemplate,
+			customizeDiffInstanceServerType,
+			customizeDiffInstanceServerRootVolumeSize,
 		),
 	}
 }
@@ -285,27 +385,20 @@ func resourceScalewayInstanceServerCreate(ctx context.Context, d *schema.Resourc
 		return diag.FromErr(err)
 	}
 
+	template, err := expandInstanceServerTemplateContent(d.Get("template").(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
 	////
 	// Create the server
 	////
 
 	commercialType := d.Get("type").(string)
 
-	imageUUID := expandID(d.Get("image"))
-	if imageUUID != "" && !scwvalidation.IsUUID(imageUUID) {
-		// Replace dashes with underscores ubuntu-focal -> ubuntu_focal
-		imageLabel := formatImageLabel(imageUUID)
-
-		marketPlaceAPI := marketplace.NewAPI(meta.(*Meta).scwClient)
-		image, err := marketPlaceAPI.GetLocalImageByLabel(&marketplace.GetLocalImageByLabelRequest{
-			CommercialType: commercialType,
-			Zone:           zone,
-			ImageLabel:     imageLabel,
-		})
-		if err != nil {
-			return diag.FromErr(fmt.Errorf("could not get image '%s': %s", newZonedID(zone, imageLabel), err))
-		}
-		imageUUID = image.ID
+	imageUUID, err := instanceImageUUID(ctx, meta, zone, commercialType, d.Get("image").(string))
+	if err != nil {
+		return diag.FromErr(err)
 	}
 
 	req := &instance.CreateServerRequest{
@@ -316,7 +409,7 @@ func resourceScalewayInstanceServerCreate(ctx context.Context, d *schema.Resourc
 		CommercialType:    commercialType,
 		SecurityGroup:     expandStringPtr(expandZonedID(d.Get("security_group_id")).ID),
 		DynamicIPRequired: scw.BoolPtr(d.Get("enable_dynamic_ip").(bool)),
-		Tags:              expandStrings(d.Get("tags")),
+		Tags:              expandTagsAll(d, meta),
 	}
 
 	enableIPv6, ok := d.GetOk("enable_ipv6")
@@ -350,7 +443,13 @@ func resourceScalewayInstanceServerCreate(ctx context.Context, d *schema.Resourc
 	serverTypeCanBootOnBlock := serverType.VolumesConstraint.MaxSize == 0
 	rootVolumeIsBootVolume := expandBoolPtr(d.Get("root_volume.0.boot"))
 	rootVolumeType := d.Get("root_volume.0.volume_type").(string)
+	if rootVolumeType == "" {
+		rootVolumeType = template.RootVolumeType
+	}
 	sizeInput := d.Get("root_volume.0.size_in_gb").(int)
+	if sizeInput == 0 {
+		sizeInput = template.RootVolumeSizeInGB
+	}
 	rootVolumeID := expandZonedID(d.Get("root_volume.0.volume_id").(string)).ID
 
 	// If the rootVolumeType is not defined, define it depending on the offer
@@ -378,11 +477,11 @@ func resourceScalewayInstanceServerCreate(ctx context.Context, d *schema.Resourc
 	}
 
 	req.Volumes["0"] = &instance.VolumeServerTemplate{
-		Name:       rootVolumeName,
-		ID:         rootVolumeID,
+		Name:       expandStringPtr(rootVolumeName),
+		ID:         expandStringPtr(rootVolumeID),
 		VolumeType: instance.VolumeVolumeType(rootVolumeType),
-		Size:       rootVolumeSize,
-		Boot:       *rootVolumeIsBootVolume,
+		Size:       expandInstanceVolumeSize(rootVolumeSize),
+		Boot:       expandInstanceVolumeBoot(*rootVolumeIsBootVolume),
 	}
 
 	if raw, ok := d.GetOk("additional_volume_ids"); ok {
@@ -396,10 +495,10 @@ func resourceScalewayInstanceServerCreate(ctx context.Context, d *schema.Resourc
 				return diag.FromErr(err)
 			}
 			req.Volumes[strconv.Itoa(i+1)] = &instance.VolumeServerTemplate{
-				ID:         vol.Volume.ID,
-				Name:       vol.Volume.Name,
+				ID:         expandStringPtr(vol.Volume.ID),
+				Name:       expandStringPtr(vol.Volume.Name),
 				VolumeType: vol.Volume.VolumeType,
-				Size:       vol.Volume.Size,
+				Size:       expandInstanceVolumeSize(vol.Volume.Size),
 			}
 		}
 	}
@@ -424,6 +523,20 @@ func resourceScalewayInstanceServerCreate(ctx context.Context, d *schema.Resourc
 		return diag.FromErr(err)
 	}
 
+	////
+	// Attach routed IPs
+	////
+	if rawIPIDs, ok := d.GetOk("ip_ids"); ok {
+		err = enableInstanceServerRoutedIP(ctx, meta.(*Meta).scwClient, zone, res.Server.ID)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		err = attachInstanceServerIPs(ctx, instanceAPI, zone, res.Server.ID, expandInstanceServerIPIDs(rawIPIDs), d.Timeout(schema.TimeoutCreate))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
 	////
 	// Set user data
 	////
@@ -460,7 +573,11 @@ func resourceScalewayInstanceServerCreate(ctx context.Context, d *schema.Resourc
 	if err != nil {
 		return diag.FromErr(err)
 	}
-	err = reachState(ctx, instanceAPI, zone, res.Server.ID, targetState)
+	lifecycle, err := expandInstanceServerLifecycle(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	err = reachState(ctx, instanceAPI, zone, res.Server.ID, targetState, lifecycle)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -468,7 +585,15 @@ func resourceScalewayInstanceServerCreate(ctx context.Context, d *schema.Resourc
 	////
 	// Private Network
 	////
-	if rawPNICs, ok := d.GetOk("private_network"); ok {
+	rawPNICs, hasPNICs := d.GetOk("private_network")
+	if !hasPNICs && len(template.PrivateNetworkIDs) > 0 {
+		templatePNICs := []interface{}(nil)
+		for _, privateNetworkID := range template.PrivateNetworkIDs {
+			templatePNICs = append(templatePNICs, map[string]interface{}{"pn_id": privateNetworkID})
+		}
+		rawPNICs, hasPNICs = templatePNICs, true
+	}
+	if hasPNICs {
 		vpcAPI, err := vpcAPI(meta)
 		if err != nil {
 			return diag.FromErr(err)
@@ -512,7 +637,7 @@ func resourceScalewayInstanceServerRead(ctx context.Context, d *schema.ResourceD
 		return diag.FromErr(err)
 	}
 
-	server, err := waitForInstanceServer(ctx, instanceAPI, zone, id, d.Timeout(schema.TimeoutRead))
+	serverWithPublicIPs, err := waitForInstanceServerWithPublicIPs(ctx, meta.(*Meta).scwClient, zone, id, d.Timeout(schema.TimeoutRead))
 	if err != nil {
 		if errorCheck(err, "is not found") {
 			log.Printf("[WARN] instance %s not found droping from state", d.Id())
@@ -521,6 +646,7 @@ func resourceScalewayInstanceServerRead(ctx context.Context, d *schema.ResourceD
 		}
 		return diag.FromErr(err)
 	}
+	server, publicIPs := serverWithPublicIPs.Server, serverWithPublicIPs.PublicIPs
 	////
 	// Read Server
 	////
@@ -537,7 +663,7 @@ func resourceScalewayInstanceServerRead(ctx context.Context, d *schema.ResourceD
 		_ = d.Set("bootscript_id", server.Bootscript.ID)
 		_ = d.Set("type", server.CommercialType)
 		if len(server.Tags) > 0 {
-			_ = d.Set("tags", server.Tags)
+			setTags(d, meta, server.Tags)
 		}
 		_ = d.Set("security_group_id", newZonedID(zone, server.SecurityGroup.ID).String())
 		_ = d.Set("enable_ipv6", server.EnableIPv6)
@@ -562,22 +688,30 @@ func resourceScalewayInstanceServerRead(ctx context.Context, d *schema.ResourceD
 			_ = d.Set("private_ip", flattenStringPtr(server.PrivateIP))
 		}
 
+		// Routed IPs listed in ip_ids are not managed by ip_id, even when the first one is the public IP of the server.
+		_, hasIPIDs := d.GetOk("ip_ids")
+		ipID := ""
 		if server.PublicIP != nil {
 			_ = d.Set("public_ip", server.PublicIP.Address.String())
 			d.SetConnInfo(map[string]string{
 				"type": "ssh",
 				"host": server.PublicIP.Address.String(),
 			})
-			if !server.PublicIP.Dynamic {
-				_ = d.Set("ip_id", newZonedID(zone, server.PublicIP.ID).String())
-			} else {
-				_ = d.Set("ip_id", "")
+			if !server.PublicIP.Dynamic && !hasIPIDs {
+				ipID = server.PublicIP.ID
 			}
 		} else {
 			_ = d.Set("public_ip", "")
-			_ = d.Set("ip_id", "")
 			d.SetConnInfo(nil)
 		}
+		if ipID != "" {
+			_ = d.Set("ip_id", newZonedID(zone, ipID).String())
+		} else {
+			_ = d.Set("ip_id", "")
+		}
+
+		_ = d.Set("ip_ids", flattenInstanceServerIPIDs(zone, publicIPs, ipID, d.Get("ip_ids").([]interface{})))
+		_ = d.Set("public_ips", flattenInstanceServerPublicIPs(zone, publicIPs))
 
 		if server.IPv6 != nil {
 			_ = d.Set("ipv6_address", server.IPv6.Address.String())
@@ -635,13 +769,15 @@ func resourceScalewayInstanceServerRead(ctx context.Context, d *schema.ResourceD
 			if err != nil {
 				return diag.FromErr(err)
 			}
-			// if key != "cloud-init" {
 			userData[key] = string(userDataValue)
-			//	} else {
-			// _ = d.Set("cloud_init", string(userDataValue))
-			// }
 		}
 		_ = d.Set("user_data", userData)
+		// cloud_init is written to the cloud-init user data
+		if cloudInit, exists := userData["cloud-init"]; exists {
+			_ = d.Set("cloud_init", cloudInit)
+		} else {
+			_ = d.Set("cloud_init", "")
+		}
 
 		////
 		// Read server private networks
@@ -678,6 +814,33 @@ func resourceScalewayInstanceServerUpdate(ctx context.Context, d *schema.Resourc
 	if err != nil {
 		return diag.FromErr(err)
 	}
+
+	////
+	// Update server type
+	////
+	if d.HasChange("type") {
+		err = resourceScalewayInstanceServerUpdateType(ctx, d, meta, instanceAPI, server)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	////
+	// Grow root volume
+	////
+	if d.HasChange("root_volume.0.size_in_gb") {
+		err = resizeInstanceVolume(ctx, instanceAPI, zone, expandZonedID(d.Get("root_volume.0.volume_id")).ID, d.Get("root_volume.0.size_in_gb").(int), d.Timeout(schema.TimeoutUpdate))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		if !isStopped {
+			warnings = append(warnings, diag.Diagnostic{
+				Severity: diag.Warning,
+				Summary:  "instance may need to be rebooted for its filesystem to use the new root volume size",
+			})
+		}
+	}
+
 	////
 	// Construct UpdateServerRequest
 	////
@@ -690,8 +853,8 @@ func resourceScalewayInstanceServerUpdate(ctx context.Context, d *schema.Resourc
 		updateRequest.Name = expandStringPtr(d.Get("name"))
 	}
 
-	if d.HasChange("tags") {
-		updateRequest.Tags = expandUpdatedStringsPtr(d.Get("tags"))
+	if d.HasChanges("tags", "tags_all") {
+		updateRequest.Tags = expandUpdatedTagsAll(d, meta)
 	}
 
 	if d.HasChange("security_group_id") {
@@ -713,9 +876,9 @@ func resourceScalewayInstanceServerUpdate(ctx context.Context, d *schema.Resourc
 
 	if raw, hasAdditionalVolumes := d.GetOk("additional_volume_ids"); d.HasChanges("additional_volume_ids", "root_volume") {
 		volumes["0"] = &instance.VolumeServerTemplate{
-			ID:   expandZonedID(d.Get("root_volume.0.volume_id")).ID,
-			Name: newRandomName("vol"), // name is ignored by the API, any name will work here
-			Boot: d.Get("root_volume.0.boot").(bool),
+			ID:   expandStringPtr(expandZonedID(d.Get("root_volume.0.volume_id")).ID),
+			Name: scw.StringPtr(newRandomName("vol")), // name is ignored by the API, any name will work here
+			Boot: expandInstanceVolumeBoot(d.Get("root_volume.0.boot").(bool)),
 		}
 
 		if !hasAdditionalVolumes {
@@ -742,8 +905,8 @@ func resourceScalewayInstanceServerUpdate(ctx context.Context, d *schema.Resourc
 				}
 			}
 			volumes[strconv.Itoa(i+1)] = &instance.VolumeServerTemplate{
-				ID:   expandZonedID(volumeID).ID,
-				Name: newRandomName("vol"), // name is ignored by the API, any name will work here
+				ID:   expandStringPtr(expandZonedID(volumeID).ID),
+				Name: scw.StringPtr(newRandomName("vol")), // name is ignored by the API, any name will work here
 			}
 		}
 
@@ -811,6 +974,33 @@ func resourceScalewayInstanceServerUpdate(ctx context.Context, d *schema.Resourc
 		}
 	}
 
+	////
+	// Update routed IPs
+	////
+	if d.HasChange("ip_ids") {
+		oldIPIDs, newIPIDs := d.GetChange("ip_ids")
+		detachedIPIDs, attachedIPIDs := diffInstanceServerIPIDs(expandInstanceServerIPIDs(oldIPIDs), expandInstanceServerIPIDs(newIPIDs))
+
+		_, err := waitForInstanceServer(ctx, instanceAPI, zone, id, d.Timeout(schema.TimeoutUpdate))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		err = attachInstanceServerIPs(ctx, instanceAPI, zone, "", detachedIPIDs, d.Timeout(schema.TimeoutUpdate))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		if len(attachedIPIDs) > 0 {
+			err = enableInstanceServerRoutedIP(ctx, meta.(*Meta).scwClient, zone, id)
+			if err != nil {
+				return diag.FromErr(err)
+			}
+			err = attachInstanceServerIPs(ctx, instanceAPI, zone, id, attachedIPIDs, d.Timeout(schema.TimeoutUpdate))
+			if err != nil {
+				return diag.FromErr(err)
+			}
+		}
+	}
+
 	if d.HasChanges("boot_type") {
 		bootType := instance.BootType(d.Get("boot_type").(string))
 		updateRequest.BootType = &bootType
@@ -920,13 +1110,18 @@ func resourceScalewayInstanceServerUpdate(ctx context.Context, d *schema.Resourc
 	// Apply changes
 	////
 
-	if d.HasChange("state") {
+	// The server is stopped to change its type
+	if d.HasChanges("state", "type") {
 		targetState, err := serverStateExpand(d.Get("state").(string))
 		if err != nil {
 			return diag.FromErr(err)
 		}
+		lifecycle, err := expandInstanceServerLifecycle(d)
+		if err != nil {
+			return diag.FromErr(err)
+		}
 		// reach expected state
-		err = reachState(ctx, instanceAPI, zone, id, targetState)
+		err = reachState(ctx, instanceAPI, zone, id, targetState, lifecycle)
 		if err != nil {
 			return diag.FromErr(err)
 		}
@@ -945,6 +1140,49 @@ func resourceScalewayInstanceServerUpdate(ctx context.Context, d *schema.Resourc
 	return append(warnings, resourceScalewayInstanceServerRead(ctx, d, meta)...)
 }
 
+// resourceScalewayInstanceServerUpdateType stops the server and changes its type in place.
+// The server is brought back to its requested state at the end of the update.
+// It fails without stopping the server when the new type is not compatible with it, according to the local checks and to the API.
+func resourceScalewayInstanceServerUpdateType(ctx context.Context, d *schema.ResourceData, meta interface{}, instanceAPI *instance.API, server *instance.Server) error {
+	commercialType := d.Get("type").(string)
+	err := validateServerTypeChange(ctx, instanceAPI, server.Zone, server, commercialType)
+	if err == nil {
+		compatibleTypes, compatibleErr := getServerCompatibleTypes(ctx, meta.(*Meta).scwClient, server.Zone, server.ID)
+		if compatibleErr != nil {
+			return compatibleErr
+		}
+		if !slices.Contains(compatibleTypes, commercialType) {
+			err = fmt.Errorf("server type %s is not compatible according to the API", commercialType)
+		}
+	}
+	if err != nil {
+		d.Partial(true)
+		return fmt.Errorf("server %s cannot change its type to %s in place, set replace_on_type_change to true to replace it instead: %w", server.ID, commercialType, err)
+	}
+
+	lifecycle, err := expandInstanceServerLifecycle(d)
+	if err != nil {
+		return err
+	}
+
+	err = reachState(ctx, instanceAPI, server.Zone, server.ID, instance.ServerStateStopped, lifecycle)
+	if err != nil {
+		return err
+	}
+
+	err = updateServerType(ctx, instanceAPI, server.Zone, server.ID, commercialType)
+	if err != nil {
+		// Keep the previous type in the state and restore the requested state of the server.
+		d.Partial(true)
+		if targetState, stateErr := serverStateExpand(d.Get("state").(string)); stateErr == nil {
+			_ = reachState(ctx, instanceAPI, server.Zone, server.ID, targetState, lifecycle)
+		}
+		return fmt.Errorf("failed to change the type of server %s to %s, set replace_on_type_change to true to replace it instead: %w", server.ID, commercialType, err)
+	}
+
+	return nil
+}
+
 func resourceScalewayInstanceServerDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	instanceAPI, zone, id, err := instanceAPIWithZoneAndID(meta, d.Id())
 	if err != nil {
@@ -961,6 +1199,12 @@ func resourceScalewayInstanceServerDelete(ctx context.Context, d *schema.Resourc
 			log.Print("[WARN] Failed to detach eip of server")
 		}
 	}
+	if rawIPIDs, ok := d.GetOk("ip_ids"); ok {
+		err := attachInstanceServerIPs(ctx, instanceAPI, zone, "", expandInstanceServerIPIDs(rawIPIDs), d.Timeout(schema.TimeoutDelete))
+		if err != nil {
+			log.Print("[WARN] Failed to detach routed ips of server")
+		}
+	}
 	// Remove instance from placement group to free it even if instance won't stop
 	if _, ok := d.GetOk("placement_group_id"); ok {
 		_, err := instanceAPI.UpdateServer(&instance.UpdateServerRequest{
@@ -972,8 +1216,12 @@ func resourceScalewayInstanceServerDelete(ctx context.Context, d *schema.Resourc
 			log.Print("[WARN] Failed remove server from instance group")
 		}
 	}
-	// reach stopped state
-	err = reachState(ctx, instanceAPI, zone, id, instance.ServerStateStopped)
+	lifecycle, err := expandInstanceServerLifecycle(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	server, err := waitForInstanceServer(ctx, instanceAPI, zone, id, d.Timeout(schema.TimeoutDelete))
 	if is404Error(err) {
 		return nil
 	}
@@ -981,15 +1229,64 @@ func resourceScalewayInstanceServerDelete(ctx context.Context, d *schema.Resourc
 		return diag.FromErr(err)
 	}
 
-	_, err = waitForInstanceServer(ctx, instanceAPI, zone, id, d.Timeout(schema.TimeoutDelete))
-	if err != nil {
-		return diag.FromErr(err)
+	// Terminating a server also deletes its block volumes, it is only done when they would be deleted anyway.
+	// Final snapshots are taken from a stopped server, it is stopped like with poweroff in that case.
+	terminate := lifecycle.StopAction == InstanceServerStopActionTerminate &&
+		server.State == instance.ServerStateRunning &&
+		len(server.Volumes) == 1 &&
+		d.Get("root_volume.0.delete_on_termination").(bool) &&
+		!d.Get("final_snapshot").(bool)
+
+	if !terminate {
+		// reach stopped state
+		err = reachState(ctx, instanceAPI, zone, id, instance.ServerStateStopped, lifecycle)
+		if is404Error(err) {
+			return nil
+		}
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		server, err = waitForInstanceServer(ctx, instanceAPI, zone, id, d.Timeout(schema.TimeoutDelete))
+		if err != nil {
+			return diag.FromErr(err)
+		}
 	}
 
-	err = instanceAPI.DeleteServer(&instance.DeleteServerRequest{
-		Zone:     zone,
-		ServerID: id,
-	}, scw.WithContext(ctx))
+	var warnings diag.Diagnostics
+
+	if d.Get("final_snapshot").(bool) {
+		snapshotIDs := []string(nil)
+		for _, volume := range sortVolumeServer(server.Volumes) {
+			snapshotID, err := createInstanceFinalSnapshot(ctx, instanceAPI, zone, volume.ID, volume.Name, server.Name, server.Project, d.Timeout(schema.TimeoutDelete))
+			// Snapshots taken so far are kept in the state when the deletion fails.
+			if snapshotID != "" {
+				snapshotIDs = append(snapshotIDs, snapshotID)
+				_ = d.Set("final_snapshot_ids", snapshotIDs)
+			}
+			if err != nil {
+				return diag.FromErr(err)
+			}
+		}
+		warnings = append(warnings, finalSnapshotWarning(server.Name, snapshotIDs))
+	}
+
+	if terminate {
+		err = waitForServerCondition(ctx, instanceAPI, zone, id, lifecycle.PreStop)
+		if err != nil {
+			return diag.FromErr(fmt.Errorf("pre-stop condition of server %s: %w", id, err))
+		}
+		_, err = instanceAPI.ServerAction(&instance.ServerActionRequest{
+			Zone:     zone,
+			ServerID: id,
+			Action:   instance.ServerActionTerminate,
+		}, scw.WithContext(ctx))
+	} else {
+		err = instanceAPI.DeleteServer(&instance.DeleteServerRequest{
+			Zone:     zone,
+			ServerID: id,
+		}, scw.WithContext(ctx))
+	}
 	if err != nil && !is404Error(err) {
 		return diag.FromErr(err)
 	}
@@ -1015,5 +1312,44 @@ func resourceScalewayInstanceServerDelete(ctx context.Context, d *schema.Resourc
 		}
 	}
 
-	return nil
+	return warnings
+}
+
+// instanceServerWaitConditionSchema returns the schema of a serverWaitCondition.
+func instanceServerWaitConditionSchema(description string) *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Optional:    true,
+		MaxItems:    1,
+		Description: description,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"user_data_key": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Description: "Wait for this user data key to exist on the server",
+				},
+				"tcp_port": {
+					Type:         schema.TypeInt,
+					Optional:     true,
+					ValidateFunc: validation.IsPortNumber,
+					Description:  "Wait for this TCP port to accept connections",
+				},
+				"tcp_address": {
+					Type:         schema.TypeString,
+					Optional:     true,
+					ValidateFunc: validation.IsIPAddress,
+					Description:  "The address to reach tcp_port on, defaults to the public IP of the server",
+				},
+				"timeout": {
+					Type:             schema.TypeString,
+					Optional:         true,
+					Default:          defaultInstanceServerConditionTimeout.String(),
+					DiffSuppressFunc: diffSuppressFuncDuration,
+					ValidateFunc:     validateDuration(),
+					Description:      "Maximum time to wait for the condition",
+				},
+			},
+		},
+	}
 }
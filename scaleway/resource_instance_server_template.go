@@ -0,0 +1,158 @@
+package scaleway
+
+import (
+	"context"
+
+	"github.com/hashicorp/go-uuid"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/scaleway/scaleway-sdk-go/api/instance/v1"
+)
+
+func resourceScalewayInstanceServerTemplate() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceScalewayInstanceServerTemplateCreate,
+		ReadContext:   resourceScalewayInstanceServerTemplateRead,
+		UpdateContext: resourceScalewayInstanceServerTemplateUpdate,
+		DeleteContext: resourceScalewayInstanceServerTemplateDelete,
+		SchemaVersion: 0,
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "The name of the server template",
+			},
+			"image": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Description:      "The UUID or the label of the base image used by the servers",
+				DiffSuppressFunc: diffSuppressFuncLocality,
+			},
+			"type": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Description:      "The instance type of the servers",
+				DiffSuppressFunc: diffSuppressFuncIgnoreCase,
+			},
+			"root_volume": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				MaxItems:    1,
+				Description: "Root volume of the servers, applied when they are created",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"size_in_gb": {
+							Type:        schema.TypeInt,
+							Optional:    true,
+							Description: "Size of the root volume in gigabytes",
+						},
+						"volume_type": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "Volume type of the root volume",
+							ValidateFunc: validation.StringInSlice([]string{
+								instance.VolumeVolumeTypeBSSD.String(),
+								instance.VolumeVolumeTypeLSSD.String(),
+							}, false),
+						},
+					},
+				},
+			},
+			"security_group_id": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Description:      "The security group the servers are attached to",
+				ValidateFunc:     validationUUIDorUUIDWithLocality(),
+				DiffSuppressFunc: diffSuppressFuncLocality,
+			},
+			"placement_group_id": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Description:      "The placement group the servers are attached to",
+				ValidateFunc:     validationUUIDorUUIDWithLocality(),
+				DiffSuppressFunc: diffSuppressFuncLocality,
+			},
+			"cloud_init": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  "The cloud init script of the servers",
+				ValidateFunc: validation.StringLenBetween(0, 127998),
+			},
+			"user_data": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Description: "The user data of the servers",
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.StringLenBetween(0, 127998),
+				},
+			},
+			"private_network_ids": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				MaxItems:    8,
+				Description: "The private networks the servers are attached to when they are created",
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validationUUIDorUUIDWithLocality(),
+				},
+			},
+			"revision": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The revision of the template, incremented on each change",
+			},
+			"content": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The content of the template, to set in the template argument of servers and pools",
+			},
+			"zone": zoneSchema(),
+		},
+		CustomizeDiff: customizeDiffInstanceServerTemplateContent,
+	}
+}
+
+func resourceScalewayInstanceServerTemplateCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	zone, err := extractZone(d, meta.(*Meta))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	id, err := uuid.GenerateUUID()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(newZonedIDString(zone, id))
+	_ = d.Set("name", expandOrGenerateString(d.Get("name"), "srv-template"))
+	_ = d.Set("revision", 1)
+
+	return resourceScalewayInstanceServerTemplateUpdate(ctx, d, meta)
+}
+
+func resourceScalewayInstanceServerTemplateRead(_ context.Context, _ *schema.ResourceData, _ interface{}) diag.Diagnostics {
+	// Server templates only exist in the Terraform state.
+	return nil
+}
+
+func resourceScalewayInstanceServerTemplateUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	zone, _, err := parseZonedID(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	content, err := flattenInstanceServerTemplateContent(expandInstanceServerTemplate(d, zone))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	_ = d.Set("zone", zone.String())
+	_ = d.Set("content", content)
+
+	return resourceScalewayInstanceServerTemplateRead(ctx, d, meta)
+}
+
+func resourceScalewayInstanceServerTemplateDelete(_ context.Context, _ *schema.ResourceData, _ interface{}) diag.Diagnostics {
+	return nil
+}
@@ -0,0 +1,191 @@
+package scaleway
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccScalewayInstanceServerTemplate_Basic(t *testing.T) {
+	tt := NewTestTools(t)
+	defer tt.Cleanup()
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: tt.ProviderFactories,
+		CheckDestroy:      testAccCheckScalewayInstanceServerDestroy(tt),
+		Steps: []resource.TestStep{
+			{
+				Config: `
+					resource "scaleway_instance_server_template" "main" {
+						type       = "DEV1-S"
+						image      = "ubuntu_jammy"
+						cloud_init = "#cloud-config"
+					}
+
+					resource "scaleway_instance_server" "main" {
+						template = scaleway_instance_server_template.main.content
+						tags     = ["terraform-test", "scaleway_instance_server_template", "basic"]
+					}
+				`,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckScalewayInstanceServerExists(tt, "scaleway_instance_server.main"),
+					resource.TestCheckResourceAttr("scaleway_instance_server_template.main", "revision", "1"),
+					resource.TestCheckResourceAttrSet("scaleway_instance_server_template.main", "content"),
+					resource.TestCheckResourceAttr("scaleway_instance_server.main", "type", "DEV1-S"),
+					resource.TestCheckResourceAttr("scaleway_instance_server.main", "cloud_init", "#cloud-config"),
+					resource.TestCheckResourceAttr("scaleway_instance_server.main", "template_fields.#", "3"),
+				),
+			},
+			{
+				Config: `
+					resource "scaleway_instance_server_template" "main" {
+						type       = "DEV1-S"
+						image      = "ubuntu_jammy"
+						cloud_init = "#cloud-config\n"
+					}
+
+					resource "scaleway_instance_server" "main" {
+						template = scaleway_instance_server_template.main.content
+						type     = "DEV1-M"
+						tags     = ["terraform-test", "scaleway_instance_server_template", "basic"]
+					}
+				`,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckScalewayInstanceServerExists(tt, "scaleway_instance_server.main"),
+					resource.TestCheckResourceAttr("scaleway_instance_server_template.main", "revision", "2"),
+					resource.TestCheckResourceAttr("scaleway_instance_server.main", "type", "DEV1-M"),
+					resource.TestCheckResourceAttr("scaleway_instance_server.main", "cloud_init", "#cloud-config\n"),
+					resource.TestCheckResourceAttr("scaleway_instance_server.main", "template_fields.#", "2"),
+				),
+			},
+		},
+	})
+}
+
+func TestFakeAPI_InstanceServerTemplate(t *testing.T) {
+	tt := NewFakeAPITestTools(t)
+	defer tt.Cleanup()
+	ctx := context.Background()
+
+	template := resourceScalewayInstanceServerTemplate()
+	templateConfig := map[string]cty.Value{
+		"name":       cty.StringVal("web"),
+		"type":       cty.StringVal("DEV1-S"),
+		"image":      cty.StringVal("11111111-1111-1111-1111-111111111111"),
+		"cloud_init": cty.StringVal("#cloud-config"),
+		"root_volume": cty.ListVal([]cty.Value{cty.ObjectVal(map[string]cty.Value{
+			"size_in_gb":  cty.NumberIntVal(30),
+			"volume_type": cty.StringVal("b_ssd"),
+		})}),
+	}
+	diff, err := testPlanResource(t, template, nil, tt.Meta, templateConfig)
+	require.NoError(t, err)
+	templateState, diags := template.Apply(ctx, nil, diff, tt.Meta)
+	require.False(t, diags.HasError(), diags)
+	assert.Equal(t, "1", templateState.Attributes["revision"])
+	assert.JSONEq(t, `{
+		"image": "11111111-1111-1111-1111-111111111111",
+		"type": "DEV1-S",
+		"root_volume_size_in_gb": 30,
+		"root_volume_type": "b_ssd",
+		"cloud_init": "#cloud-config"
+	}`, templateState.Attributes["content"])
+
+	// The template is only kept in the state.
+	templateState, diags = template.RefreshWithoutUpgrade(ctx, templateState, tt.Meta)
+	require.False(t, diags.HasError(), diags)
+	require.NotNil(t, templateState)
+
+	// The content is computed when the arguments are not known yet.
+	templateConfig["image"] = cty.UnknownVal(cty.String)
+	diff, err = testPlanResource(t, template, templateState, tt.Meta, templateConfig)
+	require.NoError(t, err)
+	assert.True(t, diff.Attributes["content"].NewComputed)
+	assert.Equal(t, "2", diff.Attributes["revision"].New)
+
+	server := resourceScalewayInstanceServer()
+	diff, err = testPlanResource(t, server, nil, tt.Meta, map[string]cty.Value{
+		"template": cty.StringVal(templateState.Attributes["content"]),
+		"type":     cty.StringVal("DEV1-M"),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "DEV1-M", diff.Attributes["type"].New)
+	assert.Equal(t, "11111111-1111-1111-1111-111111111111", diff.Attributes["image"].New)
+	assert.Equal(t, "#cloud-config", diff.Attributes["cloud_init"].New)
+	assert.Equal(t, "3", diff.Attributes["template_fields.#"].New)
+	assert.Equal(t, "image", diff.Attributes["template_fields.0"].New)
+	assert.Equal(t, "root_volume", diff.Attributes["template_fields.2"].New)
+
+	state, diags := server.Apply(ctx, nil, diff, tt.Meta)
+	require.False(t, diags.HasError(), diags)
+	assert.Equal(t, "DEV1-M", state.Attributes["type"])
+	assert.Equal(t, "30", state.Attributes["root_volume.0.size_in_gb"])
+	assert.Equal(t, "b_ssd", state.Attributes["root_volume.0.volume_type"])
+
+	// Without template, a server needs a type and an image or a root volume.
+	_, err = testPlanResource(t, server, nil, tt.Meta, map[string]cty.Value{
+		"image": cty.StringVal("11111111-1111-1111-1111-111111111111"),
+	})
+	assert.ErrorContains(t, err, "one of `template,type` must be specified")
+	_, err = testPlanResource(t, server, nil, tt.Meta, map[string]cty.Value{
+		"type": cty.StringVal("DEV1-S"),
+	})
+	assert.ErrorContains(t, err, "one of `image,root_volume.0.volume_id,template` must be specified")
+
+	// With a template, the type must still be set somewhere.
+	_, err = testPlanResource(t, server, nil, tt.Meta, map[string]cty.Value{
+		"template": cty.StringVal(`{"image": "11111111-1111-1111-1111-111111111111"}`),
+	})
+	assert.ErrorContains(t, err, "type must be set")
+
+	// The root volume set by the template can be overridden without setting the image.
+	diff, err = testPlanResource(t, server, nil, tt.Meta, map[string]cty.Value{
+		"template": cty.StringVal(templateState.Attributes["content"]),
+		"root_volume": cty.ListVal([]cty.Value{cty.ObjectVal(map[string]cty.Value{
+			"size_in_gb": cty.NumberIntVal(40),
+		})}),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "40", diff.Attributes["root_volume.0.size_in_gb"].New)
+	assert.Equal(t, "11111111-1111-1111-1111-111111111111", diff.Attributes["image"].New)
+
+	// A root volume cannot be both created from an image and an existing volume.
+	_, err = testPlanResource(t, server, nil, tt.Meta, map[string]cty.Value{
+		"type":  cty.StringVal("DEV1-S"),
+		"image": cty.StringVal("11111111-1111-1111-1111-111111111111"),
+		"root_volume": cty.ListVal([]cty.Value{cty.ObjectVal(map[string]cty.Value{
+			"volume_id": cty.StringVal("fr-par-1/22222222-2222-2222-2222-222222222222"),
+		})}),
+	})
+	assert.ErrorContains(t, err, "conflicts with")
+
+	// The image of the template is not used when the root volume is an existing volume.
+	diff, err = testPlanResource(t, server, nil, tt.Meta, map[string]cty.Value{
+		"template": cty.StringVal(templateState.Attributes["content"]),
+		"root_volume": cty.ListVal([]cty.Value{cty.ObjectVal(map[string]cty.Value{
+			"volume_id": cty.StringVal("fr-par-1/22222222-2222-2222-2222-222222222222"),
+		})}),
+	})
+	require.NoError(t, err)
+	assert.NotContains(t, diff.Attributes, "image")
+
+	// Changes of the template are planned on the servers using it.
+	templateConfig["image"] = cty.StringVal("22222222-2222-2222-2222-222222222222")
+	diff, err = testPlanResource(t, template, templateState, tt.Meta, templateConfig)
+	require.NoError(t, err)
+	templateState, diags = template.Apply(ctx, templateState, diff, tt.Meta)
+	require.False(t, diags.HasError(), diags)
+	assert.Equal(t, "2", templateState.Attributes["revision"])
+
+	diff, err = testPlanResource(t, server, state, tt.Meta, map[string]cty.Value{
+		"template": cty.StringVal(templateState.Attributes["content"]),
+		"type":     cty.StringVal("DEV1-M"),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "22222222-2222-2222-2222-222222222222", diff.Attributes["image"].New)
+	assert.True(t, diff.RequiresNew())
+}
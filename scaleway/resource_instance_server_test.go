@@ -1,14 +1,22 @@
 package scaleway
 
 import (
+	"context"
 	"fmt"
+	"net"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 	"github.com/scaleway/scaleway-sdk-go/api/instance/v1"
 	"github.com/scaleway/scaleway-sdk-go/scw"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func init() {
@@ -1317,3 +1325,319 @@ func TestAccScalewayInstanceServer_PrivateNetwork(t *testing.T) {
 		},
 	})
 }
+
+func TestFakeAPI_InstanceServerUpdateType(t *testing.T) {
+	tt := NewFakeAPITestTools(t)
+	defer tt.Cleanup()
+	ctx := context.Background()
+
+	d := schema.TestResourceDataRaw(t, resourceScalewayInstanceServer().Schema, map[string]interface{}{
+		"name":  "server",
+		"type":  "DEV1-S",
+		"image": "11111111-1111-1111-1111-111111111111",
+		"state": "started",
+	})
+	diags := resourceScalewayInstanceServerCreate(ctx, d, tt.Meta)
+	require.False(t, diags.HasError(), diags)
+
+	instanceAPI, zone, id, err := instanceAPIWithZoneAndID(tt.Meta, d.Id())
+	require.NoError(t, err)
+	getServer := func() *instance.Server {
+		res, err := instanceAPI.GetServer(&instance.GetServerRequest{Zone: zone, ServerID: id})
+		require.NoError(t, err)
+		return res.Server
+	}
+	require.Equal(t, instance.ServerStateRunning, getServer().State)
+
+	// The fake API rejects commercial type changes of running servers, like the real one.
+	err = updateServerType(ctx, instanceAPI, zone, id, "DEV1-M")
+	require.Error(t, err)
+
+	err = validateServerTypeChange(ctx, instanceAPI, zone, getServer(), "AMP2-C1")
+	assert.ErrorContains(t, err, "architecture")
+	err = validateServerTypeChange(ctx, instanceAPI, zone, getServer(), "UNKNOWN")
+	assert.ErrorContains(t, err, "not available")
+
+	// The plan only replaces the server when replace_on_type_change is true.
+	planType := func(commercialType string, replace bool) *terraform.InstanceDiff {
+		diff, err := testPlanResource(t, resourceScalewayInstanceServer(), d.State(), tt.Meta, map[string]cty.Value{
+			"name":                   cty.StringVal("server"),
+			"type":                   cty.StringVal(commercialType),
+			"image":                  cty.StringVal("11111111-1111-1111-1111-111111111111"),
+			"state":                  cty.StringVal("started"),
+			"replace_on_type_change": cty.BoolVal(replace),
+		})
+		require.NoError(t, err)
+		return diff
+	}
+	assert.False(t, planType("DEV1-M", false).RequiresNew())
+	assert.False(t, planType("GP1-XS", false).RequiresNew())
+	assert.True(t, planType("DEV1-M", true).RequiresNew())
+
+	// The update fails without stopping the server when the API does not list the new type as compatible.
+	_ = d.Set("type", "GP1-XS")
+	err = resourceScalewayInstanceServerUpdateType(ctx, d, tt.Meta, instanceAPI, getServer())
+	assert.ErrorContains(t, err, "server type GP1-XS is not compatible according to the API")
+	assert.Equal(t, instance.ServerStateRunning, getServer().State)
+
+	_ = d.Set("type", "DEV1-M")
+	err = resourceScalewayInstanceServerUpdateType(ctx, d, tt.Meta, instanceAPI, getServer())
+	require.NoError(t, err)
+	server := getServer()
+	assert.Equal(t, "DEV1-M", server.CommercialType)
+	assert.Equal(t, instance.ServerStateStopped, server.State)
+}
+
+func TestFakeAPI_InstanceServerGrowRootVolume(t *testing.T) {
+	tt := NewFakeAPITestTools(t)
+	defer tt.Cleanup()
+	ctx := context.Background()
+
+	d := schema.TestResourceDataRaw(t, resourceScalewayInstanceServer().Schema, map[string]interface{}{
+		"name":  "server",
+		"type":  "DEV1-S",
+		"image": "11111111-1111-1111-1111-111111111111",
+		"root_volume": []interface{}{map[string]interface{}{
+			"volume_type": "b_ssd",
+			"size_in_gb":  20,
+		}},
+	})
+	diags := resourceScalewayInstanceServerCreate(ctx, d, tt.Meta)
+	require.False(t, diags.HasError(), diags)
+	require.Equal(t, 20, d.Get("root_volume.0.size_in_gb"))
+
+	server := resourceScalewayInstanceServer()
+	config := func(sizeInGB int64) map[string]cty.Value {
+		return map[string]cty.Value{
+			"name":  cty.StringVal("server"),
+			"type":  cty.StringVal("DEV1-S"),
+			"image": cty.StringVal("11111111-1111-1111-1111-111111111111"),
+			"root_volume": cty.ListVal([]cty.Value{cty.ObjectVal(map[string]cty.Value{
+				"volume_type": cty.StringVal("b_ssd"),
+				"size_in_gb":  cty.NumberIntVal(sizeInGB),
+			})}),
+		}
+	}
+
+	// Growing the root volume updates the server in place.
+	diff, err := testPlanResource(t, server, d.State(), tt.Meta, config(30))
+	require.NoError(t, err)
+	require.NotNil(t, diff)
+	assert.False(t, diff.RequiresNew())
+	assert.Equal(t, "30", diff.Attributes["root_volume.0.size_in_gb"].New)
+	state, diags := server.Apply(ctx, d.State(), diff, tt.Meta)
+	require.False(t, diags.HasError(), diags)
+	assert.Equal(t, d.Id(), state.ID)
+	assert.Equal(t, "30", state.Attributes["root_volume.0.size_in_gb"])
+
+	instanceAPI, zone, _, err := instanceAPIWithZoneAndID(tt.Meta, d.Id())
+	require.NoError(t, err)
+	volume, err := instanceAPI.GetVolume(&instance.GetVolumeRequest{Zone: zone, VolumeID: expandZonedID(state.Attributes["root_volume.0.volume_id"]).ID})
+	require.NoError(t, err)
+	assert.Equal(t, scw.Size(30*gb), volume.Volume.Size)
+
+	// Shrinking it is rejected at plan time.
+	_, err = testPlanResource(t, server, state, tt.Meta, config(20))
+	assert.ErrorContains(t, err, "cannot be resized down")
+
+	assert.NoError(t, validateInstanceVolumeGrowth("b_ssd", 20, 30))
+	assert.ErrorContains(t, validateInstanceVolumeGrowth("b_ssd", 30, 20), "cannot be resized down")
+	assert.ErrorContains(t, validateInstanceVolumeGrowth("l_ssd", 20, 30), "only block volume")
+}
+
+func TestFakeAPI_InstanceFinalSnapshot(t *testing.T) {
+	tt := NewFakeAPITestTools(t)
+	defer tt.Cleanup()
+	ctx := context.Background()
+
+	server := schema.TestResourceDataRaw(t, resourceScalewayInstanceServer().Schema, map[string]interface{}{
+		"name":           "server",
+		"type":           "DEV1-S",
+		"image":          "11111111-1111-1111-1111-111111111111",
+		"final_snapshot": true,
+	})
+	diags := resourceScalewayInstanceServerCreate(ctx, server, tt.Meta)
+	require.False(t, diags.HasError(), diags)
+
+	diags = resourceScalewayInstanceServerDelete(ctx, server, tt.Meta)
+	require.False(t, diags.HasError(), diags)
+	require.Len(t, diags, 1)
+	assert.Equal(t, diag.Warning, diags[0].Severity)
+	snapshotIDs := expandStrings(server.Get("final_snapshot_ids"))
+	require.Len(t, snapshotIDs, 1)
+	assert.Contains(t, diags[0].Detail, snapshotIDs[0])
+
+	instanceAPI, zone, snapshotID, err := instanceAPIWithZoneAndID(tt.Meta, snapshotIDs[0])
+	require.NoError(t, err)
+	snapshot, err := instanceAPI.GetSnapshot(&instance.GetSnapshotRequest{Zone: zone, SnapshotID: snapshotID})
+	require.NoError(t, err)
+	assert.Contains(t, snapshot.Snapshot.Tags, "final-snapshot")
+	assert.Contains(t, snapshot.Snapshot.Tags, "server")
+
+	volume := schema.TestResourceDataRaw(t, resourceScalewayInstanceVolume().Schema, map[string]interface{}{
+		"name":           "volume",
+		"type":           "b_ssd",
+		"size_in_gb":     20,
+		"final_snapshot": true,
+	})
+	diags = resourceScalewayInstanceVolumeCreate(ctx, volume, tt.Meta)
+	require.False(t, diags.HasError(), diags)
+
+	diags = resourceScalewayInstanceVolumeDelete(ctx, volume, tt.Meta)
+	require.False(t, diags.HasError(), diags)
+	assert.Len(t, expandStrings(volume.Get("final_snapshot_ids")), 1)
+}
+
+func TestFakeAPI_InstanceServerLifecycle(t *testing.T) {
+	tt := NewFakeAPITestTools(t)
+	defer tt.Cleanup()
+	ctx := context.Background()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+	port := listener.Addr().(*net.TCPAddr).Port
+
+	d := schema.TestResourceDataRaw(t, resourceScalewayInstanceServer().Schema, map[string]interface{}{
+		"name":        "server",
+		"type":        "DEV1-S",
+		"image":       "11111111-1111-1111-1111-111111111111",
+		"stop_action": "graceful",
+		"post_start": []interface{}{map[string]interface{}{
+			"tcp_port":    port,
+			"tcp_address": "127.0.0.1",
+		}},
+	})
+	diags := resourceScalewayInstanceServerCreate(ctx, d, tt.Meta)
+	require.False(t, diags.HasError(), diags)
+
+	instanceAPI, zone, id, err := instanceAPIWithZoneAndID(tt.Meta, d.Id())
+	require.NoError(t, err)
+	lifecycle, err := expandInstanceServerLifecycle(d)
+	require.NoError(t, err)
+	assert.Equal(t, InstanceServerStopActionGraceful, lifecycle.StopAction)
+	assert.Equal(t, defaultInstanceServerConditionTimeout, lifecycle.PostStart.Timeout)
+
+	err = reachState(ctx, instanceAPI, zone, id, instance.ServerStateStopped, lifecycle)
+	require.NoError(t, err)
+	res, err := instanceAPI.GetServer(&instance.GetServerRequest{Zone: zone, ServerID: id})
+	require.NoError(t, err)
+	assert.Equal(t, instance.ServerStateStopped, res.Server.State)
+
+	// The server is not stopped while its pre-stop condition is not met.
+	lifecycle.PreStop = &serverWaitCondition{UserDataKey: "drained", Timeout: 100 * time.Millisecond}
+	err = reachState(ctx, instanceAPI, zone, id, instance.ServerStateRunning, lifecycle)
+	require.NoError(t, err)
+	err = reachState(ctx, instanceAPI, zone, id, instance.ServerStateStopped, lifecycle)
+	assert.ErrorContains(t, err, "user data drained does not exist")
+	err = instanceAPI.SetServerUserData(&instance.SetServerUserDataRequest{Zone: zone, ServerID: id, Key: "drained", Content: strings.NewReader("true")})
+	require.NoError(t, err)
+	err = reachState(ctx, instanceAPI, zone, id, instance.ServerStateStopped, lifecycle)
+	require.NoError(t, err)
+
+	// Terminating a running server deletes it with its root volume.
+	_ = d.Set("stop_action", "terminate")
+	_ = d.Set("pre_stop", nil)
+	diags = resourceScalewayInstanceServerDelete(ctx, d, tt.Meta)
+	require.False(t, diags.HasError(), diags)
+	_, err = instanceAPI.GetServer(&instance.GetServerRequest{Zone: zone, ServerID: id})
+	assert.True(t, is404Error(err))
+	_, err = instanceAPI.GetVolume(&instance.GetVolumeRequest{Zone: zone, VolumeID: expandZonedID(d.Get("root_volume.0.volume_id")).ID})
+	assert.True(t, is404Error(err))
+
+	// A server terminated with a final snapshot is stopped first, no snapshot is taken before its pre-stop condition is met.
+	d = schema.TestResourceDataRaw(t, resourceScalewayInstanceServer().Schema, map[string]interface{}{
+		"name":           "snapshotted",
+		"type":           "DEV1-S",
+		"image":          "11111111-1111-1111-1111-111111111111",
+		"stop_action":    "terminate",
+		"final_snapshot": true,
+		"pre_stop": []interface{}{map[string]interface{}{
+			"user_data_key": "drained",
+			"timeout":       "100ms",
+		}},
+	})
+	diags = resourceScalewayInstanceServerCreate(ctx, d, tt.Meta)
+	require.False(t, diags.HasError(), diags)
+	_, _, id, err = instanceAPIWithZoneAndID(tt.Meta, d.Id())
+	require.NoError(t, err)
+
+	diags = resourceScalewayInstanceServerDelete(ctx, d, tt.Meta)
+	require.True(t, diags.HasError())
+	assert.Empty(t, d.Get("final_snapshot_ids"))
+	snapshots, err := instanceAPI.ListSnapshots(&instance.ListSnapshotsRequest{Zone: zone}, scw.WithAllPages())
+	require.NoError(t, err)
+	assert.Len(t, snapshots.Snapshots, 0)
+
+	err = instanceAPI.SetServerUserData(&instance.SetServerUserDataRequest{Zone: zone, ServerID: id, Key: "drained", Content: strings.NewReader("true")})
+	require.NoError(t, err)
+	diags = resourceScalewayInstanceServerDelete(ctx, d, tt.Meta)
+	require.False(t, diags.HasError(), diags)
+	assert.Len(t, expandStrings(d.Get("final_snapshot_ids")), 1)
+	_, err = instanceAPI.GetServer(&instance.GetServerRequest{Zone: zone, ServerID: id})
+	assert.True(t, is404Error(err))
+}
+
+func TestFakeAPI_InstanceServerRoutedIPs(t *testing.T) {
+	tt := NewFakeAPITestTools(t)
+	defer tt.Cleanup()
+	ctx := context.Background()
+
+	newIP := func(ipType string) *schema.ResourceData {
+		d := schema.TestResourceDataRaw(t, resourceScalewayInstanceIP().Schema, map[string]interface{}{
+			"type": ipType,
+		})
+		diags := resourceScalewayInstanceIPCreate(ctx, d, tt.Meta)
+		require.False(t, diags.HasError(), diags)
+		return d
+	}
+	ipv4 := newIP("routed_ipv4")
+	assert.Equal(t, "routed_ipv4", ipv4.Get("type"))
+	assert.NotEmpty(t, ipv4.Get("address"))
+	ipv6 := newIP("routed_ipv6")
+	assert.Equal(t, "routed_ipv6", ipv6.Get("type"))
+	assert.Equal(t, "", ipv6.Get("address"))
+	assert.Contains(t, ipv6.Get("prefix"), "/64")
+
+	d := schema.TestResourceDataRaw(t, resourceScalewayInstanceServer().Schema, map[string]interface{}{
+		"name":   "server",
+		"type":   "DEV1-S",
+		"image":  "11111111-1111-1111-1111-111111111111",
+		"state":  "started",
+		"ip_ids": []interface{}{ipv6.Id(), ipv4.Id()},
+	})
+	diags := resourceScalewayInstanceServerCreate(ctx, d, tt.Meta)
+	require.False(t, diags.HasError(), diags)
+	assert.Equal(t, []interface{}{ipv6.Id(), ipv4.Id()}, d.Get("ip_ids"))
+	assert.Equal(t, "", d.Get("ip_id"))
+	assert.Equal(t, ipv4.Get("address"), d.Get("public_ip"))
+	publicIPs := d.Get("public_ips").([]interface{})
+	require.Len(t, publicIPs, 2)
+	families := []interface{}{publicIPs[0].(map[string]interface{})["family"], publicIPs[1].(map[string]interface{})["family"]}
+	assert.ElementsMatch(t, []interface{}{"inet", "inet6"}, families)
+
+	diags = resourceScalewayInstanceIPRead(ctx, ipv6, tt.Meta)
+	require.False(t, diags.HasError(), diags)
+	assert.Equal(t, d.Id(), ipv6.Get("server_id"))
+
+	// Removing an IP from ip_ids detaches it from the server.
+	server := resourceScalewayInstanceServer()
+	diff, err := testPlanResource(t, server, d.State(), tt.Meta, map[string]cty.Value{
+		"name":   cty.StringVal("server"),
+		"type":   cty.StringVal("DEV1-S"),
+		"image":  cty.StringVal("11111111-1111-1111-1111-111111111111"),
+		"state":  cty.StringVal("started"),
+		"ip_ids": cty.ListVal([]cty.Value{cty.StringVal(ipv6.Id())}),
+	})
+	require.NoError(t, err)
+	state, diags := server.Apply(ctx, d.State(), diff, tt.Meta)
+	require.False(t, diags.HasError(), diags)
+	assert.Equal(t, "1", state.Attributes["ip_ids.#"])
+	assert.Equal(t, ipv6.Id(), state.Attributes["ip_ids.0"])
+	assert.Equal(t, "1", state.Attributes["public_ips.#"])
+	assert.Equal(t, "", state.Attributes["public_ip"])
+
+	diags = resourceScalewayInstanceIPRead(ctx, ipv4, tt.Meta)
+	require.False(t, diags.HasError(), diags)
+	assert.Equal(t, "", ipv4.Get("server_id"))
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/scaleway/scaleway-sdk-go/api/instance/v1"
@@ -68,6 +69,7 @@ func resourceScalewayInstanceSnapshot() *schema.Resource {
 				Optional:    true,
 				Description: "The tags associated with the snapshot",
 			},
+			"tags_all": tagsAllSchema(),
 			"import": {
 				Type:     schema.TypeList,
 				ForceNew: true,
@@ -99,7 +101,10 @@ func resourceScalewayInstanceSnapshot() *schema.Resource {
 			"organization_id": organizationIDSchema(),
 			"project_id":      projectIDSchema(),
 		},
-		CustomizeDiff: customizeDiffLocalityCheck("volume_id"),
+		CustomizeDiff: customdiff.Sequence(
+			customizeDiffLocalityCheck("volume_id"),
+			customizeDiffTagsAll,
+		),
 	}
 }
 
@@ -119,7 +124,7 @@ func resourceScalewayInstanceSnapshotCreate(ctx context.Context, d *schema.Resou
 		volumeType := instance.SnapshotVolumeType(volumeType.(string))
 		req.VolumeType = volumeType
 	}
-	tags := expandStrings(d.Get("tags"))
+	tags := expandTagsAll(d, meta)
 	if len(tags) > 0 {
 		req.Tags = tags
 	}
@@ -174,7 +179,13 @@ func resourceScalewayInstanceSnapshotRead(ctx context.Context, d *schema.Resourc
 	_ = d.Set("name", snapshot.Snapshot.Name)
 	_ = d.Set("created_at", snapshot.Snapshot.CreationDate.Format(time.RFC3339))
 	_ = d.Set("type", snapshot.Snapshot.VolumeType.String())
-	_ = d.Set("tags", snapshot.Snapshot.Tags)
+	setTags(d, meta, snapshot.Snapshot.Tags)
+	_ = d.Set("zone", snapshot.Snapshot.Zone)
+	_ = d.Set("project_id", snapshot.Snapshot.Project)
+	// The base volume may have been deleted since the snapshot was taken, only set it when importing.
+	if _, exists := d.GetOk("volume_id"); !exists && snapshot.Snapshot.BaseVolume != nil {
+		_ = d.Set("volume_id", newZonedIDString(zone, snapshot.Snapshot.BaseVolume.ID))
+	}
 
 	return nil
 }
@@ -192,9 +203,9 @@ func resourceScalewayInstanceSnapshotUpdate(ctx context.Context, d *schema.Resou
 		Tags:       scw.StringsPtr([]string{}),
 	}
 
-	tags := expandStrings(d.Get("tags"))
-	if d.HasChange("tags") && len(tags) > 0 {
-		req.Tags = scw.StringsPtr(expandStrings(d.Get("tags")))
+	tags := expandTagsAll(d, meta)
+	if d.HasChanges("tags", "tags_all") && len(tags) > 0 {
+		req.Tags = scw.StringsPtr(tags)
 	}
 
 	_, err = instanceAPI.UpdateSnapshot(req, scw.WithContext(ctx))
@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/scaleway/scaleway-sdk-go/api/instance/v1"
@@ -18,7 +19,7 @@ func resourceScalewayInstanceVolume() *schema.Resource {
 		UpdateContext: resourceScalewayInstanceVolumeUpdate,
 		DeleteContext: resourceScalewayInstanceVolumeDelete,
 		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
+			StateContext: importStateByName(instanceVolumeIDByName),
 		},
 		Timeouts: &schema.ResourceTimeout{
 			Create:  schema.DefaultTimeout(defaultInstanceVolumeDeleteTimeout),
@@ -70,6 +71,18 @@ func resourceScalewayInstanceVolume() *schema.Resource {
 				Computed:    true,
 				Description: "The server associated with this volume",
 			},
+			"final_snapshot": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Snapshot the volume before destroying it",
+			},
+			"final_snapshot_ids": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The snapshot of the volume taken when it was destroyed",
+			},
 			"tags": {
 				Type: schema.TypeList,
 				Elem: &schema.Schema{
@@ -78,11 +91,16 @@ func resourceScalewayInstanceVolume() *schema.Resource {
 				Optional:    true,
 				Description: "The tags associated with the volume",
 			},
+			"tags_all":        tagsAllSchema(),
 			"organization_id": organizationIDSchema(),
 			"project_id":      projectIDSchema(),
 			"zone":            zoneSchema(),
 		},
-		CustomizeDiff: customizeDiffLocalityCheck("from_volume_id", "from_snapshot_id"),
+		CustomizeDiff: customdiff.Sequence(
+			customizeDiffLocalityCheck("from_volume_id", "from_snapshot_id"),
+			customizeDiffTagsAll,
+			customizeDiffInstanceVolumeSize,
+		),
 	}
 }
 
@@ -98,7 +116,7 @@ func resourceScalewayInstanceVolumeCreate(ctx context.Context, d *schema.Resourc
 		VolumeType: instance.VolumeVolumeType(d.Get("type").(string)),
 		Project:    expandStringPtr(d.Get("project_id")),
 	}
-	tags := expandStrings(d.Get("tags"))
+	tags := expandTagsAll(d, meta)
 	if len(tags) > 0 {
 		createVolumeRequest.Tags = tags
 	}
@@ -159,7 +177,7 @@ func resourceScalewayInstanceVolumeRead(ctx context.Context, d *schema.ResourceD
 	_ = d.Set("project_id", res.Volume.Project)
 	_ = d.Set("zone", string(zone))
 	_ = d.Set("type", res.Volume.VolumeType.String())
-	_ = d.Set("tags", res.Volume.Tags)
+	setTags(d, meta, res.Volume.Tags)
 
 	_, fromVolume := d.GetOk("from_volume_id")
 	_, fromSnapshot := d.GetOk("from_snapshot_id")
@@ -193,34 +211,13 @@ func resourceScalewayInstanceVolumeUpdate(ctx context.Context, d *schema.Resourc
 		req.Name = &newName
 	}
 
-	tags := expandStrings(d.Get("tags"))
-	if d.HasChange("tags") && len(tags) > 0 {
-		req.Tags = scw.StringsPtr(expandStrings(d.Get("tags")))
+	tags := expandTagsAll(d, meta)
+	if d.HasChanges("tags", "tags_all") && len(tags) > 0 {
+		req.Tags = scw.StringsPtr(tags)
 	}
 
 	if d.HasChange("size_in_gb") {
-		if d.Get("type") != instance.VolumeVolumeTypeBSSD.String() {
-			return diag.FromErr(fmt.Errorf("only block volume can be resized"))
-		}
-		if oldSize, newSize := d.GetChange("size_in_gb"); oldSize.(int) > newSize.(int) {
-			return diag.FromErr(fmt.Errorf("block volumes cannot be resized down"))
-		}
-
-		_, err = waitForInstanceVolume(ctx, instanceAPI, zone, id, d.Timeout(schema.TimeoutUpdate))
-		if err != nil {
-			return diag.FromErr(err)
-		}
-
-		volumeSizeInBytes := scw.Size(uint64(d.Get("size_in_gb").(int)) * gb)
-		_, err = instanceAPI.UpdateVolume(&instance.UpdateVolumeRequest{
-			VolumeID: id,
-			Zone:     zone,
-			Size:     &volumeSizeInBytes,
-		}, scw.WithContext(ctx))
-		if err != nil {
-			return diag.FromErr(fmt.Errorf("couldn't resize volume: %s", err))
-		}
-		_, err = waitForInstanceVolume(ctx, instanceAPI, zone, id, d.Timeout(schema.TimeoutUpdate))
+		err = resizeInstanceVolume(ctx, instanceAPI, zone, id, d.Get("size_in_gb").(int), d.Timeout(schema.TimeoutUpdate))
 		if err != nil {
 			return diag.FromErr(err)
 		}
@@ -257,6 +254,19 @@ func resourceScalewayInstanceVolumeDelete(ctx context.Context, d *schema.Resourc
 		return diag.FromErr(fmt.Errorf("volume is still attached to a server"))
 	}
 
+	var warnings diag.Diagnostics
+
+	if d.Get("final_snapshot").(bool) {
+		snapshotID, err := createInstanceFinalSnapshot(ctx, instanceAPI, zone, volume.ID, volume.Name, volume.Name, volume.Project, d.Timeout(schema.TimeoutDelete))
+		if snapshotID != "" {
+			_ = d.Set("final_snapshot_ids", []string{snapshotID})
+		}
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		warnings = append(warnings, finalSnapshotWarning(volume.Name, []string{snapshotID}))
+	}
+
 	deleteRequest := &instance.DeleteVolumeRequest{
 		Zone:     zone,
 		VolumeID: id,
@@ -267,5 +277,5 @@ func resourceScalewayInstanceVolumeDelete(ctx context.Context, d *schema.Resourc
 		return diag.FromErr(err)
 	}
 
-	return nil
+	return warnings
 }
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/scaleway/scaleway-sdk-go/api/k8s/v1"
@@ -20,7 +21,7 @@ func resourceScalewayK8SCluster() *schema.Resource {
 		UpdateContext: resourceScalewayK8SClusterUpdate,
 		DeleteContext: resourceScalewayK8SClusterDelete,
 		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
+			StateContext: importStateByName(k8sClusterIDByName),
 		},
 		Timeouts: &schema.ResourceTimeout{
 			Create:  schema.DefaultTimeout(defaultK8SClusterTimeout),
@@ -75,6 +76,7 @@ func resourceScalewayK8SCluster() *schema.Resource {
 				Optional:    true,
 				Description: "The tags associated with the cluster",
 			},
+			"tags_all": tagsAllSchema(),
 			"autoscaler_config": {
 				Type:        schema.TypeList,
 				MaxItems:    1,
@@ -157,6 +159,18 @@ func resourceScalewayK8SCluster() *schema.Resource {
 				Required:    true,
 				Description: "Delete additional resources like block volumes and loadbalancers on cluster deletion",
 			},
+			"upgrade_pools": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Upgrade the pools after each minor version the cluster is upgraded to, one pool at a time",
+			},
+			"persist_kubeconfig": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Store the admin kubeconfig in the kubeconfig attribute, use the scaleway_k8s_cluster_credentials data source otherwise",
+			},
 			"region":          regionSchema(),
 			"organization_id": organizationIDSchema(),
 			"project_id":      projectIDSchema(),
@@ -222,18 +236,21 @@ func resourceScalewayK8SCluster() *schema.Resource {
 				Description: "The status of the cluster",
 			},
 		},
-		CustomizeDiff: func(ctx context.Context, diff *schema.ResourceDiff, i interface{}) error {
-			autoUpgradeEnable, okAutoUpgradeEnable := diff.GetOkExists("auto_upgrade.0.enable")
+		CustomizeDiff: customdiff.Sequence(
+			func(ctx context.Context, diff *schema.ResourceDiff, i interface{}) error {
+				autoUpgradeEnable, okAutoUpgradeEnable := diff.GetOkExists("auto_upgrade.0.enable")
 
-			version := diff.Get("version").(string)
-			versionIsOnlyMinor := len(strings.Split(version, ".")) == 2
+				version := diff.Get("version").(string)
+				versionIsOnlyMinor := len(strings.Split(version, ".")) == 2
 
-			if okAutoUpgradeEnable && versionIsOnlyMinor != autoUpgradeEnable.(bool) {
-				return fmt.Errorf("minor version x.y must be used with auto upgrade enabled")
-			}
+				if okAutoUpgradeEnable && versionIsOnlyMinor != autoUpgradeEnable.(bool) {
+					return fmt.Errorf("minor version x.y must be used with auto upgrade enabled")
+				}
 
-			return nil
-		},
+				return nil
+			},
+			customizeDiffTagsAll,
+		),
 	}
 }
 
@@ -265,7 +282,7 @@ func resourceScalewayK8SClusterCreate(ctx context.Context, d *schema.ResourceDat
 		Type:              clusterType.(string),
 		Description:       description.(string),
 		Cni:               k8s.CNI(d.Get("cni").(string)),
-		Tags:              expandStrings(d.Get("tags")),
+		Tags:              expandTagsAll(d, meta),
 		FeatureGates:      expandStrings(d.Get("feature_gates")),
 		AdmissionPlugins:  expandStrings(d.Get("admission_plugins")),
 		ApiserverCertSans: expandStrings(d.Get("apiserver_cert_sans")),
@@ -431,12 +448,22 @@ func resourceScalewayK8SClusterRead(ctx context.Context, d *schema.ResourceData,
 
 	_ = d.Set("region", string(region))
 	_ = d.Set("name", cluster.Name)
+	// delete_additional_resources is not returned by the API, imported clusters keep their additional resources on deletion.
+	if _, ok := getBool(d, "delete_additional_resources").(bool); !ok {
+		_ = d.Set("delete_additional_resources", false)
+	}
+	// upgrade_pools is not returned by the API either, imported clusters upgrade their pools.
+	upgradePools := true
+	if rawUpgradePools, ok := getBool(d, "upgrade_pools").(bool); ok {
+		upgradePools = rawUpgradePools
+	}
+	_ = d.Set("upgrade_pools", upgradePools)
 	_ = d.Set("type", cluster.Type)
 	_ = d.Set("organization_id", cluster.OrganizationID)
 	_ = d.Set("project_id", cluster.ProjectID)
 	_ = d.Set("description", cluster.Description)
 	_ = d.Set("cni", cluster.Cni)
-	_ = d.Set("tags", cluster.Tags)
+	setTags(d, meta, cluster.Tags)
 	_ = d.Set("apiserver_cert_sans", cluster.ApiserverCertSans)
 	_ = d.Set("created_at", cluster.CreatedAt.Format(time.RFC3339))
 	_ = d.Set("updated_at", cluster.UpdatedAt.Format(time.RFC3339))
@@ -462,6 +489,17 @@ func resourceScalewayK8SClusterRead(ctx context.Context, d *schema.ResourceData,
 	_ = d.Set("open_id_connect_config", clusterOpenIDConnectConfigFlatten(cluster))
 	_ = d.Set("auto_upgrade", clusterAutoUpgradeFlatten(cluster))
 
+	// persist_kubeconfig is not returned by the API, it is missing from the state of imported clusters.
+	persistKubeconfig := true
+	if rawPersistKubeconfig, ok := getBool(d, "persist_kubeconfig").(bool); ok {
+		persistKubeconfig = rawPersistKubeconfig
+	}
+	_ = d.Set("persist_kubeconfig", persistKubeconfig)
+	if !persistKubeconfig {
+		_ = d.Set("kubeconfig", nil)
+		return nil
+	}
+
 	////
 	// Read kubeconfig
 	////
@@ -524,8 +562,8 @@ func resourceScalewayK8SClusterUpdate(ctx context.Context, d *schema.ResourceDat
 		updateRequest.Description = expandStringPtr(d.Get("description"))
 	}
 
-	if d.HasChange("tags") {
-		updateRequest.Tags = expandUpdatedStringsPtr(d.Get("tags"))
+	if d.HasChanges("tags", "tags_all") {
+		updateRequest.Tags = expandUpdatedTagsAll(d, meta)
 	}
 
 	if d.HasChange("apiserver_cert_sans") {
@@ -567,6 +605,7 @@ func resourceScalewayK8SClusterUpdate(ctx context.Context, d *schema.ResourceDat
 		}
 	}
 
+	currentVersion := ""
 	if d.HasChange("version") {
 		// maybe it's a change from minor to patch or patch to minor
 		// we need to check the current version
@@ -578,6 +617,7 @@ func resourceScalewayK8SClusterUpdate(ctx context.Context, d *schema.ResourceDat
 		if err != nil {
 			return diag.FromErr(err)
 		}
+		currentVersion = clusterResp.Version
 
 		if clusterResp.Version == version {
 			// no upgrades if same version
@@ -680,25 +720,21 @@ func resourceScalewayK8SClusterUpdate(ctx context.Context, d *schema.ResourceDat
 	////
 	// Upgrade if needed
 	////
+	var diags diag.Diagnostics
 	if canUpgrade {
-		upgradeRequest := &k8s.UpgradeClusterRequest{
-			Region:       region,
-			ClusterID:    clusterID,
-			Version:      version,
-			UpgradePools: true,
-		}
-		_, err = k8sAPI.UpgradeCluster(upgradeRequest)
+		// the API only upgrades to the next minor version, intermediate ones are walked one at a time
+		path, err := k8sGetUpgradePath(ctx, k8sAPI, region, currentVersion, version)
 		if err != nil {
 			return diag.FromErr(err)
 		}
 
-		_, err = waitK8SCluster(ctx, k8sAPI, region, clusterID, d.Timeout(schema.TimeoutUpdate))
-		if err != nil {
-			return diag.FromErr(err)
+		diags = upgradeK8SCluster(ctx, k8sAPI, region, clusterID, path, d.Get("upgrade_pools").(bool), d.Timeout(schema.TimeoutUpdate))
+		if diags.HasError() {
+			return diags
 		}
 	}
 
-	return resourceScalewayK8SClusterRead(ctx, d, meta)
+	return append(diags, resourceScalewayK8SClusterRead(ctx, d, meta)...)
 }
 
 func resourceScalewayK8SClusterDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
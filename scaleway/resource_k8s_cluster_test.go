@@ -1,13 +1,19 @@
 package scaleway
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 	"github.com/scaleway/scaleway-sdk-go/api/k8s/v1"
 	"github.com/scaleway/scaleway-sdk-go/scw"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func init() {
@@ -592,3 +598,62 @@ resource "scaleway_k8s_pool" "multicloud" {
 }
 `, version)
 }
+
+func TestFakeAPI_K8SClusterMultiStepUpgrade(t *testing.T) {
+	tt := NewFakeAPITestTools(t)
+	defer tt.Cleanup()
+	ctx := context.Background()
+	k8sAPI := k8s.NewAPI(tt.Meta.scwClient)
+
+	dataSource := dataSourceScalewayK8SVersion()
+	d := schema.TestResourceDataRaw(t, dataSource.Schema, map[string]interface{}{"name": "1.24.11"})
+	diags := dataSource.ReadContext(ctx, d, tt.Meta)
+	require.False(t, diags.HasError(), diags)
+	assert.Equal(t, []interface{}{"1.25.7"}, d.Get("available_upgrades"))
+
+	cluster, err := k8sAPI.CreateCluster(&k8s.CreateClusterRequest{
+		Region:  scw.RegionFrPar,
+		Name:    "cluster",
+		Version: "1.24.11",
+		Cni:     k8s.CNICilium,
+		Pools: []*k8s.CreateClusterRequestPoolConfig{
+			{Name: "default", NodeType: "DEV1-M", Size: 1},
+			{Name: "gpu", NodeType: "GPU-3070-S", Size: 1},
+		},
+	})
+	require.NoError(t, err)
+
+	// The API rejects upgrades skipping a minor version.
+	_, err = k8sAPI.UpgradeCluster(&k8s.UpgradeClusterRequest{Region: scw.RegionFrPar, ClusterID: cluster.ID, Version: "1.26.2"})
+	require.Error(t, err)
+
+	resource := resourceScalewayK8SCluster()
+	d = resource.Data(nil)
+	d.SetId(newRegionalIDString(scw.RegionFrPar, cluster.ID))
+	diags = resource.ReadContext(ctx, d, tt.Meta)
+	require.False(t, diags.HasError(), diags)
+	assert.True(t, d.Get("upgrade_pools").(bool))
+
+	diff, err := testPlanResource(t, resource, d.State(), tt.Meta, map[string]cty.Value{
+		"name":                        cty.StringVal("cluster"),
+		"version":                     cty.StringVal("1.26.2"),
+		"cni":                         cty.StringVal("cilium"),
+		"delete_additional_resources": cty.False,
+	})
+	require.NoError(t, err)
+	state, diags := resource.Apply(ctx, d.State(), diff, tt.Meta)
+	require.False(t, diags.HasError(), diags)
+	assert.Equal(t, "1.26.2", state.Attributes["version"])
+	require.Len(t, diags, 2)
+	assert.Equal(t, diag.Warning, diags[0].Severity)
+	assert.Contains(t, diags[0].Summary, "1.25.7")
+	assert.Contains(t, diags[0].Detail, "default, gpu")
+	assert.Contains(t, diags[1].Summary, "1.26.2")
+
+	pools, err := k8sAPI.ListPools(&k8s.ListPoolsRequest{Region: scw.RegionFrPar, ClusterID: cluster.ID})
+	require.NoError(t, err)
+	require.Len(t, pools.Pools, 2)
+	for _, pool := range pools.Pools {
+		assert.Equal(t, "1.26.2", pool.Version)
+	}
+}
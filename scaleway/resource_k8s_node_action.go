@@ -0,0 +1,166 @@
+package scaleway
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/scaleway/scaleway-sdk-go/api/k8s/v1"
+	"github.com/scaleway/scaleway-sdk-go/scw"
+)
+
+const (
+	k8sNodeActionReboot  = "reboot"
+	k8sNodeActionReplace = "replace"
+)
+
+func resourceScalewayK8SNodeAction() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceScalewayK8SNodeActionCreate,
+		ReadContext:   resourceScalewayK8SNodeActionRead,
+		DeleteContext: resourceScalewayK8SNodeActionDelete,
+		Timeouts: &schema.ResourceTimeout{
+			Create:  schema.DefaultTimeout(defaultK8SPoolTimeout),
+			Default: schema.DefaultTimeout(defaultK8SPoolTimeout),
+		},
+		Schema: map[string]*schema.Schema{
+			"cluster_id": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				Description:      "The ID of the cluster of the node",
+				ValidateFunc:     validationUUIDorUUIDWithLocality(),
+				DiffSuppressFunc: diffSuppressFuncLocality,
+			},
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The name of the node",
+			},
+			"action": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "The action applied to the node (reboot or replace)",
+				ValidateFunc: validation.StringInSlice([]string{k8sNodeActionReboot, k8sNodeActionReplace}, false),
+			},
+			"triggers": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "Arbitrary values applying the action again when they change",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"node_id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The ID of the node",
+			},
+			"pool_id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The ID of the pool of the node",
+			},
+			"status": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The status of the node",
+			},
+			"region": regionSchema(),
+		},
+	}
+}
+
+// findK8SNodeByName returns the node of a cluster with the given name, node names being unique in a cluster.
+func findK8SNodeByName(ctx context.Context, k8sAPI *k8s.API, region scw.Region, clusterID, name string) (*k8s.Node, error) {
+	res, err := k8sAPI.ListNodes(&k8s.ListNodesRequest{
+		Region:    region,
+		ClusterID: clusterID,
+		Name:      &name,
+	}, scw.WithAllPages(), scw.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	for _, node := range res.Nodes {
+		if node.Name == name {
+			return node, nil
+		}
+	}
+	return nil, fmt.Errorf("no node found with the name %s in cluster %s", name, clusterID)
+}
+
+func resourceScalewayK8SNodeActionCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	k8sAPI, region, err := k8sAPIWithRegion(d, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	node, err := findK8SNodeByName(ctx, k8sAPI, region, expandID(d.Get("cluster_id")), d.Get("name").(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	switch d.Get("action").(string) {
+	case k8sNodeActionReboot:
+		_, err = k8sAPI.RebootNode(&k8s.RebootNodeRequest{
+			Region: region,
+			NodeID: node.ID,
+		}, scw.WithContext(ctx))
+	case k8sNodeActionReplace:
+		_, err = k8sAPI.ReplaceNode(&k8s.ReplaceNodeRequest{
+			Region: region,
+			NodeID: node.ID,
+		}, scw.WithContext(ctx))
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(newRegionalIDString(region, node.ID))
+
+	_, err = waitK8SNodeReady(ctx, k8sAPI, region, node.ID, d.Timeout(schema.TimeoutCreate))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	return resourceScalewayK8SNodeActionRead(ctx, d, meta)
+}
+
+func resourceScalewayK8SNodeActionRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	k8sAPI, region, nodeID, err := k8sAPIWithRegionAndID(meta, d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	node, err := k8sAPI.GetNode(&k8s.GetNodeRequest{
+		Region: region,
+		NodeID: nodeID,
+	}, scw.WithContext(ctx))
+	if err != nil {
+		// the action was applied: it is kept when its node is deleted, e.g. by the replacement of its pool,
+		// so that it is not applied again to a node with the same name
+		if is404Error(err) {
+			return nil
+		}
+		return diag.FromErr(err)
+	}
+
+	_ = d.Set("cluster_id", newRegionalIDString(region, node.ClusterID))
+	_ = d.Set("name", node.Name)
+	_ = d.Set("node_id", newRegionalIDString(region, node.ID))
+	_ = d.Set("pool_id", newRegionalIDString(region, node.PoolID))
+	_ = d.Set("status", node.Status.String())
+	_ = d.Set("region", region)
+
+	return nil
+}
+
+// resourceScalewayK8SNodeActionDelete only removes the action from the state, the node is managed by its pool.
+func resourceScalewayK8SNodeActionDelete(_ context.Context, _ *schema.ResourceData, _ interface{}) diag.Diagnostics {
+	return nil
+}
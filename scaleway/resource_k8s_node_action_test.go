@@ -0,0 +1,164 @@
+package scaleway
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/scaleway/scaleway-sdk-go/api/k8s/v1"
+	"github.com/scaleway/scaleway-sdk-go/scw"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccScalewayK8SNodeAction_Basic(t *testing.T) {
+	tt := NewTestTools(t)
+	defer tt.Cleanup()
+
+	config := func(trigger string) string {
+		return fmt.Sprintf(`
+			data "scaleway_k8s_version" "latest" {
+				name = "latest"
+			}
+
+			resource "scaleway_k8s_cluster" "main" {
+				name                        = "tf-tests-k8s-node-action"
+				version                     = data.scaleway_k8s_version.latest.name
+				cni                         = "cilium"
+				delete_additional_resources = true
+				tags                        = ["terraform-test", "scaleway_k8s_node_action", "basic"]
+			}
+
+			resource "scaleway_k8s_pool" "main" {
+				cluster_id          = scaleway_k8s_cluster.main.id
+				name                = "node-action"
+				node_type           = "gp1_xs"
+				size                = 1
+				wait_for_pool_ready = true
+			}
+
+			resource "scaleway_k8s_node_action" "reboot" {
+				cluster_id = scaleway_k8s_cluster.main.id
+				name       = scaleway_k8s_pool.main.nodes[0].name
+				action     = "reboot"
+				triggers = {
+					maintenance = "%s"
+				}
+			}`, trigger)
+	}
+	nodeActionID := ""
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: tt.ProviderFactories,
+		CheckDestroy:      testAccCheckScalewayK8SClusterDestroy(tt),
+		Steps: []resource.TestStep{
+			{
+				Config: config("first"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckScalewayK8SPoolExists(tt, "scaleway_k8s_pool.main"),
+					resource.TestCheckResourceAttrPair("scaleway_k8s_node_action.reboot", "name", "scaleway_k8s_pool.main", "nodes.0.name"),
+					resource.TestCheckResourceAttrPair("scaleway_k8s_node_action.reboot", "pool_id", "scaleway_k8s_pool.main", "id"),
+					resource.TestCheckResourceAttrPair("scaleway_k8s_node_action.reboot", "node_id", "scaleway_k8s_node_action.reboot", "id"),
+					resource.TestCheckResourceAttr("scaleway_k8s_node_action.reboot", "status", k8s.NodeStatusReady.String()),
+					func(s *terraform.State) error {
+						nodeActionID = s.RootModule().Resources["scaleway_k8s_node_action.reboot"].Primary.ID
+						return nil
+					},
+				),
+			},
+			{
+				Config: config("second"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("scaleway_k8s_node_action.reboot", "triggers.maintenance", "second"),
+					resource.TestCheckResourceAttr("scaleway_k8s_node_action.reboot", "status", k8s.NodeStatusReady.String()),
+					func(s *terraform.State) error {
+						if id := s.RootModule().Resources["scaleway_k8s_node_action.reboot"].Primary.ID; id != nodeActionID {
+							return fmt.Errorf("node action should still target node %s, got %s", nodeActionID, id)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func TestFakeAPI_K8SNodeAction(t *testing.T) {
+	tt := NewFakeAPITestTools(t)
+	defer tt.Cleanup()
+	ctx := context.Background()
+	k8sAPI := k8s.NewAPI(tt.Meta.scwClient)
+
+	cluster, err := k8sAPI.CreateCluster(&k8s.CreateClusterRequest{
+		Region:  scw.RegionFrPar,
+		Name:    "cluster",
+		Version: "1.26.2",
+		Cni:     k8s.CNICilium,
+		Pools: []*k8s.CreateClusterRequestPoolConfig{
+			{Name: "default", NodeType: "DEV1-M", Size: 2},
+			{Name: "gpu", NodeType: "GPU-3070-S", Size: 1},
+		},
+	})
+	require.NoError(t, err)
+	clusterID := newRegionalIDString(scw.RegionFrPar, cluster.ID)
+	pools, err := k8sAPI.ListPools(&k8s.ListPoolsRequest{Region: scw.RegionFrPar, ClusterID: cluster.ID, Name: scw.StringPtr("gpu")})
+	require.NoError(t, err)
+	require.Len(t, pools.Pools, 1)
+	gpuPoolID := newRegionalIDString(scw.RegionFrPar, pools.Pools[0].ID)
+
+	nodes, err := k8sAPI.ListNodes(&k8s.ListNodesRequest{Region: scw.RegionFrPar, ClusterID: cluster.ID, PoolID: &pools.Pools[0].ID})
+	require.NoError(t, err)
+	require.Len(t, nodes.Nodes, 1)
+	gpuNodeName := nodes.Nodes[0].Name
+
+	resource := resourceScalewayK8SNodeAction()
+	for _, action := range []string{"reboot", "replace"} {
+		d := testFakeAPILifecycle(t, tt, resource, map[string]interface{}{
+			"cluster_id": clusterID,
+			"name":       gpuNodeName,
+			"action":     action,
+		})
+		assert.Equal(t, gpuPoolID, d.Get("pool_id"))
+		assert.Equal(t, "ready", d.Get("status"))
+	}
+
+	// A cluster ID without region does not apply the action again.
+	config := map[string]cty.Value{
+		"cluster_id": cty.StringVal(cluster.ID),
+		"name":       cty.StringVal(gpuNodeName),
+		"action":     cty.StringVal("reboot"),
+	}
+	diff, err := testPlanResource(t, resource, nil, tt.Meta, config)
+	require.NoError(t, err)
+	state, diags := resource.Apply(ctx, nil, diff, tt.Meta)
+	require.False(t, diags.HasError(), diags)
+	assert.Equal(t, clusterID, state.Attributes["cluster_id"])
+	diff, err = testPlanResource(t, resource, state, tt.Meta, config)
+	require.NoError(t, err)
+	assert.Nil(t, diff)
+
+	// The action is kept when its node is deleted.
+	_, err = k8sAPI.DeletePool(&k8s.DeletePoolRequest{Region: scw.RegionFrPar, PoolID: pools.Pools[0].ID})
+	require.NoError(t, err)
+	_, err = k8sAPI.GetNode(&k8s.GetNodeRequest{Region: scw.RegionFrPar, NodeID: expandID(state.ID)})
+	require.True(t, is404Error(err), err)
+	d := resource.Data(state)
+	diags = resource.ReadContext(ctx, d, tt.Meta)
+	require.False(t, diags.HasError(), diags)
+	assert.Equal(t, state.ID, d.Id())
+	assert.Equal(t, gpuPoolID, d.Get("pool_id"))
+
+	d = schema.TestResourceDataRaw(t, resource.Schema, map[string]interface{}{
+		"cluster_id": clusterID,
+		"name":       "scw-unknown",
+		"action":     "reboot",
+	})
+	diags = resource.CreateContext(ctx, d, tt.Meta)
+	require.True(t, diags.HasError())
+	assert.Contains(t, diags[0].Summary, "no node found with the name scw-unknown")
+}
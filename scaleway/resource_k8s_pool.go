@@ -19,7 +19,7 @@ func resourceScalewayK8SPool() *schema.Resource {
 		DeleteContext: resourceScalewayK8SPoolDelete,
 		CustomizeDiff: resourceScalewayK8SPoolCustomDiff,
 		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
+			StateContext: importStateByName(k8sPoolIDByName),
 		},
 		Timeouts: &schema.ResourceTimeout{
 			Create:  schema.DefaultTimeout(defaultK8SPoolTimeout),
@@ -43,7 +43,6 @@ func resourceScalewayK8SPool() *schema.Resource {
 			"node_type": {
 				Type:             schema.TypeString,
 				Required:         true,
-				ForceNew:         true,
 				Description:      "Server type of the pool servers",
 				DiffSuppressFunc: diffSuppressFuncIgnoreCaseAndHyphen,
 			},
@@ -84,11 +83,51 @@ func resourceScalewayK8SPool() *schema.Resource {
 				Optional:    true,
 				Description: "The tags associated with the pool",
 			},
+			"tags_all": tagsAllSchema(),
+			"labels": {
+				Type: schema.TypeMap,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Optional:         true,
+				Description:      "The Kubernetes labels of the nodes of the pool",
+				ValidateDiagFunc: validateK8SPoolLabels(),
+			},
+			"taints": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Description: "The Kubernetes taints of the nodes of the pool",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"key": {
+							Type:         schema.TypeString,
+							Required:     true,
+							Description:  "The key of the taint",
+							ValidateFunc: validation.StringDoesNotContainAny("=:"),
+						},
+						"value": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Description:  "The value of the taint",
+							ValidateFunc: validation.StringDoesNotContainAny("=:"),
+						},
+						"effect": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "The effect of the taint (NoSchedule, PreferNoSchedule or NoExecute)",
+							ValidateFunc: validation.StringInSlice([]string{
+								"NoSchedule",
+								"PreferNoSchedule",
+								"NoExecute",
+							}, false),
+						},
+					},
+				},
+			},
 			"container_runtime": {
 				Type:        schema.TypeString,
 				Optional:    true,
 				Default:     k8s.RuntimeContainerd.String(),
-				ForceNew:    true,
 				Description: "Container runtime for the pool",
 				ValidateFunc: validation.StringInSlice([]string{
 					k8s.RuntimeDocker.String(),
@@ -105,7 +144,6 @@ func resourceScalewayK8SPool() *schema.Resource {
 			"placement_group_id": {
 				Type:        schema.TypeString,
 				Optional:    true,
-				ForceNew:    true,
 				Default:     nil,
 				Description: "ID of the placement group",
 			},
@@ -143,16 +181,27 @@ func resourceScalewayK8SPool() *schema.Resource {
 			"root_volume_type": {
 				Type:        schema.TypeString,
 				Optional:    true,
-				ForceNew:    true,
+				Computed:    true,
 				Description: "System volume type of the nodes composing the pool",
 				ValidateFunc: validation.StringInSlice([]string{
 					k8s.PoolVolumeTypeBSSD.String(),
 					k8s.PoolVolumeTypeLSSD.String(),
 				}, false),
 			},
+			"replacement_strategy": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     k8sPoolReplacementStrategyRecreate,
+				Description: "How the pool is replaced when node_type, container_runtime, root_volume_type or placement_group_id change",
+				ValidateFunc: validation.StringInSlice([]string{
+					k8sPoolReplacementStrategyRecreate,
+					k8sPoolReplacementStrategyBlueGreen,
+				}, false),
+			},
 			"root_volume_size_in_gb": {
 				Type:        schema.TypeInt,
 				Optional:    true,
+				Computed:    true,
 				ForceNew:    true,
 				Description: "The size of the system volume of the nodes in gigabyte",
 			},
@@ -179,6 +228,11 @@ func resourceScalewayK8SPool() *schema.Resource {
 				Computed:    true,
 				Description: "The actual size of the pool",
 			},
+			"pool_id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The ID of the pool, which changes when the pool is replaced by a blue/green replacement",
+			},
 			"nodes": {
 				Type:     schema.TypeList,
 				Computed: true,
@@ -226,6 +280,54 @@ func resourceScalewayK8SPoolCreate(ctx context.Context, d *schema.ResourceData,
 	////
 	// Create pool
 	////
+	req := expandK8SPoolCreateRequest(d, meta, region)
+
+	// check if the cluster is waiting for a pool
+	cluster, err := k8sAPI.GetCluster(&k8s.GetClusterRequest{
+		ClusterID: expandID(d.Get("cluster_id")),
+		Region:    region,
+	}, scw.WithContext(ctx))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	waitForCluster := false
+
+	if cluster.Status == k8s.ClusterStatusPoolRequired {
+		waitForCluster = true
+	} else if cluster.Status == k8s.ClusterStatusCreating {
+		_, err = waitK8SCluster(ctx, k8sAPI, region, cluster.ID, d.Timeout(schema.TimeoutCreate))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	res, err := k8sAPI.CreatePool(req, scw.WithContext(ctx))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(newRegionalIDString(region, res.ID))
+
+	if d.Get("wait_for_pool_ready").(bool) { // wait for the pool to be ready if specified (including all its nodes)
+		_, err = waitK8SPoolReady(ctx, k8sAPI, region, res.ID, d.Timeout(schema.TimeoutCreate))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	if waitForCluster {
+		_, err = waitK8SCluster(ctx, k8sAPI, region, cluster.ID, d.Timeout(schema.TimeoutCreate))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return resourceScalewayK8SPoolRead(ctx, d, meta)
+}
+
+// expandK8SPoolCreateRequest returns the request creating a pool matching the configuration
+func expandK8SPoolCreateRequest(d *schema.ResourceData, meta interface{}, region scw.Region) *k8s.CreatePoolRequest {
 	req := &k8s.CreatePoolRequest{
 		Region:      region,
 		ClusterID:   expandID(d.Get("cluster_id")),
@@ -234,7 +336,7 @@ func resourceScalewayK8SPoolCreate(ctx context.Context, d *schema.ResourceData,
 		Autoscaling: d.Get("autoscaling").(bool),
 		Autohealing: d.Get("autohealing").(bool),
 		Size:        uint32(d.Get("size").(int)),
-		Tags:        expandStrings(d.Get("tags")),
+		Tags:        append(expandTagsAll(d, meta), expandK8SPoolNodeTags(d)...),
 		Zone:        scw.Zone(d.Get("zone").(string)),
 		KubeletArgs: expandKubeletArgs(d.Get("kubelet_args")),
 	}
@@ -286,48 +388,7 @@ func resourceScalewayK8SPoolCreate(ctx context.Context, d *schema.ResourceData,
 		req.RootVolumeSize = &volumeSizeInBytes
 	}
 
-	// check if the cluster is waiting for a pool
-	cluster, err := k8sAPI.GetCluster(&k8s.GetClusterRequest{
-		ClusterID: expandID(d.Get("cluster_id")),
-		Region:    region,
-	}, scw.WithContext(ctx))
-	if err != nil {
-		return diag.FromErr(err)
-	}
-
-	waitForCluster := false
-
-	if cluster.Status == k8s.ClusterStatusPoolRequired {
-		waitForCluster = true
-	} else if cluster.Status == k8s.ClusterStatusCreating {
-		_, err = waitK8SCluster(ctx, k8sAPI, region, cluster.ID, d.Timeout(schema.TimeoutCreate))
-		if err != nil {
-			return diag.FromErr(err)
-		}
-	}
-
-	res, err := k8sAPI.CreatePool(req, scw.WithContext(ctx))
-	if err != nil {
-		return diag.FromErr(err)
-	}
-
-	d.SetId(newRegionalIDString(region, res.ID))
-
-	if d.Get("wait_for_pool_ready").(bool) { // wait for the pool to be ready if specified (including all its nodes)
-		_, err = waitK8SPoolReady(ctx, k8sAPI, region, res.ID, d.Timeout(schema.TimeoutCreate))
-		if err != nil {
-			return diag.FromErr(err)
-		}
-	}
-
-	if waitForCluster {
-		_, err = waitK8SCluster(ctx, k8sAPI, region, cluster.ID, d.Timeout(schema.TimeoutCreate))
-		if err != nil {
-			return diag.FromErr(err)
-		}
-	}
-
-	return resourceScalewayK8SPoolRead(ctx, d, meta)
+	return req
 }
 
 func resourceScalewayK8SPoolRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
@@ -359,7 +420,17 @@ func resourceScalewayK8SPoolRead(ctx context.Context, d *schema.ResourceData, me
 	}
 
 	_ = d.Set("cluster_id", newRegionalIDString(region, pool.ClusterID))
-	_ = d.Set("name", pool.Name)
+	_ = d.Set("pool_id", newRegionalIDString(region, pool.ID))
+	// pools created by a blue/green replacement keep the name of the pool they replaced
+	name := pool.Name
+	if matches := k8sPoolBlueGreenNameRegexp.FindStringSubmatch(pool.Name); matches != nil && matches[1] == d.Get("name").(string) {
+		name = matches[1]
+	}
+	_ = d.Set("name", name)
+	// replacement_strategy is not returned by the API, imported pools are recreated
+	if d.Get("replacement_strategy").(string) == "" {
+		_ = d.Set("replacement_strategy", k8sPoolReplacementStrategyRecreate)
+	}
 	_ = d.Set("node_type", pool.NodeType)
 	_ = d.Set("autoscaling", pool.Autoscaling)
 	_ = d.Set("autohealing", pool.Autohealing)
@@ -370,7 +441,10 @@ func resourceScalewayK8SPoolRead(ctx context.Context, d *schema.ResourceData, me
 	_ = d.Set("version", pool.Version)
 	_ = d.Set("min_size", int(pool.MinSize))
 	_ = d.Set("max_size", int(pool.MaxSize))
-	_ = d.Set("tags", pool.Tags)
+	tags, labels, taints := flattenK8SPoolTags(pool.Tags, expandStrings(d.Get("tags")))
+	setTags(d, meta, tags)
+	_ = d.Set("labels", labels)
+	_ = d.Set("taints", taints)
 	_ = d.Set("container_runtime", pool.ContainerRuntime)
 	_ = d.Set("created_at", pool.CreatedAt.Format(time.RFC3339))
 	_ = d.Set("updated_at", pool.UpdatedAt.Format(time.RFC3339))
@@ -378,7 +452,12 @@ func resourceScalewayK8SPoolRead(ctx context.Context, d *schema.ResourceData, me
 	_ = d.Set("status", pool.Status)
 	_ = d.Set("kubelet_args", flattenKubeletArgs(pool.KubeletArgs))
 	_ = d.Set("zone", pool.Zone)
+	_ = d.Set("region", region)
 	_ = d.Set("upgrade_policy", poolUpgradePolicyFlatten(pool))
+	_ = d.Set("root_volume_type", pool.RootVolumeType)
+	if pool.RootVolumeSize != nil {
+		_ = d.Set("root_volume_size_in_gb", int(uint64(*pool.RootVolumeSize)/gb))
+	}
 
 	if pool.PlacementGroupID != nil {
 		_ = d.Set("placement_group_id", newZonedID(pool.Zone, *pool.PlacementGroupID).String())
@@ -393,6 +472,19 @@ func resourceScalewayK8SPoolUpdate(ctx context.Context, d *schema.ResourceData,
 		return diag.FromErr(err)
 	}
 
+	// the changes of the other fields are applied by the creation of the new pool
+	if d.HasChanges(k8sPoolBlueGreenFields...) {
+		err = replaceK8SPoolBlueGreen(ctx, d, meta, k8sAPI, region, poolID)
+		if err != nil {
+			if d.Id() != newRegionalIDString(region, poolID) {
+				// the pool was replaced, only the deletion of the old one failed
+				return append(resourceScalewayK8SPoolRead(ctx, d, meta), diag.FromErr(err)...)
+			}
+			return diag.FromErr(err)
+		}
+		return resourceScalewayK8SPoolRead(ctx, d, meta)
+	}
+
 	////
 	// Update Pool
 	////
@@ -421,8 +513,9 @@ func resourceScalewayK8SPoolUpdate(ctx context.Context, d *schema.ResourceData,
 		updateRequest.Size = scw.Uint32Ptr(uint32(d.Get("size").(int)))
 	}
 
-	if d.HasChange("tags") {
-		updateRequest.Tags = expandUpdatedStringsPtr(d.Get("tags"))
+	if d.HasChanges("tags", "tags_all", "labels", "taints") {
+		tags := append(*expandUpdatedTagsAll(d, meta), expandK8SPoolNodeTags(d)...)
+		updateRequest.Tags = &tags
 	}
 
 	if d.HasChange("kubelet_args") {
@@ -481,12 +574,32 @@ func resourceScalewayK8SPoolDelete(ctx context.Context, d *schema.ResourceData,
 	return nil
 }
 
-func resourceScalewayK8SPoolCustomDiff(_ context.Context, diff *schema.ResourceDiff, _ interface{}) error {
+func resourceScalewayK8SPoolCustomDiff(ctx context.Context, diff *schema.ResourceDiff, meta interface{}) error {
 	if diff.HasChange("size") {
 		err := diff.SetNewComputed("nodes")
 		if err != nil {
 			return err
 		}
 	}
-	return nil
+	if diff.Id() != "" && diff.HasChanges(k8sPoolBlueGreenFields...) {
+		if diff.Get("replacement_strategy").(string) == k8sPoolReplacementStrategyBlueGreen {
+			// the pool is replaced in place by a new one
+			for _, field := range []string{"pool_id", "nodes", "created_at", "status"} {
+				err := diff.SetNewComputed(field)
+				if err != nil {
+					return err
+				}
+			}
+		} else {
+			for _, field := range k8sPoolBlueGreenFields {
+				if diff.HasChange(field) {
+					err := diff.ForceNew(field)
+					if err != nil {
+						return err
+					}
+				}
+			}
+		}
+	}
+	return customizeDiffTagsAll(ctx, diff, meta)
 }
@@ -1,12 +1,22 @@
 package scaleway
 
 import (
+	"context"
 	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
 	"testing"
 
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 	"github.com/scaleway/scaleway-sdk-go/api/k8s/v1"
+	"github.com/scaleway/scaleway-sdk-go/scw"
+	"github.com/scaleway/terraform-provider-scaleway/v2/internal/fakeapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestAccScalewayK8SCluster_PoolBasic(t *testing.T) {
@@ -659,3 +669,428 @@ func testAccCheckScalewayK8SPoolNodesOneOfIsDeleting(name string) resource.TestC
 		return fmt.Errorf("nodes status were not as expected: got %q for nodes.0 and %q for nodes.1", nodesZeroStatus, nodesOneStatus)
 	}
 }
+
+func TestAccScalewayK8SCluster_PoolBlueGreen(t *testing.T) {
+	tt := NewTestTools(t)
+	defer tt.Cleanup()
+
+	config := func(nodeType string) string {
+		return fmt.Sprintf(`
+			data "scaleway_k8s_version" "latest" {
+				name = "latest"
+			}
+
+			resource "scaleway_k8s_cluster" "main" {
+				name                        = "tf-tests-k8s-pool-blue-green"
+				version                     = data.scaleway_k8s_version.latest.name
+				cni                         = "cilium"
+				delete_additional_resources = true
+				tags                        = ["terraform-test", "scaleway_k8s_pool", "blue-green"]
+			}
+
+			resource "scaleway_k8s_pool" "main" {
+				cluster_id           = scaleway_k8s_cluster.main.id
+				name                 = "blue-green"
+				node_type            = "%s"
+				size                 = 1
+				replacement_strategy = "blue_green"
+				wait_for_pool_ready  = true
+			}`, nodeType)
+	}
+	poolID := ""
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: tt.ProviderFactories,
+		CheckDestroy:      testAccCheckScalewayK8SClusterDestroy(tt),
+		Steps: []resource.TestStep{
+			{
+				Config: config("gp1_xs"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckScalewayK8SPoolExists(tt, "scaleway_k8s_pool.main"),
+					resource.TestCheckResourceAttr("scaleway_k8s_pool.main", "node_type", "gp1_xs"),
+					resource.TestCheckResourceAttrPair("scaleway_k8s_pool.main", "pool_id", "scaleway_k8s_pool.main", "id"),
+					func(s *terraform.State) error {
+						poolID = s.RootModule().Resources["scaleway_k8s_pool.main"].Primary.ID
+						return nil
+					},
+				),
+			},
+			{
+				Config: config("gp1_s"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckScalewayK8SPoolExists(tt, "scaleway_k8s_pool.main"),
+					resource.TestCheckResourceAttr("scaleway_k8s_pool.main", "name", "blue-green"),
+					resource.TestCheckResourceAttr("scaleway_k8s_pool.main", "node_type", "gp1_s"),
+					resource.TestCheckResourceAttr("scaleway_k8s_pool.main", "nodes.#", "1"),
+					resource.TestCheckResourceAttrPair("scaleway_k8s_pool.main", "pool_id", "scaleway_k8s_pool.main", "id"),
+					func(s *terraform.State) error {
+						if s.RootModule().Resources["scaleway_k8s_pool.main"].Primary.ID == poolID {
+							return fmt.Errorf("pool %s was not replaced", poolID)
+						}
+						return nil
+					},
+					func(s *terraform.State) error {
+						k8sAPI, region, id, err := k8sAPIWithRegionAndID(tt.Meta, poolID)
+						if err != nil {
+							return err
+						}
+						_, err = k8sAPI.GetPool(&k8s.GetPoolRequest{Region: region, PoolID: id})
+						if !is404Error(err) {
+							return fmt.Errorf("replaced pool %s was not deleted: %v", poolID, err)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func TestFakeAPI_K8SPoolBlueGreenReplacement(t *testing.T) {
+	tt := NewFakeAPITestTools(t)
+	defer tt.Cleanup()
+	ctx := context.Background()
+	k8sAPI := k8s.NewAPI(tt.Meta.scwClient)
+
+	cluster, err := k8sAPI.CreateCluster(&k8s.CreateClusterRequest{
+		Region:  scw.RegionFrPar,
+		Name:    "cluster",
+		Version: "1.26.2",
+		Cni:     k8s.CNICilium,
+	})
+	require.NoError(t, err)
+
+	resource := resourceScalewayK8SPool()
+	config := map[string]cty.Value{
+		"cluster_id": cty.StringVal(newRegionalIDString(scw.RegionFrPar, cluster.ID)),
+		"name":       cty.StringVal("web"),
+		"node_type":  cty.StringVal("DEV1-M"),
+		"size":       cty.NumberIntVal(2),
+	}
+	diff, err := testPlanResource(t, resource, nil, tt.Meta, config)
+	require.NoError(t, err)
+	state, diags := resource.Apply(ctx, nil, diff, tt.Meta)
+	require.False(t, diags.HasError(), diags)
+	assert.Equal(t, "recreate", state.Attributes["replacement_strategy"])
+	oldPoolID := state.ID
+	oldNodes := []string{state.Attributes["nodes.0.name"], state.Attributes["nodes.1.name"]}
+
+	// The default strategy recreates the pool.
+	config["node_type"] = cty.StringVal("DEV1-L")
+	diff, err = testPlanResource(t, resource, state, tt.Meta, config)
+	require.NoError(t, err)
+	assert.True(t, diff.RequiresNew())
+
+	kubeRequest := func(method, path string, body interface{}, response interface{}) {
+		kubeClient, err := newK8SKubeClient(ctx, tt.Meta, k8sAPI, scw.RegionFrPar, cluster.ID)
+		require.NoError(t, err)
+		require.NoError(t, kubeClient.do(ctx, method, path, "application/json", body, response))
+	}
+	kubeRequest(http.MethodPost, "/api/v1/namespaces/default/pods", map[string]interface{}{
+		"metadata": map[string]interface{}{"name": "web"},
+		"spec":     map[string]interface{}{"nodeName": oldNodes[0]},
+	}, nil)
+	kubeRequest(http.MethodPost, "/api/v1/namespaces/kube-system/pods", map[string]interface{}{
+		"metadata": map[string]interface{}{"name": "cilium", "ownerReferences": []interface{}{map[string]interface{}{"kind": "DaemonSet"}}},
+		"spec":     map[string]interface{}{"nodeName": oldNodes[0]},
+	}, nil)
+
+	config["replacement_strategy"] = cty.StringVal("blue_green")
+	diff, err = testPlanResource(t, resource, state, tt.Meta, config)
+	require.NoError(t, err)
+	assert.False(t, diff.RequiresNew())
+	// The resources referencing pool_id plan with the ID of the new pool.
+	assert.True(t, diff.Attributes["pool_id"].NewComputed)
+	state, diags = resource.Apply(ctx, state, diff, tt.Meta)
+	require.False(t, diags.HasError(), diags)
+	assert.NotEqual(t, oldPoolID, state.ID)
+	assert.Equal(t, state.ID, state.Attributes["pool_id"])
+	assert.Equal(t, "web", state.Attributes["name"])
+	assert.Equal(t, "DEV1-L", state.Attributes["node_type"])
+	assert.Equal(t, "2", state.Attributes["nodes.#"])
+	assert.NotContains(t, oldNodes, state.Attributes["nodes.0.name"])
+
+	_, err = k8sAPI.GetPool(&k8s.GetPoolRequest{Region: scw.RegionFrPar, PoolID: expandID(oldPoolID)})
+	assert.True(t, is404Error(err))
+	pool, err := k8sAPI.GetPool(&k8s.GetPoolRequest{Region: scw.RegionFrPar, PoolID: expandID(state.ID)})
+	require.NoError(t, err)
+	assert.Regexp(t, `^web-bg[0-9a-z]+$`, pool.Name)
+
+	node := struct {
+		Spec struct {
+			Unschedulable bool `json:"unschedulable"`
+		} `json:"spec"`
+	}{}
+	kubeRequest(http.MethodGet, "/api/v1/nodes/"+oldNodes[1], nil, &node)
+	assert.True(t, node.Spec.Unschedulable)
+	pods := struct {
+		Items []*k8sKubePod `json:"items"`
+	}{}
+	kubeRequest(http.MethodGet, "/api/v1/pods", nil, &pods)
+	require.Len(t, pods.Items, 1)
+	assert.Equal(t, "cilium", pods.Items[0].Metadata.Name)
+
+	// The configuration matches the new pool.
+	diff, err = testPlanResource(t, resource, state, tt.Meta, config)
+	require.NoError(t, err)
+	assert.Nil(t, diff)
+
+	// Once the old pool is drained, the new pool is kept even if the old one cannot be deleted.
+	drainedPoolID := state.ID
+	_, err = k8sAPI.UpdatePool(&k8s.UpdatePoolRequest{Region: scw.RegionFrPar, PoolID: expandID(drainedPoolID), Tags: scw.StringsPtr([]string{fakeapi.DeletionDeniedTag})})
+	require.NoError(t, err)
+	config["node_type"] = cty.StringVal("DEV1-XL")
+	diff, err = testPlanResource(t, resource, state, tt.Meta, config)
+	require.NoError(t, err)
+	state, diags = resource.Apply(ctx, state, diff, tt.Meta)
+	require.True(t, diags.HasError())
+	assert.Contains(t, diags[0].Summary, "pool "+drainedPoolID+" was replaced by pool "+state.ID+" but could not be deleted, it must be removed")
+	assert.NotEqual(t, drainedPoolID, state.ID)
+	assert.Equal(t, state.ID, state.Attributes["pool_id"])
+	assert.Equal(t, "DEV1-XL", state.Attributes["node_type"])
+	pools, err := k8sAPI.ListPools(&k8s.ListPoolsRequest{Region: scw.RegionFrPar, ClusterID: cluster.ID}, scw.WithAllPages())
+	require.NoError(t, err)
+	assert.Len(t, pools.Pools, 2)
+	_, err = k8sAPI.UpdatePool(&k8s.UpdatePoolRequest{Region: scw.RegionFrPar, PoolID: expandID(drainedPoolID), Tags: scw.StringsPtr([]string{})})
+	require.NoError(t, err)
+	_, err = k8sAPI.DeletePool(&k8s.DeletePoolRequest{Region: scw.RegionFrPar, PoolID: expandID(drainedPoolID)})
+	require.NoError(t, err)
+
+	// A pod that cannot be evicted fails the replacement: the current pool is kept and the replacing one is deleted.
+	currentPoolID := state.ID
+	currentNodes := []string{state.Attributes["nodes.0.name"], state.Attributes["nodes.1.name"]}
+	kubeRequest(http.MethodPost, "/api/v1/namespaces/default/pods", map[string]interface{}{
+		"metadata": map[string]interface{}{"name": "db", "annotations": map[string]interface{}{fakeapi.KubeEvictionDeniedAnnotation: "true"}},
+		"spec":     map[string]interface{}{"nodeName": currentNodes[0]},
+	}, nil)
+	config["node_type"] = cty.StringVal("GP1-XS")
+	config["timeouts"] = cty.ObjectVal(map[string]cty.Value{"update": cty.StringVal("1s")})
+	diff, err = testPlanResource(t, resource, state, tt.Meta, config)
+	require.NoError(t, err)
+	failedState, diags := resource.Apply(ctx, state, diff, tt.Meta)
+	require.True(t, diags.HasError())
+	assert.Contains(t, diags[0].Summary, "failed to drain node "+currentNodes[0])
+	assert.Equal(t, currentPoolID, failedState.ID)
+	assert.Equal(t, "DEV1-XL", failedState.Attributes["node_type"])
+
+	pools, err = k8sAPI.ListPools(&k8s.ListPoolsRequest{Region: scw.RegionFrPar, ClusterID: cluster.ID}, scw.WithAllPages())
+	require.NoError(t, err)
+	require.Len(t, pools.Pools, 1)
+	assert.Equal(t, expandID(currentPoolID), pools.Pools[0].ID)
+	for _, name := range currentNodes {
+		kubeRequest(http.MethodGet, "/api/v1/nodes/"+name, nil, &node)
+		assert.False(t, node.Spec.Unschedulable, name)
+	}
+}
+
+func TestAccScalewayK8SCluster_PoolLabelsAndTaints(t *testing.T) {
+	tt := NewTestTools(t)
+	defer tt.Cleanup()
+
+	config := func(role string, taints string) string {
+		return fmt.Sprintf(`
+			data "scaleway_k8s_version" "latest" {
+				name = "latest"
+			}
+
+			resource "scaleway_k8s_cluster" "main" {
+				name                        = "tf-tests-k8s-pool-labels-taints"
+				version                     = data.scaleway_k8s_version.latest.name
+				cni                         = "cilium"
+				delete_additional_resources = true
+				tags                        = ["terraform-test", "scaleway_k8s_pool", "labels-taints"]
+			}
+
+			resource "scaleway_k8s_pool" "main" {
+				cluster_id = scaleway_k8s_cluster.main.id
+				name       = "labels-taints"
+				node_type  = "gp1_xs"
+				size       = 1
+				tags       = ["terraform-test", "scaleway_k8s_pool"]
+				labels = {
+					"example.com/role" = "%s"
+					"tier"             = "backend"
+				}
+				%s
+			}`, role, taints)
+	}
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: tt.ProviderFactories,
+		CheckDestroy:      testAccCheckScalewayK8SClusterDestroy(tt),
+		Steps: []resource.TestStep{
+			{
+				Config: config("web", `
+					taints {
+						key    = "dedicated"
+						value  = "web"
+						effect = "NoSchedule"
+					}`),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckScalewayK8SPoolExists(tt, "scaleway_k8s_pool.main"),
+					resource.TestCheckResourceAttr("scaleway_k8s_pool.main", "labels.%", "2"),
+					resource.TestCheckResourceAttr("scaleway_k8s_pool.main", "labels.example.com/role", "web"),
+					resource.TestCheckResourceAttr("scaleway_k8s_pool.main", "labels.tier", "backend"),
+					resource.TestCheckResourceAttr("scaleway_k8s_pool.main", "taints.#", "1"),
+					resource.TestCheckTypeSetElemNestedAttrs("scaleway_k8s_pool.main", "taints.*", map[string]string{
+						"key":    "dedicated",
+						"value":  "web",
+						"effect": "NoSchedule",
+					}),
+					resource.TestCheckResourceAttr("scaleway_k8s_pool.main", "tags.#", "2"),
+					testAccCheckScalewayK8SPoolTags(tt, "scaleway_k8s_pool.main", []string{
+						"terraform-test",
+						"scaleway_k8s_pool",
+						"noprefix=example.com/role=web",
+						"noprefix=tier=backend",
+						"taint=dedicated=web:NoSchedule",
+					}),
+				),
+			},
+			{
+				Config: config("api", ""),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("scaleway_k8s_pool.main", "labels.example.com/role", "api"),
+					resource.TestCheckResourceAttr("scaleway_k8s_pool.main", "taints.#", "0"),
+					resource.TestCheckResourceAttr("scaleway_k8s_pool.main", "tags.#", "2"),
+					testAccCheckScalewayK8SPoolTags(tt, "scaleway_k8s_pool.main", []string{
+						"terraform-test",
+						"scaleway_k8s_pool",
+						"noprefix=example.com/role=api",
+						"noprefix=tier=backend",
+					}),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckScalewayK8SPoolTags(tt *TestTools, n string, expected []string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("resource not found: %s", n)
+		}
+
+		k8sAPI, region, poolID, err := k8sAPIWithRegionAndID(tt.Meta, rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+
+		pool, err := k8sAPI.GetPool(&k8s.GetPoolRequest{
+			Region: region,
+			PoolID: poolID,
+		})
+		if err != nil {
+			return err
+		}
+
+		tags := append([]string(nil), pool.Tags...)
+		sort.Strings(tags)
+		expected = append([]string(nil), expected...)
+		sort.Strings(expected)
+		if !reflect.DeepEqual(tags, expected) {
+			return fmt.Errorf("pool %s has the tags %v, expected %v", n, pool.Tags, expected)
+		}
+
+		return nil
+	}
+}
+
+func TestFakeAPI_K8SPoolLabelsAndTaints(t *testing.T) {
+	tt := NewFakeAPITestTools(t)
+	defer tt.Cleanup()
+	ctx := context.Background()
+	k8sAPI := k8s.NewAPI(tt.Meta.scwClient)
+
+	cluster, err := k8sAPI.CreateCluster(&k8s.CreateClusterRequest{
+		Region:  scw.RegionFrPar,
+		Name:    "cluster",
+		Version: "1.26.2",
+		Cni:     k8s.CNICilium,
+	})
+	require.NoError(t, err)
+
+	resource := resourceScalewayK8SPool()
+	config := map[string]cty.Value{
+		"cluster_id": cty.StringVal(newRegionalIDString(scw.RegionFrPar, cluster.ID)),
+		"name":       cty.StringVal("web"),
+		"node_type":  cty.StringVal("DEV1-M"),
+		"size":       cty.NumberIntVal(1),
+		"tags":       cty.ListVal([]cty.Value{cty.StringVal("env=prod")}),
+		"labels":     cty.MapVal(map[string]cty.Value{"role": cty.StringVal("web")}),
+		"taints": cty.SetVal([]cty.Value{
+			cty.ObjectVal(map[string]cty.Value{"key": cty.StringVal("dedicated"), "value": cty.StringVal("web"), "effect": cty.StringVal("NoSchedule")}),
+			cty.ObjectVal(map[string]cty.Value{"key": cty.StringVal("gpu"), "value": cty.NullVal(cty.String), "effect": cty.StringVal("NoExecute")}),
+		}),
+	}
+	diff, err := testPlanResource(t, resource, nil, tt.Meta, config)
+	require.NoError(t, err)
+	state, diags := resource.Apply(ctx, nil, diff, tt.Meta)
+	require.False(t, diags.HasError(), diags)
+	assert.Equal(t, "1", state.Attributes["tags.#"])
+	assert.Equal(t, "1", state.Attributes["tags_all.#"])
+	assert.Equal(t, "web", state.Attributes["labels.role"])
+	assert.Equal(t, "2", state.Attributes["taints.#"])
+
+	pool, err := k8sAPI.GetPool(&k8s.GetPoolRequest{Region: scw.RegionFrPar, PoolID: expandID(state.ID)})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"env=prod", "noprefix=role=web", "taint=dedicated=web:NoSchedule", "taint=gpu:NoExecute"}, pool.Tags)
+
+	diff, err = testPlanResource(t, resource, state, tt.Meta, config)
+	require.NoError(t, err)
+	assert.Nil(t, diff)
+
+	// Labels and taints changed outside of Terraform show in plan.
+	_, err = k8sAPI.UpdatePool(&k8s.UpdatePoolRequest{
+		Region: scw.RegionFrPar,
+		PoolID: pool.ID,
+		Tags:   &[]string{"env=prod", "noprefix=role=api", "taint=dedicated=web:NoSchedule"},
+	})
+	require.NoError(t, err)
+	state, diags = resource.RefreshWithoutUpgrade(ctx, state, tt.Meta)
+	require.False(t, diags.HasError(), diags)
+	assert.Equal(t, "api", state.Attributes["labels.role"])
+	assert.Equal(t, "1", state.Attributes["taints.#"])
+	assert.Equal(t, "1", state.Attributes["tags.#"])
+
+	diff, err = testPlanResource(t, resource, state, tt.Meta, config)
+	require.NoError(t, err)
+	require.NotNil(t, diff)
+	assert.False(t, diff.RequiresNew())
+	assert.Equal(t, "web", diff.Attributes["labels.role"].New)
+	state, diags = resource.Apply(ctx, state, diff, tt.Meta)
+	require.False(t, diags.HasError(), diags)
+	pool, err = k8sAPI.GetPool(&k8s.GetPoolRequest{Region: scw.RegionFrPar, PoolID: pool.ID})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"env=prod", "noprefix=role=web", "taint=dedicated=web:NoSchedule", "taint=gpu:NoExecute"}, pool.Tags)
+
+	// Label and taint tags set in tags are kept there.
+	config["tags"] = cty.ListVal([]cty.Value{cty.StringVal("env=prod"), cty.StringVal("noprefix=team=a")})
+	diff, err = testPlanResource(t, resource, state, tt.Meta, config)
+	require.NoError(t, err)
+	state, diags = resource.Apply(ctx, state, diff, tt.Meta)
+	require.False(t, diags.HasError(), diags)
+	assert.Equal(t, "2", state.Attributes["tags.#"])
+	assert.Equal(t, "1", state.Attributes["labels.%"])
+	diff, err = testPlanResource(t, resource, state, tt.Meta, config)
+	require.NoError(t, err)
+	assert.Nil(t, diff)
+
+	// Labels Kapsule could not apply are rejected, rather than showing a diff on every plan.
+	for _, labels := range []map[string]cty.Value{
+		{"role=web": cty.StringVal("web")},
+		{"role": cty.StringVal("a=b")},
+		{"-role": cty.StringVal("web")},
+		{"example.com/role": cty.StringVal(strings.Repeat("a", 64))},
+	} {
+		config["labels"] = cty.MapVal(labels)
+		_, err = testPlanResource(t, resource, state, tt.Meta, config)
+		assert.ErrorContains(t, err, "label", labels)
+	}
+	config["labels"] = cty.MapVal(map[string]cty.Value{"example.com/role": cty.StringVal(""), "node.kubernetes.io/role_1": cty.StringVal("web.api-1")})
+	_, err = testPlanResource(t, resource, state, tt.Meta, config)
+	require.NoError(t, err)
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	lbSDK "github.com/scaleway/scaleway-sdk-go/api/lb/v1"
@@ -25,7 +26,7 @@ func resourceScalewayLb() *schema.Resource {
 		UpdateContext: resourceScalewayLbUpdate,
 		DeleteContext: resourceScalewayLbDelete,
 		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
+			StateContext: importStateByName(lbIDByName),
 		},
 		Timeouts: &schema.ResourceTimeout{
 			Create:  schema.DefaultTimeout(defaultLbLbTimeout),
@@ -38,7 +39,10 @@ func resourceScalewayLb() *schema.Resource {
 		StateUpgraders: []schema.StateUpgrader{
 			{Version: 0, Type: lbUpgradeV1SchemaType(), Upgrade: lbUpgradeV1SchemaUpgradeFunc},
 		},
-		CustomizeDiff: customizeDiffLocalityCheck("ip_id", "private_network.#.private_network_id"),
+		CustomizeDiff: customdiff.Sequence(
+			customizeDiffLocalityCheck("ip_id", "private_network.#.private_network_id"),
+			customizeDiffTagsAll,
+		),
 		Schema: map[string]*schema.Schema{
 			"name": {
 				Type:        schema.TypeString,
@@ -65,6 +69,7 @@ func resourceScalewayLb() *schema.Resource {
 				},
 				Description: "Array of tags to associate with the load-balancer",
 			},
+			"tags_all": tagsAllSchema(),
 			"ip_id": {
 				Type:             schema.TypeString,
 				Required:         true,
@@ -158,10 +163,8 @@ func resourceScalewayLbCreate(ctx context.Context, d *schema.ResourceData, meta
 		SslCompatibilityLevel: lbSDK.SSLCompatibilityLevel(*expandStringPtr(d.Get("ssl_compatibility_level"))),
 	}
 
-	if raw, ok := d.GetOk("tags"); ok {
-		for _, tag := range raw.([]interface{}) {
-			createReq.Tags = append(createReq.Tags, tag.(string))
-		}
+	if tags := expandTagsAll(d, meta); len(tags) > 0 {
+		createReq.Tags = tags
 	}
 	lb, err := lbAPI.CreateLB(createReq, scw.WithContext(ctx))
 	if err != nil {
@@ -225,7 +228,7 @@ func resourceScalewayLbRead(ctx context.Context, d *schema.ResourceData, meta in
 	_ = d.Set("region", region.String())
 	_ = d.Set("organization_id", lb.OrganizationID)
 	_ = d.Set("project_id", lb.ProjectID)
-	_ = d.Set("tags", lb.Tags)
+	setTags(d, meta, lb.Tags)
 	// For now API return lowercase lb type. This should be fixed in a near future on the API side
 	_ = d.Set("type", strings.ToUpper(lb.Type))
 	_ = d.Set("ip_id", newZonedIDString(zone, lb.IP[0].ID))
@@ -259,9 +262,9 @@ func resourceScalewayLbUpdate(ctx context.Context, d *schema.ResourceData, meta
 
 	hasChanged := false
 
-	if d.HasChanges("name", "tags") {
+	if d.HasChanges("name", "tags", "tags_all") {
 		req.Name = d.Get("name").(string)
-		req.Tags = expandStrings(d.Get("tags"))
+		req.Tags = expandTagsAll(d, meta)
 		hasChanged = true
 	}
 
@@ -74,6 +74,7 @@ func resourceScalewayObject() *schema.Resource {
 					Type: schema.TypeString,
 				},
 			},
+			"tags_all": tagsAllMapSchema(),
 			"visibility": {
 				Optional:    true,
 				Type:        schema.TypeString,
@@ -87,6 +88,7 @@ func resourceScalewayObject() *schema.Resource {
 			"region":     regionSchema(),
 			"project_id": projectIDSchema(),
 		},
+		CustomizeDiff: customizeDiffMapTagsAll,
 	}
 }
 
@@ -125,12 +127,12 @@ func resourceScalewayObjectCreate(ctx context.Context, d *schema.ResourceData, m
 		return diag.FromErr(err)
 	}
 
-	if rawTags, hasTags := d.GetOk("tags"); hasTags {
+	if tagsSet := expandObjectBucketTagsAll(d, meta); len(tagsSet) > 0 {
 		_, err := s3Client.PutObjectTaggingWithContext(ctx, &s3.PutObjectTaggingInput{
 			Bucket: expandStringPtr(bucket),
 			Key:    expandStringPtr(key),
 			Tagging: &s3.Tagging{
-				TagSet: expandObjectBucketTags(rawTags),
+				TagSet: tagsSet,
 			},
 		})
 		if err != nil {
@@ -195,12 +197,12 @@ func resourceScalewayObjectUpdate(ctx context.Context, d *schema.ResourceData, m
 		}
 	}
 
-	if d.HasChange("tags") {
+	if d.HasChanges("tags", "tags_all") {
 		_, err := s3Client.PutObjectTaggingWithContext(ctx, &s3.PutObjectTaggingInput{
 			Bucket: expandStringPtr(d.Get("bucket")),
 			Key:    expandStringPtr(key),
 			Tagging: &s3.Tagging{
-				TagSet: expandObjectBucketTags(d.Get("tags")),
+				TagSet: expandObjectBucketTagsAll(d, meta),
 			},
 		})
 		if err != nil {
@@ -250,7 +252,7 @@ func resourceScalewayObjectRead(ctx context.Context, d *schema.ResourceData, met
 		return diag.FromErr(err)
 	}
 
-	_ = d.Set("tags", flattenObjectBucketTags(tags.TagSet))
+	setMapTags(d, meta, expandTagsMap(flattenObjectBucketTags(tags.TagSet)))
 
 	acl, err := s3Client.GetObjectAclWithContext(ctx, &s3.GetObjectAclInput{
 		Bucket: expandStringPtr(bucket),
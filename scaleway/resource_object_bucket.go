@@ -11,6 +11,7 @@ import (
 	"github.com/hashicorp/aws-sdk-go-base/tfawserr"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
@@ -64,6 +65,7 @@ func resourceScalewayObjectBucket() *schema.Resource {
 				Optional:    true,
 				Description: "The tags associated with this bucket",
 			},
+			"tags_all": tagsAllMapSchema(),
 			"endpoint": {
 				Type:        schema.TypeString,
 				Description: "Endpoint of the bucket",
@@ -204,15 +206,18 @@ func resourceScalewayObjectBucket() *schema.Resource {
 				},
 			},
 		},
-		CustomizeDiff: func(ctx context.Context, diff *schema.ResourceDiff, meta interface{}) error {
-			if diff.Get("object_lock_enabled").(bool) {
-				if diff.HasChange("versioning") && !diff.Get("versioning.0.enabled").(bool) {
-					return fmt.Errorf("versioning must be enabled when object lock is enabled")
+		CustomizeDiff: customdiff.Sequence(
+			func(ctx context.Context, diff *schema.ResourceDiff, meta interface{}) error {
+				if diff.Get("object_lock_enabled").(bool) {
+					if diff.HasChange("versioning") && !diff.Get("versioning.0.enabled").(bool) {
+						return fmt.Errorf("versioning must be enabled when object lock is enabled")
+					}
 				}
-			}
 
-			return nil
-		},
+				return nil
+			},
+			customizeDiffMapTagsAll,
+		),
 	}
 }
 
@@ -239,7 +244,7 @@ func resourceScalewayObjectBucketCreate(ctx context.Context, d *schema.ResourceD
 		return diag.FromErr(err)
 	}
 
-	tagsSet := expandObjectBucketTags(d.Get("tags"))
+	tagsSet := expandObjectBucketTagsAll(d, meta)
 
 	if len(tagsSet) > 0 {
 		_, err = s3Client.PutBucketTaggingWithContext(ctx, &s3.PutBucketTaggingInput{
@@ -285,8 +290,8 @@ func resourceScalewayObjectBucketUpdate(ctx context.Context, d *schema.ResourceD
 		}
 	}
 
-	if d.HasChange("tags") {
-		tagsSet := expandObjectBucketTags(d.Get("tags"))
+	if d.HasChanges("tags", "tags_all") {
+		tagsSet := expandObjectBucketTagsAll(d, meta)
 
 		if len(tagsSet) > 0 {
 			_, err = s3Client.PutBucketTaggingWithContext(ctx, &s3.PutBucketTaggingInput{
@@ -513,7 +518,7 @@ func resourceScalewayObjectBucketRead(ctx context.Context, d *schema.ResourceDat
 		tagsSet = tagsResponse.TagSet
 	}
 
-	_ = d.Set("tags", flattenObjectBucketTags(tagsSet))
+	setMapTags(d, meta, expandTagsMap(flattenObjectBucketTags(tagsSet)))
 
 	_ = d.Set("endpoint", objectBucketEndpointURL(bucketName, region))
 
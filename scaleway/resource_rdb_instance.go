@@ -6,6 +6,7 @@ import (
 	"io/ioutil"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/scaleway/scaleway-sdk-go/api/rdb/v1"
@@ -26,7 +27,7 @@ func resourceScalewayRdbInstance() *schema.Resource {
 			Default: schema.DefaultTimeout(defaultRdbInstanceTimeout),
 		},
 		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
+			StateContext: importStateByName(rdbInstanceIDByName),
 		},
 		SchemaVersion: 0,
 		Schema: map[string]*schema.Schema{
@@ -116,6 +117,7 @@ func resourceScalewayRdbInstance() *schema.Resource {
 				Optional:    true,
 				Description: "List of tags [\"tag1\", \"tag2\", ...] attached to a database instance",
 			},
+			"tags_all": tagsAllSchema(),
 			"volume_type": {
 				Type:     schema.TypeString,
 				Default:  rdb.VolumeTypeLssd,
@@ -276,7 +278,10 @@ func resourceScalewayRdbInstance() *schema.Resource {
 			"organization_id": organizationIDSchema(),
 			"project_id":      projectIDSchema(),
 		},
-		CustomizeDiff: customizeDiffLocalityCheck("private_network.#.pn_id"),
+		CustomizeDiff: customdiff.Sequence(
+			customizeDiffLocalityCheck("private_network.#.pn_id"),
+			customizeDiffTagsAll,
+		),
 	}
 }
 
@@ -303,9 +308,8 @@ func resourceScalewayRdbInstanceCreate(ctx context.Context, d *schema.ResourceDa
 		createReq.InitSettings = expandInstanceSettings(initSettings)
 	}
 
-	rawTag, tagExist := d.GetOk("tags")
-	if tagExist {
-		createReq.Tags = expandStrings(rawTag)
+	if tags := expandTagsAll(d, meta); len(tags) > 0 {
+		createReq.Tags = tags
 	}
 
 	pn, pnExist := d.GetOk("private_network")
@@ -407,7 +411,7 @@ func resourceScalewayRdbInstanceRead(ctx context.Context, d *schema.ResourceData
 	_ = d.Set("user_name", d.Get("user_name").(string)) // user name and
 	_ = d.Set("password", d.Get("password").(string))   // password are immutable
 	if len(res.Tags) > 0 {
-		_ = d.Set("tags", flattenSliceString(res.Tags))
+		setTags(d, meta, res.Tags)
 	}
 	if res.Endpoint != nil {
 		_ = d.Set("endpoint_ip", flattenIPPtr(res.Endpoint.IP))
@@ -480,8 +484,8 @@ func resourceScalewayRdbInstanceUpdate(ctx context.Context, d *schema.ResourceDa
 	if d.HasChange("backup_same_region") {
 		req.BackupSameRegion = expandBoolPtr(d.Get("backup_same_region"))
 	}
-	if d.HasChange("tags") {
-		req.Tags = expandUpdatedStringsPtr(d.Get("tags"))
+	if d.HasChanges("tags", "tags_all") {
+		req.Tags = expandUpdatedTagsAll(d, meta)
 	}
 
 	_, err = waitForRDBInstance(ctx, rdbAPI, region, ID, d.Timeout(schema.TimeoutUpdate))
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/scaleway/scaleway-sdk-go/api/redis/v1"
@@ -66,6 +67,7 @@ func resourceScalewayRedisCluster() *schema.Resource {
 				},
 				Description: "List of tags [\"tag1\", \"tag2\", ...] attached to a redis cluster",
 			},
+			"tags_all": tagsAllSchema(),
 			"cluster_size": {
 				Type:        schema.TypeInt,
 				Optional:    true,
@@ -191,7 +193,10 @@ func resourceScalewayRedisCluster() *schema.Resource {
 			"zone":       zoneSchema(),
 			"project_id": projectIDSchema(),
 		},
-		CustomizeDiff: customizeDiffLocalityCheck("private_network.#.id"),
+		CustomizeDiff: customdiff.Sequence(
+			customizeDiffLocalityCheck("private_network.#.id"),
+			customizeDiffTagsAll,
+		),
 	}
 }
 
@@ -211,9 +216,8 @@ func resourceScalewayRedisClusterCreate(ctx context.Context, d *schema.ResourceD
 		Password:  d.Get("password").(string),
 	}
 
-	tags, tagsExist := d.GetOk("tags")
-	if tagsExist {
-		createReq.Tags = expandStrings(tags)
+	if tags := expandTagsAll(d, meta); len(tags) > 0 {
+		createReq.Tags = tags
 	}
 	clusterSize, clusterSizeExist := d.GetOk("cluster_size")
 	if clusterSizeExist {
@@ -287,13 +291,14 @@ func resourceScalewayRedisClusterRead(ctx context.Context, d *schema.ResourceDat
 	_ = d.Set("project_id", cluster.ProjectID)
 	_ = d.Set("version", cluster.Version)
 	_ = d.Set("cluster_size", int(cluster.ClusterSize))
+	_ = d.Set("tls_enabled", cluster.TLSEnabled)
 	_ = d.Set("created_at", cluster.CreatedAt.Format(time.RFC3339))
 	_ = d.Set("updated_at", cluster.UpdatedAt.Format(time.RFC3339))
 	_ = d.Set("acl", flattenRedisACLs(cluster.ACLRules))
 	_ = d.Set("settings", flattenRedisSettings(cluster.ClusterSettings))
 
 	if len(cluster.Tags) > 0 {
-		_ = d.Set("tags", cluster.Tags)
+		setTags(d, meta, cluster.Tags)
 	}
 
 	// set endpoints
@@ -345,8 +350,8 @@ func resourceScalewayRedisClusterUpdate(ctx context.Context, d *schema.ResourceD
 	if d.HasChange("password") {
 		req.Password = expandStringPtr(d.Get("password"))
 	}
-	if d.HasChange("tags") {
-		req.Tags = expandUpdatedStringsPtr(d.Get("tags"))
+	if d.HasChanges("tags", "tags_all") {
+		req.Tags = expandUpdatedTagsAll(d, meta)
 	}
 	if d.HasChange("acl") {
 		diagnostics := resourceScalewayRedisClusterUpdateACL(ctx, d, redisAPI, zone, ID)
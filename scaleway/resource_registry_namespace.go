@@ -16,7 +16,7 @@ func resourceScalewayRegistryNamespace() *schema.Resource {
 		UpdateContext: resourceScalewayRegistryNamespaceUpdate,
 		DeleteContext: resourceScalewayRegistryNamespaceDelete,
 		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
+			StateContext: importStateByName(registryNamespaceIDByName),
 		},
 		Timeouts: &schema.ResourceTimeout{
 			Create:  schema.DefaultTimeout(defaultRedisClusterTimeout),
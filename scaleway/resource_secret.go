@@ -41,6 +41,7 @@ func resourceScalewaySecret() *schema.Resource {
 				Optional:    true,
 				Description: "List of tags [\"tag1\", \"tag2\", ...] associated to secret",
 			},
+			"tags_all": tagsAllSchema(),
 			"status": {
 				Type:        schema.TypeString,
 				Computed:    true,
@@ -64,6 +65,7 @@ func resourceScalewaySecret() *schema.Resource {
 			"region":     regionSchema(),
 			"project_id": projectIDSchema(),
 		},
+		CustomizeDiff: customizeDiffTagsAll,
 	}
 }
 
@@ -79,9 +81,8 @@ func resourceScalewaySecretCreate(ctx context.Context, d *schema.ResourceData, m
 		Name:      d.Get("name").(string),
 	}
 
-	rawTag, tagExist := d.GetOk("tags")
-	if tagExist {
-		secretCreateRequest.Tags = expandStrings(rawTag)
+	if tags := expandTagsAll(d, meta); len(tags) > 0 {
+		secretCreateRequest.Tags = tags
 	}
 
 	rawDescription, descriptionExist := d.GetOk("description")
@@ -118,7 +119,7 @@ func resourceScalewaySecretRead(ctx context.Context, d *schema.ResourceData, met
 	}
 
 	if len(secretResponse.Tags) > 0 {
-		_ = d.Set("tags", flattenSliceString(secretResponse.Tags))
+		setTags(d, meta, secretResponse.Tags)
 	}
 
 	_ = d.Set("name", secretResponse.Name)
@@ -156,8 +157,8 @@ func resourceScalewaySecretUpdate(ctx context.Context, d *schema.ResourceData, m
 		hasChanged = true
 	}
 
-	if d.HasChange("tags") {
-		updateRequest.Tags = expandUpdatedStringsPtr(d.Get("tags"))
+	if d.HasChanges("tags", "tags_all") {
+		updateRequest.Tags = expandUpdatedTagsAll(d, meta)
 		hasChanged = true
 	}
 
@@ -17,7 +17,7 @@ func resourceScalewayVPCPrivateNetwork() *schema.Resource {
 		UpdateContext: resourceScalewayVPCPrivateNetworkUpdate,
 		DeleteContext: resourceScalewayVPCPrivateNetworkDelete,
 		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
+			StateContext: importStateByName(vpcPrivateNetworkIDByName),
 		},
 		SchemaVersion: 0,
 		Schema: map[string]*schema.Schema{
@@ -35,6 +35,7 @@ func resourceScalewayVPCPrivateNetwork() *schema.Resource {
 					Type: schema.TypeString,
 				},
 			},
+			"tags_all":   tagsAllSchema(),
 			"project_id": projectIDSchema(),
 			"zone":       zoneSchema(),
 			// Computed elements
@@ -50,6 +51,7 @@ func resourceScalewayVPCPrivateNetwork() *schema.Resource {
 				Description: "The date and time of the last update of the private network",
 			},
 		},
+		CustomizeDiff: customizeDiffTagsAll,
 	}
 }
 
@@ -61,7 +63,7 @@ func resourceScalewayVPCPrivateNetworkCreate(ctx context.Context, d *schema.Reso
 
 	pn, err := vpcAPI.CreatePrivateNetwork(&vpc.CreatePrivateNetworkRequest{
 		Name:      expandOrGenerateString(d.Get("name"), "pn"),
-		Tags:      expandStrings(d.Get("tags")),
+		Tags:      expandTagsAll(d, meta),
 		ProjectID: d.Get("project_id").(string),
 		Zone:      zone,
 	}, scw.WithContext(ctx))
@@ -98,7 +100,7 @@ func resourceScalewayVPCPrivateNetworkRead(ctx context.Context, d *schema.Resour
 	_ = d.Set("created_at", pn.CreatedAt.Format(time.RFC3339))
 	_ = d.Set("updated_at", pn.UpdatedAt.Format(time.RFC3339))
 	_ = d.Set("zone", zone)
-	_ = d.Set("tags", pn.Tags)
+	setTags(d, meta, pn.Tags)
 
 	return nil
 }
@@ -109,12 +111,12 @@ func resourceScalewayVPCPrivateNetworkUpdate(ctx context.Context, d *schema.Reso
 		return diag.FromErr(err)
 	}
 
-	if d.HasChanges("name", "tags") {
+	if d.HasChanges("name", "tags", "tags_all") {
 		updateRequest := &vpc.UpdatePrivateNetworkRequest{
 			PrivateNetworkID: ID,
 			Zone:             zone,
 			Name:             scw.StringPtr(d.Get("name").(string)),
-			Tags:             expandUpdatedStringsPtr(d.Get("tags")),
+			Tags:             expandUpdatedTagsAll(d, meta),
 		}
 
 		_, err = vpcAPI.UpdatePrivateNetwork(updateRequest, scw.WithContext(ctx))
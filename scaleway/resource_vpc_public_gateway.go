@@ -63,6 +63,7 @@ func resourceScalewayVPCPublicGateway() *schema.Resource {
 					Type: schema.TypeString,
 				},
 			},
+			"tags_all": tagsAllSchema(),
 			"bastion_enabled": {
 				Type:        schema.TypeBool,
 				Description: "Enable SSH bastion on the gateway",
@@ -95,6 +96,7 @@ func resourceScalewayVPCPublicGateway() *schema.Resource {
 				Description: "The date and time of the last update of the public gateway",
 			},
 		},
+		CustomizeDiff: customizeDiffTagsAll,
 	}
 }
 
@@ -107,7 +109,7 @@ func resourceScalewayVPCPublicGatewayCreate(ctx context.Context, d *schema.Resou
 	req := &vpcgw.CreateGatewayRequest{
 		Name:               expandOrGenerateString(d.Get("name"), "pn"),
 		Type:               d.Get("type").(string),
-		Tags:               expandStrings(d.Get("tags")),
+		Tags:               expandTagsAll(d, meta),
 		UpstreamDNSServers: expandStrings(d.Get("upstream_dns_servers")),
 		ProjectID:          d.Get("project_id").(string),
 		EnableBastion:      d.Get("bastion_enabled").(bool),
@@ -155,12 +157,15 @@ func resourceScalewayVPCPublicGatewayRead(ctx context.Context, d *schema.Resourc
 	}
 
 	_ = d.Set("name", gateway.Name)
+	if gateway.Type != nil {
+		_ = d.Set("type", gateway.Type.Name)
+	}
 	_ = d.Set("organization_id", gateway.OrganizationID)
 	_ = d.Set("project_id", gateway.ProjectID)
 	_ = d.Set("created_at", gateway.CreatedAt.Format(time.RFC3339))
 	_ = d.Set("updated_at", gateway.UpdatedAt.Format(time.RFC3339))
 	_ = d.Set("zone", gateway.Zone)
-	_ = d.Set("tags", gateway.Tags)
+	setTags(d, meta, gateway.Tags)
 	_ = d.Set("upstream_dns_servers", gateway.UpstreamDNSServers)
 	_ = d.Set("ip_id", newZonedID(gateway.Zone, gateway.IP.ID).String())
 	_ = d.Set("bastion_enabled", gateway.BastionEnabled)
@@ -190,8 +195,8 @@ func resourceScalewayVPCPublicGatewayUpdate(ctx context.Context, d *schema.Resou
 		updateRequest.Name = scw.StringPtr(d.Get("name").(string))
 	}
 
-	if d.HasChange("tags") {
-		updateRequest.Tags = expandUpdatedStringsPtr(d.Get("tags"))
+	if d.HasChanges("tags", "tags_all") {
+		updateRequest.Tags = expandUpdatedTagsAll(d, meta)
 	}
 
 	if d.HasChange("bastion_port") {
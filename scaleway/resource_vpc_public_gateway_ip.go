@@ -34,6 +34,7 @@ func resourceScalewayVPCPublicGatewayIP() *schema.Resource {
 					Type: schema.TypeString,
 				},
 			},
+			"tags_all":   tagsAllSchema(),
 			"project_id": projectIDSchema(),
 			"zone":       zoneSchema(),
 			// Computed elements
@@ -55,6 +56,7 @@ func resourceScalewayVPCPublicGatewayIP() *schema.Resource {
 				Description: "The date and time of the last update of the public gateway IP",
 			},
 		},
+		CustomizeDiff: customizeDiffTagsAll,
 	}
 }
 
@@ -65,7 +67,7 @@ func resourceScalewayVPCPublicGatewayIPCreate(ctx context.Context, d *schema.Res
 	}
 
 	req := &vpcgw.CreateIPRequest{
-		Tags:      expandStrings(d.Get("tags")),
+		Tags:      expandTagsAll(d, meta),
 		ProjectID: d.Get("project_id").(string),
 		Zone:      zone,
 	}
@@ -82,7 +84,7 @@ func resourceScalewayVPCPublicGatewayIPCreate(ctx context.Context, d *schema.Res
 		updateRequest := &vpcgw.UpdateIPRequest{
 			IPID:    res.ID,
 			Zone:    zone,
-			Tags:    scw.StringsPtr(expandStrings(d.Get("tags"))),
+			Tags:    scw.StringsPtr(expandTagsAll(d, meta)),
 			Reverse: expandStringPtr(reverse.(string)),
 		}
 		_, err = vpcgwAPI.UpdateIP(updateRequest, scw.WithContext(ctx))
@@ -118,7 +120,7 @@ func resourceScalewayVPCPublicGatewayIPRead(ctx context.Context, d *schema.Resou
 	_ = d.Set("created_at", ip.CreatedAt.Format(time.RFC3339))
 	_ = d.Set("updated_at", ip.UpdatedAt.Format(time.RFC3339))
 	_ = d.Set("zone", zone)
-	_ = d.Set("tags", ip.Tags)
+	setTags(d, meta, ip.Tags)
 	_ = d.Set("reverse", ip.Reverse)
 
 	return nil
@@ -130,11 +132,11 @@ func resourceScalewayVPCPublicGatewayIPUpdate(ctx context.Context, d *schema.Res
 		return diag.FromErr(err)
 	}
 
-	if d.HasChanges("tags", "reverse") {
+	if d.HasChanges("tags", "tags_all", "reverse") {
 		updateRequest := &vpcgw.UpdateIPRequest{
 			IPID:    ID,
 			Zone:    zone,
-			Tags:    scw.StringsPtr(expandStrings(d.Get("tags"))),
+			Tags:    scw.StringsPtr(expandTagsAll(d, meta)),
 			Reverse: expandStringPtr(d.Get("reverse").(string)),
 		}
 
@@ -3,20 +3,202 @@ package scaleway
 import (
 	"bytes"
 	"context"
+	"fmt"
 	"io"
 	"io/ioutil"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/hashicorp/go-retryablehttp"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 type retryableTransportOptions struct {
 	RetryMax     *int
 	RetryWaitMax *time.Duration
 	RetryWaitMin *time.Duration
+	// RetryableStatusCodes overrides the HTTP status codes that are retried.
+	// Requests failing without a response are always retried.
+	RetryableStatusCodes []int
 }
 
+// retrySchema returns the provider level retry block.
+func retrySchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Optional:    true,
+		MaxItems:    1,
+		Description: "Configuration of the retries performed on failed API requests.",
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"max_attempts": {
+					Type:         schema.TypeInt,
+					Optional:     true,
+					Description:  "Maximum number of attempts for a request, including the first one.",
+					ValidateFunc: validation.IntAtLeast(1),
+				},
+				"min_wait": {
+					Type:         schema.TypeString,
+					Optional:     true,
+					Description:  "Minimum time to wait between two attempts.",
+					ValidateFunc: validateDuration(),
+				},
+				"max_wait": {
+					Type:         schema.TypeString,
+					Optional:     true,
+					Description:  "Maximum time to wait between two attempts, also caps the Retry-After header.",
+					ValidateFunc: validateDuration(),
+				},
+				"retryable_status_codes": {
+					Type:        schema.TypeList,
+					Optional:    true,
+					Description: "HTTP status codes that should be retried.",
+					Elem: &schema.Schema{
+						Type:         schema.TypeInt,
+						ValidateFunc: validation.IntBetween(400, 599),
+					},
+				},
+			},
+		},
+	}
+}
+
+func expandRetryableTransportOptions(d *schema.ResourceData) (retryableTransportOptions, error) {
+	options := retryableTransportOptions{}
+	if d == nil {
+		return options, nil
+	}
+
+	if maxAttempts, ok := d.GetOk("retry.0.max_attempts"); ok {
+		retryMax := maxAttempts.(int) - 1
+		options.RetryMax = &retryMax
+	}
+	minWait, err := expandDuration(d.Get("retry.0.min_wait"))
+	if err != nil {
+		return options, err
+	}
+	options.RetryWaitMin = minWait
+	maxWait, err := expandDuration(d.Get("retry.0.max_wait"))
+	if err != nil {
+		return options, err
+	}
+	options.RetryWaitMax = maxWait
+	if rawStatusCodes, ok := d.GetOk("retry.0.retryable_status_codes"); ok {
+		for _, statusCode := range rawStatusCodes.([]interface{}) {
+			options.RetryableStatusCodes = append(options.RetryableStatusCodes, statusCode.(int))
+		}
+	}
+
+	if options.RetryWaitMin != nil && options.RetryWaitMax != nil && *options.RetryWaitMin > *options.RetryWaitMax {
+		return options, fmt.Errorf("retry min_wait (%s) must be lower than max_wait (%s)", options.RetryWaitMin, options.RetryWaitMax)
+	}
+
+	return options, nil
+}
+
+// retryAfterDuration parses the Retry-After header that can either be a number of seconds or an HTTP date.
+func retryAfterDuration(resp *http.Response) (time.Duration, bool) {
+	if resp == nil {
+		return 0, false
+	}
+	retryAfter := resp.Header.Get("Retry-After")
+	if retryAfter == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.ParseInt(retryAfter, 10, 64); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(retryAfter); err == nil {
+		wait := time.Until(date)
+		if wait < 0 {
+			wait = 0
+		}
+		return wait, true
+	}
+	return 0, false
+}
+
+// retryableTransportBackoff waits for the duration requested by the Retry-After header on throttled requests
+// and falls back on an exponential backoff. The wait is always capped by max.
+func retryableTransportBackoff(min, max time.Duration, attemptNum int, resp *http.Response) time.Duration {
+	wait := retryablehttp.DefaultBackoff(min, max, attemptNum, nil)
+	if resp != nil && (resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable) {
+		if retryAfter, ok := retryAfterDuration(resp); ok {
+			wait = retryAfter
+			if wait > max {
+				wait = max
+			}
+		}
+	}
+
+	if resp != nil && resp.Request != nil {
+		tflog.Debug(resp.Request.Context(), "waiting before retrying request", map[string]interface{}{
+			"method":      resp.Request.Method,
+			"api_path":    resp.Request.URL.Path,
+			"status_code": resp.StatusCode,
+			"attempt":     attemptNum + 1,
+			"wait":        wait.String(),
+		})
+	}
+
+	return wait
+}
+
+// retryableTransportCheckRetry retries requests without response, throttled requests and server errors.
+// If statusCodes is not nil, only responses with one of those status codes are retried.
+func retryableTransportCheckRetry(statusCodes []int) retryablehttp.CheckRetry {
+	return func(ctx context.Context, resp *http.Response, err error) (bool, error) {
+		if resp == nil {
+			tflog.Debug(ctx, "retrying request without response", map[string]interface{}{
+				"error": fmt.Sprint(err),
+			})
+			return true, err
+		}
+
+		var shouldRetry bool
+		var checkErr error
+		switch {
+		case statusCodes != nil:
+			shouldRetry = isRetryableStatusCode(statusCodes, resp.StatusCode)
+		case resp.StatusCode == http.StatusTooManyRequests:
+			shouldRetry = true
+		default:
+			shouldRetry, checkErr = retryablehttp.DefaultRetryPolicy(ctx, resp, err)
+		}
+
+		if resp.StatusCode >= http.StatusBadRequest {
+			fields := map[string]interface{}{
+				"status_code": resp.StatusCode,
+				"retry":       shouldRetry,
+			}
+			if resp.Request != nil {
+				fields["method"] = resp.Request.Method
+				fields["api_path"] = resp.Request.URL.Path
+			}
+			tflog.Debug(ctx, "retry decision for failed request", fields)
+		}
+
+		return shouldRetry, checkErr
+	}
+}
+
+func isRetryableStatusCode(statusCodes []int, statusCode int) bool {
+	for _, code := range statusCodes {
+		if code == statusCode {
+			return true
+		}
+	}
+	return false
+}
+
+// TODO Retry logic should be moved in the SDK
+// newRetryableTransportWithOptions creates a http transport with retry capability.
 func newRetryableTransportWithOptions(defaultTransport http.RoundTripper, options retryableTransportOptions) http.RoundTripper {
 	c := retryablehttp.NewClient()
 	c.HTTPClient = &http.Client{Transport: defaultTransport}
@@ -26,12 +208,8 @@ func newRetryableTransportWithOptions(defaultTransport http.RoundTripper, option
 	c.RetryWaitMax = 2 * time.Minute
 	c.Logger = l
 	c.RetryWaitMin = time.Second * 2
-	c.CheckRetry = func(ctx context.Context, resp *http.Response, err error) (bool, error) {
-		if resp == nil || resp.StatusCode == http.StatusTooManyRequests {
-			return true, err
-		}
-		return retryablehttp.DefaultRetryPolicy(ctx, resp, err)
-	}
+	c.Backoff = retryableTransportBackoff
+	c.CheckRetry = retryableTransportCheckRetry(options.RetryableStatusCodes)
 
 	// If ErrorHandler is not set, retryablehttp will wrap http errors
 	c.ErrorHandler = func(resp *http.Response, err error, numTries int) (*http.Response, error) {
@@ -57,12 +235,6 @@ func newRetryableTransportWithOptions(defaultTransport http.RoundTripper, option
 	return &retryableTransport{c}
 }
 
-// TODO Retry logic should be moved in the SDK
-// newRetryableTransport creates a http transport with retry capability.
-func newRetryableTransport(defaultTransport http.RoundTripper) http.RoundTripper {
-	return newRetryableTransportWithOptions(defaultTransport, retryableTransportOptions{})
-}
-
 // client is a bridge between scw.httpClient interface and retryablehttp.Client
 type retryableTransport struct {
 	*retryablehttp.Client
@@ -78,7 +250,7 @@ func (c *retryableTransport) RoundTrip(r *http.Request) (*http.Response, error)
 		}
 		body = bytes.NewReader(bs)
 	}
-	req, err := retryablehttp.NewRequest(r.Method, r.URL.String(), body)
+	req, err := retryablehttp.NewRequestWithContext(r.Context(), r.Method, r.URL.String(), body)
 	if err != nil {
 		return nil, err
 	}
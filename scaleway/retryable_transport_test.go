@@ -0,0 +1,56 @@
+package scaleway
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryableTransportBackoff(t *testing.T) {
+	throttled := func(retryAfter string) *http.Response {
+		resp := &http.Response{
+			StatusCode: http.StatusTooManyRequests,
+			Header:     http.Header{},
+		}
+		if retryAfter != "" {
+			resp.Header.Set("Retry-After", retryAfter)
+		}
+		return resp
+	}
+
+	assert.Equal(t, 10*time.Second, retryableTransportBackoff(time.Second, time.Minute, 0, throttled("10")))
+	assert.Equal(t, time.Minute, retryableTransportBackoff(time.Second, time.Minute, 0, throttled("3600")))
+	assert.Equal(t, 4*time.Second, retryableTransportBackoff(time.Second, time.Minute, 2, throttled("")))
+	assert.Equal(t, time.Duration(0), retryableTransportBackoff(time.Second, time.Minute, 0, throttled(time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat))))
+	assert.Equal(t, time.Duration(0), retryableTransportBackoff(time.Second, 0, 0, throttled("10")))
+}
+
+func TestRetryableTransportCheckRetry(t *testing.T) {
+	ctx := context.Background()
+	response := func(statusCode int) *http.Response {
+		return &http.Response{StatusCode: statusCode}
+	}
+
+	defaultCheckRetry := retryableTransportCheckRetry(nil)
+	for statusCode, expected := range map[int]bool{
+		http.StatusOK:                  false,
+		http.StatusNotFound:            false,
+		http.StatusTooManyRequests:     true,
+		http.StatusServiceUnavailable:  true,
+		http.StatusInternalServerError: true,
+	} {
+		retry, _ := defaultCheckRetry(ctx, response(statusCode), nil)
+		assert.Equal(t, expected, retry, "status code %d", statusCode)
+	}
+
+	customCheckRetry := retryableTransportCheckRetry([]int{http.StatusConflict})
+	retry, _ := customCheckRetry(ctx, response(http.StatusConflict), nil)
+	assert.True(t, retry)
+	retry, _ = customCheckRetry(ctx, response(http.StatusInternalServerError), nil)
+	assert.False(t, retry)
+	retry, _ = customCheckRetry(ctx, nil, nil)
+	assert.True(t, retry)
+}